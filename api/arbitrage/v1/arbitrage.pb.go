@@ -0,0 +1,1604 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: arbitrage/v1/arbitrage.proto
+
+package arbitragev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EventType int32
+
+const (
+	EventType_EVENT_TYPE_UNSPECIFIED EventType = 0
+	// Sent once per new block, before it is evaluated.
+	EventType_EVENT_TYPE_HEARTBEAT EventType = 1
+	// The most profitable trade found in a block.
+	EventType_EVENT_TYPE_OPPORTUNITY EventType = 2
+	// A profitable trade that was not profitable in the previous block.
+	EventType_EVENT_TYPE_OPPORTUNITY_OPENED EventType = 3
+	// An open opportunity whose profit changed materially.
+	EventType_EVENT_TYPE_OPPORTUNITY_UPDATED EventType = 4
+	// An open opportunity no longer profitable.
+	EventType_EVENT_TYPE_OPPORTUNITY_CLOSED EventType = 5
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "EVENT_TYPE_UNSPECIFIED",
+		1: "EVENT_TYPE_HEARTBEAT",
+		2: "EVENT_TYPE_OPPORTUNITY",
+		3: "EVENT_TYPE_OPPORTUNITY_OPENED",
+		4: "EVENT_TYPE_OPPORTUNITY_UPDATED",
+		5: "EVENT_TYPE_OPPORTUNITY_CLOSED",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_TYPE_UNSPECIFIED":         0,
+		"EVENT_TYPE_HEARTBEAT":           1,
+		"EVENT_TYPE_OPPORTUNITY":         2,
+		"EVENT_TYPE_OPPORTUNITY_OPENED":  3,
+		"EVENT_TYPE_OPPORTUNITY_UPDATED": 4,
+		"EVENT_TYPE_OPPORTUNITY_CLOSED":  5,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_arbitrage_v1_arbitrage_proto_enumTypes[0].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_arbitrage_v1_arbitrage_proto_enumTypes[0]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{0}
+}
+
+type Direction int32
+
+const (
+	Direction_DIRECTION_UNSPECIFIED Direction = 0
+	// Buy on the exchange, sell on the pool.
+	Direction_DIRECTION_CEX_TO_DEX Direction = 1
+	// Buy on the pool, sell on the exchange.
+	Direction_DIRECTION_DEX_TO_CEX Direction = 2
+)
+
+// Enum value maps for Direction.
+var (
+	Direction_name = map[int32]string{
+		0: "DIRECTION_UNSPECIFIED",
+		1: "DIRECTION_CEX_TO_DEX",
+		2: "DIRECTION_DEX_TO_CEX",
+	}
+	Direction_value = map[string]int32{
+		"DIRECTION_UNSPECIFIED": 0,
+		"DIRECTION_CEX_TO_DEX":  1,
+		"DIRECTION_DEX_TO_CEX":  2,
+	}
+)
+
+func (x Direction) Enum() *Direction {
+	p := new(Direction)
+	*p = x
+	return p
+}
+
+func (x Direction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Direction) Descriptor() protoreflect.EnumDescriptor {
+	return file_arbitrage_v1_arbitrage_proto_enumTypes[1].Descriptor()
+}
+
+func (Direction) Type() protoreflect.EnumType {
+	return &file_arbitrage_v1_arbitrage_proto_enumTypes[1]
+}
+
+func (x Direction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Direction.Descriptor instead.
+func (Direction) EnumDescriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{1}
+}
+
+type TradeData struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CexPrice        float64                `protobuf:"fixed64,1,opt,name=cex_price,json=cexPrice,proto3" json:"cex_price,omitempty"`
+	DexPrice        float64                `protobuf:"fixed64,2,opt,name=dex_price,json=dexPrice,proto3" json:"dex_price,omitempty"`
+	SpreadPct       float64                `protobuf:"fixed64,3,opt,name=spread_pct,json=spreadPct,proto3" json:"spread_pct,omitempty"`
+	EstimatedProfit float64                `protobuf:"fixed64,4,opt,name=estimated_profit,json=estimatedProfit,proto3" json:"estimated_profit,omitempty"`
+	GasCost         float64                `protobuf:"fixed64,5,opt,name=gas_cost,json=gasCost,proto3" json:"gas_cost,omitempty"`
+	Symbol          string                 `protobuf:"bytes,6,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Venue           string                 `protobuf:"bytes,7,opt,name=venue,proto3" json:"venue,omitempty"`
+	Direction       Direction              `protobuf:"varint,8,opt,name=direction,proto3,enum=arbitrage.v1.Direction" json:"direction,omitempty"`
+	// Time between the exchange book and the block.
+	BookSkewMs int64 `protobuf:"varint,9,opt,name=book_skew_ms,json=bookSkewMs,proto3" json:"book_skew_ms,omitempty"`
+	// Set when book_skew_ms exceeded the allowed skew.
+	StaleBook bool `protobuf:"varint,10,opt,name=stale_book,json=staleBook,proto3" json:"stale_book,omitempty"`
+	// Trade size in token_in units.
+	Size float64 `protobuf:"fixed64,11,opt,name=size,proto3" json:"size,omitempty"`
+	// Fee tier of the pool quoted.
+	PoolFee       int64 `protobuf:"varint,12,opt,name=pool_fee,json=poolFee,proto3" json:"pool_fee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TradeData) Reset() {
+	*x = TradeData{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TradeData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradeData) ProtoMessage() {}
+
+func (x *TradeData) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradeData.ProtoReflect.Descriptor instead.
+func (*TradeData) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TradeData) GetCexPrice() float64 {
+	if x != nil {
+		return x.CexPrice
+	}
+	return 0
+}
+
+func (x *TradeData) GetDexPrice() float64 {
+	if x != nil {
+		return x.DexPrice
+	}
+	return 0
+}
+
+func (x *TradeData) GetSpreadPct() float64 {
+	if x != nil {
+		return x.SpreadPct
+	}
+	return 0
+}
+
+func (x *TradeData) GetEstimatedProfit() float64 {
+	if x != nil {
+		return x.EstimatedProfit
+	}
+	return 0
+}
+
+func (x *TradeData) GetGasCost() float64 {
+	if x != nil {
+		return x.GasCost
+	}
+	return 0
+}
+
+func (x *TradeData) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *TradeData) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *TradeData) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_DIRECTION_UNSPECIFIED
+}
+
+func (x *TradeData) GetBookSkewMs() int64 {
+	if x != nil {
+		return x.BookSkewMs
+	}
+	return 0
+}
+
+func (x *TradeData) GetStaleBook() bool {
+	if x != nil {
+		return x.StaleBook
+	}
+	return false
+}
+
+func (x *TradeData) GetSize() float64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *TradeData) GetPoolFee() int64 {
+	if x != nil {
+		return x.PoolFee
+	}
+	return 0
+}
+
+// Opportunity identifies an opportunity across the blocks it lasts.
+type Opportunity struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique to each time the opportunity opens.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Pair, venue, direction, pool and size bucket.
+	Key         string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	OpenedBlock uint64                 `protobuf:"varint,3,opt,name=opened_block,json=openedBlock,proto3" json:"opened_block,omitempty"`
+	OpenedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=opened_at,json=openedAt,proto3" json:"opened_at,omitempty"`
+	// Latest block the opportunity was seen in.
+	LastBlock uint64 `protobuf:"varint,5,opt,name=last_block,json=lastBlock,proto3" json:"last_block,omitempty"`
+	// Blocks lasted, from opened_block to last_block.
+	Blocks     uint64  `protobuf:"varint,6,opt,name=blocks,proto3" json:"blocks,omitempty"`
+	PeakProfit float64 `protobuf:"fixed64,7,opt,name=peak_profit,json=peakProfit,proto3" json:"peak_profit,omitempty"`
+	PeakBlock  uint64  `protobuf:"varint,8,opt,name=peak_block,json=peakBlock,proto3" json:"peak_block,omitempty"`
+	// Mean profit over the blocks it was evaluated in.
+	AvgProfit float64 `protobuf:"fixed64,9,opt,name=avg_profit,json=avgProfit,proto3" json:"avg_profit,omitempty"`
+	// The rest is set once the opportunity closed.
+	ClosedBlock uint64                 `protobuf:"varint,10,opt,name=closed_block,json=closedBlock,proto3" json:"closed_block,omitempty"`
+	ClosedAt    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=closed_at,json=closedAt,proto3" json:"closed_at,omitempty"`
+	// Profit lost per block from the peak to the evaluation that closed it.
+	DecayPerBlock float64 `protobuf:"fixed64,12,opt,name=decay_per_block,json=decayPerBlock,proto3" json:"decay_per_block,omitempty"`
+	// The price that moved against it most: "dex" (on-chain activity), "cex",
+	// "gas", or "unknown".
+	ClosedBy      string `protobuf:"bytes,13,opt,name=closed_by,json=closedBy,proto3" json:"closed_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Opportunity) Reset() {
+	*x = Opportunity{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Opportunity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Opportunity) ProtoMessage() {}
+
+func (x *Opportunity) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Opportunity.ProtoReflect.Descriptor instead.
+func (*Opportunity) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Opportunity) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Opportunity) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Opportunity) GetOpenedBlock() uint64 {
+	if x != nil {
+		return x.OpenedBlock
+	}
+	return 0
+}
+
+func (x *Opportunity) GetOpenedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OpenedAt
+	}
+	return nil
+}
+
+func (x *Opportunity) GetLastBlock() uint64 {
+	if x != nil {
+		return x.LastBlock
+	}
+	return 0
+}
+
+func (x *Opportunity) GetBlocks() uint64 {
+	if x != nil {
+		return x.Blocks
+	}
+	return 0
+}
+
+func (x *Opportunity) GetPeakProfit() float64 {
+	if x != nil {
+		return x.PeakProfit
+	}
+	return 0
+}
+
+func (x *Opportunity) GetPeakBlock() uint64 {
+	if x != nil {
+		return x.PeakBlock
+	}
+	return 0
+}
+
+func (x *Opportunity) GetAvgProfit() float64 {
+	if x != nil {
+		return x.AvgProfit
+	}
+	return 0
+}
+
+func (x *Opportunity) GetClosedBlock() uint64 {
+	if x != nil {
+		return x.ClosedBlock
+	}
+	return 0
+}
+
+func (x *Opportunity) GetClosedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClosedAt
+	}
+	return nil
+}
+
+func (x *Opportunity) GetDecayPerBlock() float64 {
+	if x != nil {
+		return x.DecayPerBlock
+	}
+	return 0
+}
+
+func (x *Opportunity) GetClosedBy() string {
+	if x != nil {
+		return x.ClosedBy
+	}
+	return ""
+}
+
+type ArbitrageEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Increases by one with every event broadcast, across all transports.
+	Seq         uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Type        EventType              `protobuf:"varint,2,opt,name=type,proto3,enum=arbitrage.v1.EventType" json:"type,omitempty"`
+	BlockNumber uint64                 `protobuf:"varint,3,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	Timestamp   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Set on opportunities and lifecycle events.
+	Data *TradeData `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	// Set on lifecycle events.
+	Opportunity   *Opportunity `protobuf:"bytes,6,opt,name=opportunity,proto3" json:"opportunity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArbitrageEvent) Reset() {
+	*x = ArbitrageEvent{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArbitrageEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArbitrageEvent) ProtoMessage() {}
+
+func (x *ArbitrageEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArbitrageEvent.ProtoReflect.Descriptor instead.
+func (*ArbitrageEvent) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ArbitrageEvent) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ArbitrageEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *ArbitrageEvent) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *ArbitrageEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ArbitrageEvent) GetData() *TradeData {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ArbitrageEvent) GetOpportunity() *Opportunity {
+	if x != nil {
+		return x.Opportunity
+	}
+	return nil
+}
+
+// Gap reports events after resume_from that could not be replayed.
+type Gap struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The missed range, inclusive, when known.
+	From uint64 `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   uint64 `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	// "expired" when the events rolled out of the buffer, "reset" when the
+	// sequence restarted.
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Gap) Reset() {
+	*x = Gap{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Gap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Gap) ProtoMessage() {}
+
+func (x *Gap) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Gap.ProtoReflect.Descriptor instead.
+func (*Gap) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Gap) GetFrom() uint64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *Gap) GetTo() uint64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *Gap) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// EventFilter selects events. Empty lists match everything. Symbols,
+// venues, directions and min_profit only constrain opportunities, and
+// min_profit does not apply to OPPORTUNITY_UPDATED and OPPORTUNITY_CLOSED.
+type EventFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	Venues        []string               `protobuf:"bytes,2,rep,name=venues,proto3" json:"venues,omitempty"`
+	Types         []EventType            `protobuf:"varint,3,rep,packed,name=types,proto3,enum=arbitrage.v1.EventType" json:"types,omitempty"`
+	Directions    []Direction            `protobuf:"varint,4,rep,packed,name=directions,proto3,enum=arbitrage.v1.Direction" json:"directions,omitempty"`
+	MinProfit     float64                `protobuf:"fixed64,5,opt,name=min_profit,json=minProfit,proto3" json:"min_profit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventFilter) Reset() {
+	*x = EventFilter{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventFilter) ProtoMessage() {}
+
+func (x *EventFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventFilter.ProtoReflect.Descriptor instead.
+func (*EventFilter) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EventFilter) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *EventFilter) GetVenues() []string {
+	if x != nil {
+		return x.Venues
+	}
+	return nil
+}
+
+func (x *EventFilter) GetTypes() []EventType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *EventFilter) GetDirections() []Direction {
+	if x != nil {
+		return x.Directions
+	}
+	return nil
+}
+
+func (x *EventFilter) GetMinProfit() float64 {
+	if x != nil {
+		return x.MinProfit
+	}
+	return 0
+}
+
+type SubscribeRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *EventFilter           `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// When set, the buffered events after this seq are sent first.
+	ResumeFrom    *uint64 `protobuf:"varint,2,opt,name=resume_from,json=resumeFrom,proto3,oneof" json:"resume_from,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SubscribeRequest) GetFilter() *EventFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *SubscribeRequest) GetResumeFrom() uint64 {
+	if x != nil && x.ResumeFrom != nil {
+		return *x.ResumeFrom
+	}
+	return 0
+}
+
+type SubscribeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Message:
+	//
+	//	*SubscribeResponse_Event
+	//	*SubscribeResponse_Gap
+	Message       isSubscribeResponse_Message `protobuf_oneof:"message"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeResponse) Reset() {
+	*x = SubscribeResponse{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeResponse) ProtoMessage() {}
+
+func (x *SubscribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeResponse) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SubscribeResponse) GetMessage() isSubscribeResponse_Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *SubscribeResponse) GetEvent() *ArbitrageEvent {
+	if x != nil {
+		if x, ok := x.Message.(*SubscribeResponse_Event); ok {
+			return x.Event
+		}
+	}
+	return nil
+}
+
+func (x *SubscribeResponse) GetGap() *Gap {
+	if x != nil {
+		if x, ok := x.Message.(*SubscribeResponse_Gap); ok {
+			return x.Gap
+		}
+	}
+	return nil
+}
+
+type isSubscribeResponse_Message interface {
+	isSubscribeResponse_Message()
+}
+
+type SubscribeResponse_Event struct {
+	Event *ArbitrageEvent `protobuf:"bytes,1,opt,name=event,proto3,oneof"`
+}
+
+type SubscribeResponse_Gap struct {
+	Gap *Gap `protobuf:"bytes,2,opt,name=gap,proto3,oneof"`
+}
+
+func (*SubscribeResponse_Event) isSubscribeResponse_Message() {}
+
+func (*SubscribeResponse_Gap) isSubscribeResponse_Message() {}
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{7}
+}
+
+type GetStatusResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Venue     string                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	Symbols   []string               `protobuf:"bytes,2,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	StartedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	// The latest block seen, 0 before the first.
+	LastBlock     uint64                 `protobuf:"varint,4,opt,name=last_block,json=lastBlock,proto3" json:"last_block,omitempty"`
+	LastBlockAt   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_block_at,json=lastBlockAt,proto3" json:"last_block_at,omitempty"`
+	ActiveWorkers int32                  `protobuf:"varint,6,opt,name=active_workers,json=activeWorkers,proto3" json:"active_workers,omitempty"`
+	MaxWorkers    int32                  `protobuf:"varint,7,opt,name=max_workers,json=maxWorkers,proto3" json:"max_workers,omitempty"`
+	// Minimum profit in token_out units, as a decimal string.
+	MinProfit string `protobuf:"bytes,8,opt,name=min_profit,json=minProfit,proto3" json:"min_profit,omitempty"`
+	// Sequence of the latest event broadcast.
+	LastSeq uint64 `protobuf:"varint,9,opt,name=last_seq,json=lastSeq,proto3" json:"last_seq,omitempty"`
+	// Clients connected over any transport.
+	Subscribers   int32 `protobuf:"varint,10,opt,name=subscribers,proto3" json:"subscribers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusResponse) Reset() {
+	*x = GetStatusResponse{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusResponse) ProtoMessage() {}
+
+func (x *GetStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetStatusResponse) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetStatusResponse) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *GetStatusResponse) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *GetStatusResponse) GetLastBlock() uint64 {
+	if x != nil {
+		return x.LastBlock
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetLastBlockAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastBlockAt
+	}
+	return nil
+}
+
+func (x *GetStatusResponse) GetActiveWorkers() int32 {
+	if x != nil {
+		return x.ActiveWorkers
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetMaxWorkers() int32 {
+	if x != nil {
+		return x.MaxWorkers
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetMinProfit() string {
+	if x != nil {
+		return x.MinProfit
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetLastSeq() uint64 {
+	if x != nil {
+		return x.LastSeq
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetSubscribers() int32 {
+	if x != nil {
+		return x.Subscribers
+	}
+	return 0
+}
+
+type Evaluation struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Symbol      string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Venue       string                 `protobuf:"bytes,2,opt,name=venue,proto3" json:"venue,omitempty"`
+	BlockNumber uint64                 `protobuf:"varint,3,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	Timestamp   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The most profitable trade, unset when none cleared the minimum profit.
+	Best *TradeData `protobuf:"bytes,5,opt,name=best,proto3" json:"best,omitempty"`
+	// Why the block could not be evaluated, empty on success.
+	Error         string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Evaluation) Reset() {
+	*x = Evaluation{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Evaluation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Evaluation) ProtoMessage() {}
+
+func (x *Evaluation) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Evaluation.ProtoReflect.Descriptor instead.
+func (*Evaluation) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Evaluation) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Evaluation) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *Evaluation) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *Evaluation) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Evaluation) GetBest() *TradeData {
+	if x != nil {
+		return x.Best
+	}
+	return nil
+}
+
+func (x *Evaluation) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetLatestEvaluationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Limits the result to these pairs; empty returns all.
+	Symbols       []string `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestEvaluationsRequest) Reset() {
+	*x = GetLatestEvaluationsRequest{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestEvaluationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestEvaluationsRequest) ProtoMessage() {}
+
+func (x *GetLatestEvaluationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestEvaluationsRequest.ProtoReflect.Descriptor instead.
+func (*GetLatestEvaluationsRequest) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetLatestEvaluationsRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+type GetLatestEvaluationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Evaluations   []*Evaluation          `protobuf:"bytes,1,rep,name=evaluations,proto3" json:"evaluations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestEvaluationsResponse) Reset() {
+	*x = GetLatestEvaluationsResponse{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestEvaluationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestEvaluationsResponse) ProtoMessage() {}
+
+func (x *GetLatestEvaluationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestEvaluationsResponse.ProtoReflect.Descriptor instead.
+func (*GetLatestEvaluationsResponse) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetLatestEvaluationsResponse) GetEvaluations() []*Evaluation {
+	if x != nil {
+		return x.Evaluations
+	}
+	return nil
+}
+
+type GetOpportunityStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty selects every pair and venue.
+	Symbols []string `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	Venues  []string `protobuf:"bytes,2,rep,name=venues,proto3" json:"venues,omitempty"`
+	// Number of recently closed opportunities to return, at most 100.
+	Recent        uint32 `protobuf:"varint,3,opt,name=recent,proto3" json:"recent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOpportunityStatsRequest) Reset() {
+	*x = GetOpportunityStatsRequest{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOpportunityStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOpportunityStatsRequest) ProtoMessage() {}
+
+func (x *GetOpportunityStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOpportunityStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetOpportunityStatsRequest) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetOpportunityStatsRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *GetOpportunityStatsRequest) GetVenues() []string {
+	if x != nil {
+		return x.Venues
+	}
+	return nil
+}
+
+func (x *GetOpportunityStatsRequest) GetRecent() uint32 {
+	if x != nil {
+		return x.Recent
+	}
+	return 0
+}
+
+// OpportunitySummary aggregates the opportunities closed on one pair and
+// venue.
+type OpportunitySummary struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Symbol             string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Venue              string                 `protobuf:"bytes,2,opt,name=venue,proto3" json:"venue,omitempty"`
+	Closed             uint32                 `protobuf:"varint,3,opt,name=closed,proto3" json:"closed,omitempty"`
+	AvgBlocks          float64                `protobuf:"fixed64,4,opt,name=avg_blocks,json=avgBlocks,proto3" json:"avg_blocks,omitempty"`
+	MaxBlocks          uint64                 `protobuf:"varint,5,opt,name=max_blocks,json=maxBlocks,proto3" json:"max_blocks,omitempty"`
+	AvgDurationSeconds float64                `protobuf:"fixed64,6,opt,name=avg_duration_seconds,json=avgDurationSeconds,proto3" json:"avg_duration_seconds,omitempty"`
+	AvgPeakProfit      float64                `protobuf:"fixed64,7,opt,name=avg_peak_profit,json=avgPeakProfit,proto3" json:"avg_peak_profit,omitempty"`
+	MaxPeakProfit      float64                `protobuf:"fixed64,8,opt,name=max_peak_profit,json=maxPeakProfit,proto3" json:"max_peak_profit,omitempty"`
+	AvgProfit          float64                `protobuf:"fixed64,9,opt,name=avg_profit,json=avgProfit,proto3" json:"avg_profit,omitempty"`
+	AvgDecayPerBlock   float64                `protobuf:"fixed64,10,opt,name=avg_decay_per_block,json=avgDecayPerBlock,proto3" json:"avg_decay_per_block,omitempty"`
+	// Closes by the price that moved against them.
+	ClosedBy      map[string]uint32 `protobuf:"bytes,11,rep,name=closed_by,json=closedBy,proto3" json:"closed_by,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpportunitySummary) Reset() {
+	*x = OpportunitySummary{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpportunitySummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpportunitySummary) ProtoMessage() {}
+
+func (x *OpportunitySummary) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpportunitySummary.ProtoReflect.Descriptor instead.
+func (*OpportunitySummary) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *OpportunitySummary) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *OpportunitySummary) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *OpportunitySummary) GetClosed() uint32 {
+	if x != nil {
+		return x.Closed
+	}
+	return 0
+}
+
+func (x *OpportunitySummary) GetAvgBlocks() float64 {
+	if x != nil {
+		return x.AvgBlocks
+	}
+	return 0
+}
+
+func (x *OpportunitySummary) GetMaxBlocks() uint64 {
+	if x != nil {
+		return x.MaxBlocks
+	}
+	return 0
+}
+
+func (x *OpportunitySummary) GetAvgDurationSeconds() float64 {
+	if x != nil {
+		return x.AvgDurationSeconds
+	}
+	return 0
+}
+
+func (x *OpportunitySummary) GetAvgPeakProfit() float64 {
+	if x != nil {
+		return x.AvgPeakProfit
+	}
+	return 0
+}
+
+func (x *OpportunitySummary) GetMaxPeakProfit() float64 {
+	if x != nil {
+		return x.MaxPeakProfit
+	}
+	return 0
+}
+
+func (x *OpportunitySummary) GetAvgProfit() float64 {
+	if x != nil {
+		return x.AvgProfit
+	}
+	return 0
+}
+
+func (x *OpportunitySummary) GetAvgDecayPerBlock() float64 {
+	if x != nil {
+		return x.AvgDecayPerBlock
+	}
+	return 0
+}
+
+func (x *OpportunitySummary) GetClosedBy() map[string]uint32 {
+	if x != nil {
+		return x.ClosedBy
+	}
+	return nil
+}
+
+type GetOpportunityStatsResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Summaries []*OpportunitySummary  `protobuf:"bytes,1,rep,name=summaries,proto3" json:"summaries,omitempty"`
+	// OPPORTUNITY_CLOSED events, newest first.
+	Recent        []*ArbitrageEvent `protobuf:"bytes,2,rep,name=recent,proto3" json:"recent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOpportunityStatsResponse) Reset() {
+	*x = GetOpportunityStatsResponse{}
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOpportunityStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOpportunityStatsResponse) ProtoMessage() {}
+
+func (x *GetOpportunityStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_arbitrage_v1_arbitrage_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOpportunityStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetOpportunityStatsResponse) Descriptor() ([]byte, []int) {
+	return file_arbitrage_v1_arbitrage_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetOpportunityStatsResponse) GetSummaries() []*OpportunitySummary {
+	if x != nil {
+		return x.Summaries
+	}
+	return nil
+}
+
+func (x *GetOpportunityStatsResponse) GetRecent() []*ArbitrageEvent {
+	if x != nil {
+		return x.Recent
+	}
+	return nil
+}
+
+var File_arbitrage_v1_arbitrage_proto protoreflect.FileDescriptor
+
+const file_arbitrage_v1_arbitrage_proto_rawDesc = "" +
+	"\n" +
+	"\x1carbitrage/v1/arbitrage.proto\x12\farbitrage.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xff\x02\n" +
+	"\tTradeData\x12\x1b\n" +
+	"\tcex_price\x18\x01 \x01(\x01R\bcexPrice\x12\x1b\n" +
+	"\tdex_price\x18\x02 \x01(\x01R\bdexPrice\x12\x1d\n" +
+	"\n" +
+	"spread_pct\x18\x03 \x01(\x01R\tspreadPct\x12)\n" +
+	"\x10estimated_profit\x18\x04 \x01(\x01R\x0festimatedProfit\x12\x19\n" +
+	"\bgas_cost\x18\x05 \x01(\x01R\agasCost\x12\x16\n" +
+	"\x06symbol\x18\x06 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05venue\x18\a \x01(\tR\x05venue\x125\n" +
+	"\tdirection\x18\b \x01(\x0e2\x17.arbitrage.v1.DirectionR\tdirection\x12 \n" +
+	"\fbook_skew_ms\x18\t \x01(\x03R\n" +
+	"bookSkewMs\x12\x1d\n" +
+	"\n" +
+	"stale_book\x18\n" +
+	" \x01(\bR\tstaleBook\x12\x12\n" +
+	"\x04size\x18\v \x01(\x01R\x04size\x12\x19\n" +
+	"\bpool_fee\x18\f \x01(\x03R\apoolFee\"\xc2\x03\n" +
+	"\vOpportunity\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12!\n" +
+	"\fopened_block\x18\x03 \x01(\x04R\vopenedBlock\x127\n" +
+	"\topened_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bopenedAt\x12\x1d\n" +
+	"\n" +
+	"last_block\x18\x05 \x01(\x04R\tlastBlock\x12\x16\n" +
+	"\x06blocks\x18\x06 \x01(\x04R\x06blocks\x12\x1f\n" +
+	"\vpeak_profit\x18\a \x01(\x01R\n" +
+	"peakProfit\x12\x1d\n" +
+	"\n" +
+	"peak_block\x18\b \x01(\x04R\tpeakBlock\x12\x1d\n" +
+	"\n" +
+	"avg_profit\x18\t \x01(\x01R\tavgProfit\x12!\n" +
+	"\fclosed_block\x18\n" +
+	" \x01(\x04R\vclosedBlock\x127\n" +
+	"\tclosed_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\bclosedAt\x12&\n" +
+	"\x0fdecay_per_block\x18\f \x01(\x01R\rdecayPerBlock\x12\x1b\n" +
+	"\tclosed_by\x18\r \x01(\tR\bclosedBy\"\x96\x02\n" +
+	"\x0eArbitrageEvent\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12+\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x17.arbitrage.v1.EventTypeR\x04type\x12!\n" +
+	"\fblock_number\x18\x03 \x01(\x04R\vblockNumber\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12+\n" +
+	"\x04data\x18\x05 \x01(\v2\x17.arbitrage.v1.TradeDataR\x04data\x12;\n" +
+	"\vopportunity\x18\x06 \x01(\v2\x19.arbitrage.v1.OpportunityR\vopportunity\"A\n" +
+	"\x03Gap\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\x04R\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\x04R\x02to\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\xc6\x01\n" +
+	"\vEventFilter\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\x12\x16\n" +
+	"\x06venues\x18\x02 \x03(\tR\x06venues\x12-\n" +
+	"\x05types\x18\x03 \x03(\x0e2\x17.arbitrage.v1.EventTypeR\x05types\x127\n" +
+	"\n" +
+	"directions\x18\x04 \x03(\x0e2\x17.arbitrage.v1.DirectionR\n" +
+	"directions\x12\x1d\n" +
+	"\n" +
+	"min_profit\x18\x05 \x01(\x01R\tminProfit\"{\n" +
+	"\x10SubscribeRequest\x121\n" +
+	"\x06filter\x18\x01 \x01(\v2\x19.arbitrage.v1.EventFilterR\x06filter\x12$\n" +
+	"\vresume_from\x18\x02 \x01(\x04H\x00R\n" +
+	"resumeFrom\x88\x01\x01B\x0e\n" +
+	"\f_resume_from\"{\n" +
+	"\x11SubscribeResponse\x124\n" +
+	"\x05event\x18\x01 \x01(\v2\x1c.arbitrage.v1.ArbitrageEventH\x00R\x05event\x12%\n" +
+	"\x03gap\x18\x02 \x01(\v2\x11.arbitrage.v1.GapH\x00R\x03gapB\t\n" +
+	"\amessage\"\x12\n" +
+	"\x10GetStatusRequest\"\x81\x03\n" +
+	"\x11GetStatusResponse\x12\x14\n" +
+	"\x05venue\x18\x01 \x01(\tR\x05venue\x12\x18\n" +
+	"\asymbols\x18\x02 \x03(\tR\asymbols\x129\n" +
+	"\n" +
+	"started_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12\x1d\n" +
+	"\n" +
+	"last_block\x18\x04 \x01(\x04R\tlastBlock\x12>\n" +
+	"\rlast_block_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vlastBlockAt\x12%\n" +
+	"\x0eactive_workers\x18\x06 \x01(\x05R\ractiveWorkers\x12\x1f\n" +
+	"\vmax_workers\x18\a \x01(\x05R\n" +
+	"maxWorkers\x12\x1d\n" +
+	"\n" +
+	"min_profit\x18\b \x01(\tR\tminProfit\x12\x19\n" +
+	"\blast_seq\x18\t \x01(\x04R\alastSeq\x12 \n" +
+	"\vsubscribers\x18\n" +
+	" \x01(\x05R\vsubscribers\"\xda\x01\n" +
+	"\n" +
+	"Evaluation\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05venue\x18\x02 \x01(\tR\x05venue\x12!\n" +
+	"\fblock_number\x18\x03 \x01(\x04R\vblockNumber\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12+\n" +
+	"\x04best\x18\x05 \x01(\v2\x17.arbitrage.v1.TradeDataR\x04best\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\"7\n" +
+	"\x1bGetLatestEvaluationsRequest\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\"Z\n" +
+	"\x1cGetLatestEvaluationsResponse\x12:\n" +
+	"\vevaluations\x18\x01 \x03(\v2\x18.arbitrage.v1.EvaluationR\vevaluations\"f\n" +
+	"\x1aGetOpportunityStatsRequest\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\x12\x16\n" +
+	"\x06venues\x18\x02 \x03(\tR\x06venues\x12\x16\n" +
+	"\x06recent\x18\x03 \x01(\rR\x06recent\"\xf2\x03\n" +
+	"\x12OpportunitySummary\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05venue\x18\x02 \x01(\tR\x05venue\x12\x16\n" +
+	"\x06closed\x18\x03 \x01(\rR\x06closed\x12\x1d\n" +
+	"\n" +
+	"avg_blocks\x18\x04 \x01(\x01R\tavgBlocks\x12\x1d\n" +
+	"\n" +
+	"max_blocks\x18\x05 \x01(\x04R\tmaxBlocks\x120\n" +
+	"\x14avg_duration_seconds\x18\x06 \x01(\x01R\x12avgDurationSeconds\x12&\n" +
+	"\x0favg_peak_profit\x18\a \x01(\x01R\ravgPeakProfit\x12&\n" +
+	"\x0fmax_peak_profit\x18\b \x01(\x01R\rmaxPeakProfit\x12\x1d\n" +
+	"\n" +
+	"avg_profit\x18\t \x01(\x01R\tavgProfit\x12-\n" +
+	"\x13avg_decay_per_block\x18\n" +
+	" \x01(\x01R\x10avgDecayPerBlock\x12K\n" +
+	"\tclosed_by\x18\v \x03(\v2..arbitrage.v1.OpportunitySummary.ClosedByEntryR\bclosedBy\x1a;\n" +
+	"\rClosedByEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\rR\x05value:\x028\x01\"\x93\x01\n" +
+	"\x1bGetOpportunityStatsResponse\x12>\n" +
+	"\tsummaries\x18\x01 \x03(\v2 .arbitrage.v1.OpportunitySummaryR\tsummaries\x124\n" +
+	"\x06recent\x18\x02 \x03(\v2\x1c.arbitrage.v1.ArbitrageEventR\x06recent*\xc7\x01\n" +
+	"\tEventType\x12\x1a\n" +
+	"\x16EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14EVENT_TYPE_HEARTBEAT\x10\x01\x12\x1a\n" +
+	"\x16EVENT_TYPE_OPPORTUNITY\x10\x02\x12!\n" +
+	"\x1dEVENT_TYPE_OPPORTUNITY_OPENED\x10\x03\x12\"\n" +
+	"\x1eEVENT_TYPE_OPPORTUNITY_UPDATED\x10\x04\x12!\n" +
+	"\x1dEVENT_TYPE_OPPORTUNITY_CLOSED\x10\x05*Z\n" +
+	"\tDirection\x12\x19\n" +
+	"\x15DIRECTION_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14DIRECTION_CEX_TO_DEX\x10\x01\x12\x18\n" +
+	"\x14DIRECTION_DEX_TO_CEX\x10\x022\x8b\x03\n" +
+	"\x10ArbitrageService\x12N\n" +
+	"\tSubscribe\x12\x1e.arbitrage.v1.SubscribeRequest\x1a\x1f.arbitrage.v1.SubscribeResponse0\x01\x12L\n" +
+	"\tGetStatus\x12\x1e.arbitrage.v1.GetStatusRequest\x1a\x1f.arbitrage.v1.GetStatusResponse\x12m\n" +
+	"\x14GetLatestEvaluations\x12).arbitrage.v1.GetLatestEvaluationsRequest\x1a*.arbitrage.v1.GetLatestEvaluationsResponse\x12j\n" +
+	"\x13GetOpportunityStats\x12(.arbitrage.v1.GetOpportunityStatsRequest\x1a).arbitrage.v1.GetOpportunityStatsResponseBVZTgithub.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/api/arbitrage/v1;arbitragev1b\x06proto3"
+
+var (
+	file_arbitrage_v1_arbitrage_proto_rawDescOnce sync.Once
+	file_arbitrage_v1_arbitrage_proto_rawDescData []byte
+)
+
+func file_arbitrage_v1_arbitrage_proto_rawDescGZIP() []byte {
+	file_arbitrage_v1_arbitrage_proto_rawDescOnce.Do(func() {
+		file_arbitrage_v1_arbitrage_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_arbitrage_v1_arbitrage_proto_rawDesc), len(file_arbitrage_v1_arbitrage_proto_rawDesc)))
+	})
+	return file_arbitrage_v1_arbitrage_proto_rawDescData
+}
+
+var file_arbitrage_v1_arbitrage_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_arbitrage_v1_arbitrage_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_arbitrage_v1_arbitrage_proto_goTypes = []any{
+	(EventType)(0),                       // 0: arbitrage.v1.EventType
+	(Direction)(0),                       // 1: arbitrage.v1.Direction
+	(*TradeData)(nil),                    // 2: arbitrage.v1.TradeData
+	(*Opportunity)(nil),                  // 3: arbitrage.v1.Opportunity
+	(*ArbitrageEvent)(nil),               // 4: arbitrage.v1.ArbitrageEvent
+	(*Gap)(nil),                          // 5: arbitrage.v1.Gap
+	(*EventFilter)(nil),                  // 6: arbitrage.v1.EventFilter
+	(*SubscribeRequest)(nil),             // 7: arbitrage.v1.SubscribeRequest
+	(*SubscribeResponse)(nil),            // 8: arbitrage.v1.SubscribeResponse
+	(*GetStatusRequest)(nil),             // 9: arbitrage.v1.GetStatusRequest
+	(*GetStatusResponse)(nil),            // 10: arbitrage.v1.GetStatusResponse
+	(*Evaluation)(nil),                   // 11: arbitrage.v1.Evaluation
+	(*GetLatestEvaluationsRequest)(nil),  // 12: arbitrage.v1.GetLatestEvaluationsRequest
+	(*GetLatestEvaluationsResponse)(nil), // 13: arbitrage.v1.GetLatestEvaluationsResponse
+	(*GetOpportunityStatsRequest)(nil),   // 14: arbitrage.v1.GetOpportunityStatsRequest
+	(*OpportunitySummary)(nil),           // 15: arbitrage.v1.OpportunitySummary
+	(*GetOpportunityStatsResponse)(nil),  // 16: arbitrage.v1.GetOpportunityStatsResponse
+	nil,                                  // 17: arbitrage.v1.OpportunitySummary.ClosedByEntry
+	(*timestamppb.Timestamp)(nil),        // 18: google.protobuf.Timestamp
+}
+var file_arbitrage_v1_arbitrage_proto_depIdxs = []int32{
+	1,  // 0: arbitrage.v1.TradeData.direction:type_name -> arbitrage.v1.Direction
+	18, // 1: arbitrage.v1.Opportunity.opened_at:type_name -> google.protobuf.Timestamp
+	18, // 2: arbitrage.v1.Opportunity.closed_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: arbitrage.v1.ArbitrageEvent.type:type_name -> arbitrage.v1.EventType
+	18, // 4: arbitrage.v1.ArbitrageEvent.timestamp:type_name -> google.protobuf.Timestamp
+	2,  // 5: arbitrage.v1.ArbitrageEvent.data:type_name -> arbitrage.v1.TradeData
+	3,  // 6: arbitrage.v1.ArbitrageEvent.opportunity:type_name -> arbitrage.v1.Opportunity
+	0,  // 7: arbitrage.v1.EventFilter.types:type_name -> arbitrage.v1.EventType
+	1,  // 8: arbitrage.v1.EventFilter.directions:type_name -> arbitrage.v1.Direction
+	6,  // 9: arbitrage.v1.SubscribeRequest.filter:type_name -> arbitrage.v1.EventFilter
+	4,  // 10: arbitrage.v1.SubscribeResponse.event:type_name -> arbitrage.v1.ArbitrageEvent
+	5,  // 11: arbitrage.v1.SubscribeResponse.gap:type_name -> arbitrage.v1.Gap
+	18, // 12: arbitrage.v1.GetStatusResponse.started_at:type_name -> google.protobuf.Timestamp
+	18, // 13: arbitrage.v1.GetStatusResponse.last_block_at:type_name -> google.protobuf.Timestamp
+	18, // 14: arbitrage.v1.Evaluation.timestamp:type_name -> google.protobuf.Timestamp
+	2,  // 15: arbitrage.v1.Evaluation.best:type_name -> arbitrage.v1.TradeData
+	11, // 16: arbitrage.v1.GetLatestEvaluationsResponse.evaluations:type_name -> arbitrage.v1.Evaluation
+	17, // 17: arbitrage.v1.OpportunitySummary.closed_by:type_name -> arbitrage.v1.OpportunitySummary.ClosedByEntry
+	15, // 18: arbitrage.v1.GetOpportunityStatsResponse.summaries:type_name -> arbitrage.v1.OpportunitySummary
+	4,  // 19: arbitrage.v1.GetOpportunityStatsResponse.recent:type_name -> arbitrage.v1.ArbitrageEvent
+	7,  // 20: arbitrage.v1.ArbitrageService.Subscribe:input_type -> arbitrage.v1.SubscribeRequest
+	9,  // 21: arbitrage.v1.ArbitrageService.GetStatus:input_type -> arbitrage.v1.GetStatusRequest
+	12, // 22: arbitrage.v1.ArbitrageService.GetLatestEvaluations:input_type -> arbitrage.v1.GetLatestEvaluationsRequest
+	14, // 23: arbitrage.v1.ArbitrageService.GetOpportunityStats:input_type -> arbitrage.v1.GetOpportunityStatsRequest
+	8,  // 24: arbitrage.v1.ArbitrageService.Subscribe:output_type -> arbitrage.v1.SubscribeResponse
+	10, // 25: arbitrage.v1.ArbitrageService.GetStatus:output_type -> arbitrage.v1.GetStatusResponse
+	13, // 26: arbitrage.v1.ArbitrageService.GetLatestEvaluations:output_type -> arbitrage.v1.GetLatestEvaluationsResponse
+	16, // 27: arbitrage.v1.ArbitrageService.GetOpportunityStats:output_type -> arbitrage.v1.GetOpportunityStatsResponse
+	24, // [24:28] is the sub-list for method output_type
+	20, // [20:24] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_arbitrage_v1_arbitrage_proto_init() }
+func file_arbitrage_v1_arbitrage_proto_init() {
+	if File_arbitrage_v1_arbitrage_proto != nil {
+		return
+	}
+	file_arbitrage_v1_arbitrage_proto_msgTypes[5].OneofWrappers = []any{}
+	file_arbitrage_v1_arbitrage_proto_msgTypes[6].OneofWrappers = []any{
+		(*SubscribeResponse_Event)(nil),
+		(*SubscribeResponse_Gap)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_arbitrage_v1_arbitrage_proto_rawDesc), len(file_arbitrage_v1_arbitrage_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_arbitrage_v1_arbitrage_proto_goTypes,
+		DependencyIndexes: file_arbitrage_v1_arbitrage_proto_depIdxs,
+		EnumInfos:         file_arbitrage_v1_arbitrage_proto_enumTypes,
+		MessageInfos:      file_arbitrage_v1_arbitrage_proto_msgTypes,
+	}.Build()
+	File_arbitrage_v1_arbitrage_proto = out.File
+	file_arbitrage_v1_arbitrage_proto_goTypes = nil
+	file_arbitrage_v1_arbitrage_proto_depIdxs = nil
+}
@@ -0,0 +1,261 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: arbitrage/v1/arbitrage.proto
+
+package arbitragev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ArbitrageService_Subscribe_FullMethodName            = "/arbitrage.v1.ArbitrageService/Subscribe"
+	ArbitrageService_GetStatus_FullMethodName            = "/arbitrage.v1.ArbitrageService/GetStatus"
+	ArbitrageService_GetLatestEvaluations_FullMethodName = "/arbitrage.v1.ArbitrageService/GetLatestEvaluations"
+	ArbitrageService_GetOpportunityStats_FullMethodName  = "/arbitrage.v1.ArbitrageService/GetOpportunityStats"
+)
+
+// ArbitrageServiceClient is the client API for ArbitrageService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ArbitrageService streams the events broadcast on /ws and /events and
+// reports the state of the engine.
+type ArbitrageServiceClient interface {
+	// Subscribe streams events matching the filter until the client cancels.
+	// A client that falls too far behind is disconnected with
+	// RESOURCE_EXHAUSTED and can resume from the last seq it received.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscribeResponse], error)
+	// GetStatus reports what the engine is watching and how far it has got.
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	// GetLatestEvaluations returns the outcome of the most recent block
+	// evaluated for each pair.
+	GetLatestEvaluations(ctx context.Context, in *GetLatestEvaluationsRequest, opts ...grpc.CallOption) (*GetLatestEvaluationsResponse, error)
+	// GetOpportunityStats summarizes the opportunities closed per pair and
+	// venue, with the latest ones.
+	GetOpportunityStats(ctx context.Context, in *GetOpportunityStatsRequest, opts ...grpc.CallOption) (*GetOpportunityStatsResponse, error)
+}
+
+type arbitrageServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewArbitrageServiceClient(cc grpc.ClientConnInterface) ArbitrageServiceClient {
+	return &arbitrageServiceClient{cc}
+}
+
+func (c *arbitrageServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscribeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ArbitrageService_ServiceDesc.Streams[0], ArbitrageService_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, SubscribeResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ArbitrageService_SubscribeClient = grpc.ServerStreamingClient[SubscribeResponse]
+
+func (c *arbitrageServiceClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, ArbitrageService_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *arbitrageServiceClient) GetLatestEvaluations(ctx context.Context, in *GetLatestEvaluationsRequest, opts ...grpc.CallOption) (*GetLatestEvaluationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLatestEvaluationsResponse)
+	err := c.cc.Invoke(ctx, ArbitrageService_GetLatestEvaluations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *arbitrageServiceClient) GetOpportunityStats(ctx context.Context, in *GetOpportunityStatsRequest, opts ...grpc.CallOption) (*GetOpportunityStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOpportunityStatsResponse)
+	err := c.cc.Invoke(ctx, ArbitrageService_GetOpportunityStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ArbitrageServiceServer is the server API for ArbitrageService service.
+// All implementations must embed UnimplementedArbitrageServiceServer
+// for forward compatibility.
+//
+// ArbitrageService streams the events broadcast on /ws and /events and
+// reports the state of the engine.
+type ArbitrageServiceServer interface {
+	// Subscribe streams events matching the filter until the client cancels.
+	// A client that falls too far behind is disconnected with
+	// RESOURCE_EXHAUSTED and can resume from the last seq it received.
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[SubscribeResponse]) error
+	// GetStatus reports what the engine is watching and how far it has got.
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	// GetLatestEvaluations returns the outcome of the most recent block
+	// evaluated for each pair.
+	GetLatestEvaluations(context.Context, *GetLatestEvaluationsRequest) (*GetLatestEvaluationsResponse, error)
+	// GetOpportunityStats summarizes the opportunities closed per pair and
+	// venue, with the latest ones.
+	GetOpportunityStats(context.Context, *GetOpportunityStatsRequest) (*GetOpportunityStatsResponse, error)
+	mustEmbedUnimplementedArbitrageServiceServer()
+}
+
+// UnimplementedArbitrageServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedArbitrageServiceServer struct{}
+
+func (UnimplementedArbitrageServiceServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[SubscribeResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedArbitrageServiceServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedArbitrageServiceServer) GetLatestEvaluations(context.Context, *GetLatestEvaluationsRequest) (*GetLatestEvaluationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatestEvaluations not implemented")
+}
+func (UnimplementedArbitrageServiceServer) GetOpportunityStats(context.Context, *GetOpportunityStatsRequest) (*GetOpportunityStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOpportunityStats not implemented")
+}
+func (UnimplementedArbitrageServiceServer) mustEmbedUnimplementedArbitrageServiceServer() {}
+func (UnimplementedArbitrageServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeArbitrageServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ArbitrageServiceServer will
+// result in compilation errors.
+type UnsafeArbitrageServiceServer interface {
+	mustEmbedUnimplementedArbitrageServiceServer()
+}
+
+func RegisterArbitrageServiceServer(s grpc.ServiceRegistrar, srv ArbitrageServiceServer) {
+	// If the following call pancis, it indicates UnimplementedArbitrageServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ArbitrageService_ServiceDesc, srv)
+}
+
+func _ArbitrageService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ArbitrageServiceServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, SubscribeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ArbitrageService_SubscribeServer = grpc.ServerStreamingServer[SubscribeResponse]
+
+func _ArbitrageService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArbitrageServiceServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArbitrageService_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArbitrageServiceServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArbitrageService_GetLatestEvaluations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestEvaluationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArbitrageServiceServer).GetLatestEvaluations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArbitrageService_GetLatestEvaluations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArbitrageServiceServer).GetLatestEvaluations(ctx, req.(*GetLatestEvaluationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArbitrageService_GetOpportunityStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOpportunityStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArbitrageServiceServer).GetOpportunityStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArbitrageService_GetOpportunityStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArbitrageServiceServer).GetOpportunityStats(ctx, req.(*GetOpportunityStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ArbitrageService_ServiceDesc is the grpc.ServiceDesc for ArbitrageService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ArbitrageService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "arbitrage.v1.ArbitrageService",
+	HandlerType: (*ArbitrageServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _ArbitrageService_GetStatus_Handler,
+		},
+		{
+			MethodName: "GetLatestEvaluations",
+			Handler:    _ArbitrageService_GetLatestEvaluations_Handler,
+		},
+		{
+			MethodName: "GetOpportunityStats",
+			Handler:    _ArbitrageService_GetOpportunityStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _ArbitrageService_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "arbitrage/v1/arbitrage.proto",
+}
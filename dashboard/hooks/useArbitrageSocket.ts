@@ -46,6 +46,10 @@ export function useArbitrageSocket() {
       const now = Date.now();
       try {
         const event: ArbitrageEvent = JSON.parse(msg.data);
+        // The feed shows every block; lifecycle events repeat the same trades.
+        if (event.type !== 'HEARTBEAT' && event.type !== 'OPPORTUNITY') {
+          return;
+        }
         // Calculate latency if timestamp is present
         if (event.timestamp) {
           const eventTime = new Date(event.timestamp).getTime();
@@ -0,0 +1,434 @@
+// Package analytics keeps the history of closed opportunities and summarizes
+// it per pair and venue.
+package analytics
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+)
+
+const (
+	// MaxRecent is the number of closed opportunities kept for
+	// RecentOpportunities.
+	MaxRecent = 100
+
+	// compactAfter is the number of closes appended to the history before it
+	// is replaced by a checkpoint, which bounds the replay at startup.
+	compactAfter = 10_000
+	queueSize    = 256
+)
+
+// Store records OPPORTUNITY_CLOSED events. With a path, each is appended to
+// it as a line of JSON and the summaries are rebuilt from it on startup.
+// Lines are written by a background goroutine, so a slow disk never holds up
+// the broadcast. The history is periodically compacted into a checkpoint of
+// the summaries and recent closes.
+type Store struct {
+	mu     sync.RWMutex
+	totals map[pairVenue]*totals
+	// recent holds the latest closes, oldest first.
+	recent []domain.ArbitrageEvent
+
+	// writes feeds the history writer; it is nil without a path and once
+	// closed.
+	writes       chan write
+	done         chan struct{}
+	closeErr     error
+	compactAfter int
+	// appended counts the closes in the history after its checkpoint.
+	appended int
+}
+
+// write is a line for the history writer. A checkpoint replaces the history.
+type write struct {
+	line       []byte
+	checkpoint bool
+}
+
+// checkpoint is the state of a Store, stored as the first line of a
+// compacted history.
+type checkpoint struct {
+	Totals []checkpointTotals      `json:"totals"`
+	Recent []domain.ArbitrageEvent `json:"recent"`
+}
+
+type checkpointTotals struct {
+	Symbol    string         `json:"symbol"`
+	Venue     string         `json:"venue"`
+	Closed    int            `json:"closed"`
+	Blocks    uint64         `json:"blocks"`
+	MaxBlocks uint64         `json:"maxBlocks"`
+	Duration  time.Duration  `json:"duration"`
+	Peak      float64        `json:"peak"`
+	MaxPeak   float64        `json:"maxPeak"`
+	Profit    float64        `json:"profit"`
+	Decay     float64        `json:"decay"`
+	ClosedBy  map[string]int `json:"closedBy"`
+}
+
+// record is a line of the history: a close or a checkpoint.
+type record struct {
+	domain.ArbitrageEvent
+	Checkpoint *checkpoint `json:"checkpoint,omitempty"`
+}
+
+type pairVenue struct {
+	symbol string
+	venue  string
+}
+
+// totals accumulates the opportunities closed on one pair and venue.
+type totals struct {
+	closed    int
+	blocks    uint64
+	maxBlocks uint64
+	duration  time.Duration
+	peak      float64
+	maxPeak   float64
+	profit    float64
+	decay     float64
+	closedBy  map[string]int
+}
+
+// NewStore opens the history at path, creating it if needed. An empty path
+// keeps the history in memory only.
+func NewStore(path string) (*Store, error) {
+	return newStore(path, compactAfter)
+}
+
+func newStore(path string, compactAfter int) (*Store, error) {
+	s := &Store{totals: make(map[pairVenue]*totals), compactAfter: compactAfter}
+	if path == "" {
+		return s, nil
+	}
+
+	if err := s.load(path); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create opportunity history directory: %w", err)
+	}
+	if s.appended >= s.compactAfter {
+		line, err := s.checkpointLine()
+		if err == nil {
+			err = replace(path, line)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("compact opportunity history: %w", err)
+		}
+		s.appended = 0
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open opportunity history: %w", err)
+	}
+	if err := terminate(f); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("repair opportunity history: %w", err)
+	}
+
+	s.writes = make(chan write, queueSize)
+	s.done = make(chan struct{})
+	go s.writeHistory(s.writes, path, f)
+	return s, nil
+}
+
+// terminate ends f with a newline if its last line was cut short, so that the
+// next one appended is not joined to it.
+func terminate(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+		return err
+	}
+	if last[0] == '\n' {
+		return nil
+	}
+	_, err = f.Write([]byte{'\n'})
+	return err
+}
+
+// load replays the history at path. A checkpoint replaces the state replayed
+// so far. Lines that cannot be decoded, such as one cut short by a crash, are
+// skipped.
+func (s *Store) load(path string) error {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open opportunity history: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var skipped int
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 16<<20)
+	for sc.Scan() {
+		var r record
+		err := json.Unmarshal(sc.Bytes(), &r)
+		switch {
+		case err == nil && r.Checkpoint != nil:
+			s.restore(r.Checkpoint)
+			s.appended = 0
+		case err == nil && closed(r.ArbitrageEvent):
+			s.add(r.ArbitrageEvent)
+			s.appended++
+		default:
+			skipped++
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read opportunity history: %w", err)
+	}
+	if skipped > 0 {
+		slog.Warn("Skipped unreadable opportunity history lines", "path", path, "lines", skipped)
+	}
+	var loaded int
+	for _, t := range s.totals {
+		loaded += t.closed
+	}
+	slog.Info("Loaded opportunity history", "path", path, "opportunities", loaded, "since_checkpoint", s.appended)
+	return nil
+}
+
+func closed(e domain.ArbitrageEvent) bool {
+	return e.Type == domain.EventOpportunityClosed && e.Opportunity != nil && e.Data != nil
+}
+
+// Broadcast records e if it closes an opportunity and queues it for the
+// history. It never blocks; a close that cannot be queued or persisted is
+// logged and still counts towards the summaries.
+func (s *Store) Broadcast(e domain.ArbitrageEvent) {
+	if !closed(e) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add(e)
+	if s.writes == nil {
+		return
+	}
+
+	// Once enough closes were appended, the checkpoint of the state, which
+	// includes e, is written instead of e.
+	w := write{checkpoint: s.appended >= s.compactAfter}
+	var err error
+	if w.checkpoint {
+		w.line, err = s.checkpointLine()
+	} else {
+		w.line, err = json.Marshal(e)
+		w.line = append(w.line, '\n')
+	}
+	if err != nil {
+		slog.Warn("Failed to persist closed opportunity", "id", e.Opportunity.ID, "error", err)
+		return
+	}
+
+	select {
+	case s.writes <- w:
+		if w.checkpoint {
+			s.appended = 0
+		} else {
+			s.appended++
+		}
+	default:
+		slog.Warn("Opportunity history queue full, dropping close", "id", e.Opportunity.ID)
+	}
+}
+
+// writeHistory writes the lines queued on writes to f until it is closed.
+func (s *Store) writeHistory(writes <-chan write, path string, f *os.File) {
+	defer close(s.done)
+	for w := range writes {
+		var err error
+		if w.checkpoint {
+			err = replace(path, w.line)
+			if err == nil {
+				f, err = reopen(path, f)
+			}
+		} else {
+			_, err = f.Write(w.line)
+		}
+		if err != nil {
+			slog.Warn("Failed to write opportunity history", "path", path, "error", err)
+		}
+	}
+	s.closeErr = f.Close()
+}
+
+// replace writes data to path through a temporary file, so that a crash
+// leaves either the old history or the new one.
+func replace(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// reopen opens path for appending in place of f, which was replaced.
+func reopen(path string, f *os.File) (*os.File, error) {
+	next, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return f, err
+	}
+	_ = f.Close()
+	return next, nil
+}
+
+// checkpointLine encodes the state as a history line. Must be called with
+// s.mu held.
+func (s *Store) checkpointLine() ([]byte, error) {
+	cp := checkpoint{
+		Totals: make([]checkpointTotals, 0, len(s.totals)),
+		Recent: s.recent,
+	}
+	for k, t := range s.totals {
+		cp.Totals = append(cp.Totals, checkpointTotals{
+			Symbol:    k.symbol,
+			Venue:     k.venue,
+			Closed:    t.closed,
+			Blocks:    t.blocks,
+			MaxBlocks: t.maxBlocks,
+			Duration:  t.duration,
+			Peak:      t.peak,
+			MaxPeak:   t.maxPeak,
+			Profit:    t.profit,
+			Decay:     t.decay,
+			ClosedBy:  t.closedBy,
+		})
+	}
+	line, err := json.Marshal(struct {
+		Checkpoint checkpoint `json:"checkpoint"`
+	}{cp})
+	return append(line, '\n'), err
+}
+
+// restore replaces the state with cp.
+func (s *Store) restore(cp *checkpoint) {
+	s.totals = make(map[pairVenue]*totals, len(cp.Totals))
+	for _, t := range cp.Totals {
+		closedBy := t.ClosedBy
+		if closedBy == nil {
+			closedBy = make(map[string]int)
+		}
+		s.totals[pairVenue{symbol: t.Symbol, venue: t.Venue}] = &totals{
+			closed:    t.Closed,
+			blocks:    t.Blocks,
+			maxBlocks: t.MaxBlocks,
+			duration:  t.Duration,
+			peak:      t.Peak,
+			maxPeak:   t.MaxPeak,
+			profit:    t.Profit,
+			decay:     t.Decay,
+			closedBy:  closedBy,
+		}
+	}
+	s.recent = cp.Recent
+}
+
+func (s *Store) add(e domain.ArbitrageEvent) {
+	o := e.Opportunity
+	k := pairVenue{symbol: e.Data.Symbol, venue: e.Data.Venue}
+	t, ok := s.totals[k]
+	if !ok {
+		t = &totals{closedBy: make(map[string]int)}
+		s.totals[k] = t
+	}
+	t.closed++
+	t.blocks += o.Blocks
+	t.maxBlocks = max(t.maxBlocks, o.Blocks)
+	t.duration += o.Duration()
+	t.peak += o.PeakProfit
+	if t.closed == 1 || o.PeakProfit > t.maxPeak {
+		t.maxPeak = o.PeakProfit
+	}
+	t.profit += o.AvgProfit
+	t.decay += o.DecayPerBlock
+	t.closedBy[o.ClosedBy]++
+
+	s.recent = append(s.recent, e)
+	if len(s.recent) > MaxRecent {
+		s.recent = slices.Delete(s.recent, 0, len(s.recent)-MaxRecent)
+	}
+}
+
+// OpportunitySummaries returns the statistics of each pair and venue, sorted
+// by symbol then venue.
+func (s *Store) OpportunitySummaries() []domain.OpportunitySummary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]domain.OpportunitySummary, 0, len(s.totals))
+	for k, t := range s.totals {
+		n := float64(t.closed)
+		out = append(out, domain.OpportunitySummary{
+			Symbol:           k.symbol,
+			Venue:            k.venue,
+			Closed:           t.closed,
+			AvgBlocks:        float64(t.blocks) / n,
+			MaxBlocks:        t.maxBlocks,
+			AvgDuration:      t.duration / time.Duration(t.closed),
+			AvgPeakProfit:    t.peak / n,
+			MaxPeakProfit:    t.maxPeak,
+			AvgProfit:        t.profit / n,
+			AvgDecayPerBlock: t.decay / n,
+			ClosedBy:         maps.Clone(t.closedBy),
+		})
+	}
+	slices.SortFunc(out, func(a, b domain.OpportunitySummary) int {
+		if c := strings.Compare(a.Symbol, b.Symbol); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Venue, b.Venue)
+	})
+	return out
+}
+
+// RecentOpportunities returns up to limit of the latest closes, newest first.
+func (s *Store) RecentOpportunities(limit int) []domain.ArbitrageEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	limit = max(0, min(limit, len(s.recent)))
+	out := make([]domain.ArbitrageEvent, 0, limit)
+	for i := len(s.recent) - 1; i >= len(s.recent)-limit; i-- {
+		out = append(out, s.recent[i])
+	}
+	return out
+}
+
+// Close writes the queued closes and closes the history file.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	writes := s.writes
+	s.writes = nil
+	s.mu.Unlock()
+	if writes == nil {
+		return nil
+	}
+
+	close(writes)
+	<-s.done
+	return s.closeErr
+}
@@ -0,0 +1,149 @@
+package analytics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func closedEvent(venue, id string, blocks uint64, peak float64, closedBy string) domain.ArbitrageEvent {
+	opened := time.Unix(1_700_000_000, 0)
+	return domain.ArbitrageEvent{
+		Type:        domain.EventOpportunityClosed,
+		BlockNumber: 100 + blocks,
+		Timestamp:   opened.Add(time.Duration(blocks) * 12 * time.Second),
+		Data:        &domain.TradeData{Symbol: "ETHUSDC", Venue: venue, EstimatedProfit: 20},
+		Opportunity: &domain.Opportunity{
+			ID:            id,
+			OpenedBlock:   100,
+			OpenedAt:      opened,
+			Blocks:        blocks,
+			PeakProfit:    peak,
+			AvgProfit:     peak / 2,
+			ClosedAt:      opened.Add(time.Duration(blocks) * 12 * time.Second),
+			DecayPerBlock: 10,
+			ClosedBy:      closedBy,
+		},
+	}
+}
+
+func TestStore_Summaries(t *testing.T) {
+	s, err := NewStore("")
+	require.NoError(t, err)
+
+	s.Broadcast(closedEvent("kraken", "a", 1, 30, domain.ClosedByCEX))
+	s.Broadcast(closedEvent("binance", "b", 2, 40, domain.ClosedByDEX))
+	s.Broadcast(closedEvent("binance", "c", 4, 80, domain.ClosedByDEX))
+	opened := closedEvent("binance", "d", 1, 10, "")
+	opened.Type = domain.EventOpportunityOpened
+	s.Broadcast(opened)
+
+	sums := s.OpportunitySummaries()
+	require.Len(t, sums, 2)
+	assert.Equal(t, "binance", sums[0].Venue)
+	assert.Equal(t, domain.OpportunitySummary{
+		Symbol:           "ETHUSDC",
+		Venue:            "binance",
+		Closed:           2,
+		AvgBlocks:        3,
+		MaxBlocks:        4,
+		AvgDuration:      36 * time.Second,
+		AvgPeakProfit:    60,
+		MaxPeakProfit:    80,
+		AvgProfit:        30,
+		AvgDecayPerBlock: 10,
+		ClosedBy:         map[string]int{domain.ClosedByDEX: 2},
+	}, sums[0])
+
+	recent := s.RecentOpportunities(2)
+	require.Len(t, recent, 2)
+	assert.Equal(t, "c", recent[0].Opportunity.ID, "newest first")
+	assert.Equal(t, "b", recent[1].Opportunity.ID)
+	assert.Len(t, s.RecentOpportunities(10), 3)
+	assert.Empty(t, s.RecentOpportunities(-1))
+}
+
+func TestStore_PersistsAndReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "opportunities.jsonl")
+
+	s, err := NewStore(path)
+	require.NoError(t, err)
+	s.Broadcast(closedEvent("binance", "a", 2, 40, domain.ClosedByDEX))
+	s.Broadcast(closedEvent("binance", "b", 4, 80, domain.ClosedByGas))
+	require.NoError(t, s.Close())
+
+	// A line cut short by a crash is skipped.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"type":"OPPORTUNITY_CLOSED","blockNum`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	s, err = NewStore(path)
+	require.NoError(t, err)
+	sums := s.OpportunitySummaries()
+	require.Len(t, sums, 1)
+	assert.Equal(t, 2, sums[0].Closed)
+	assert.Equal(t, 36*time.Second, sums[0].AvgDuration)
+	assert.Equal(t, map[string]int{domain.ClosedByDEX: 1, domain.ClosedByGas: 1}, sums[0].ClosedBy)
+
+	recent := s.RecentOpportunities(MaxRecent)
+	require.Len(t, recent, 2)
+	assert.Equal(t, "b", recent[0].Opportunity.ID)
+	assert.Equal(t, 48*time.Second, recent[0].Opportunity.Duration())
+
+	// Closes appended after the torn line are kept.
+	s.Broadcast(closedEvent("binance", "c", 1, 10, domain.ClosedByCEX))
+	require.NoError(t, s.Close())
+	s, err = NewStore(path)
+	require.NoError(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+	assert.Equal(t, 3, s.OpportunitySummaries()[0].Closed)
+}
+
+func TestStore_CompactsHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "opportunities.jsonl")
+	lines := func() []string {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	}
+
+	s, err := newStore(path, 3)
+	require.NoError(t, err)
+	for i, id := range []string{"a", "b", "c", "d", "e"} {
+		s.Broadcast(closedEvent("binance", id, uint64(i+1), float64(10*(i+1)), domain.ClosedByDEX))
+	}
+	sums := s.OpportunitySummaries()
+	require.NoError(t, s.Close())
+
+	got := lines()
+	require.Len(t, got, 2, "the fourth close is written as a checkpoint replacing the first three")
+	assert.True(t, strings.HasPrefix(got[0], `{"checkpoint":`))
+
+	s, err = newStore(path, 3)
+	require.NoError(t, err)
+	assert.Equal(t, sums, s.OpportunitySummaries())
+	recent := s.RecentOpportunities(MaxRecent)
+	require.Len(t, recent, 5)
+	assert.Equal(t, "e", recent[0].Opportunity.ID)
+	assert.Equal(t, "a", recent[4].Opportunity.ID)
+	require.NoError(t, s.Close())
+
+	// A history with too many closes since its checkpoint is compacted on open.
+	s, err = newStore(path, 1)
+	require.NoError(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+	assert.Len(t, lines(), 1)
+	assert.Equal(t, sums, s.OpportunitySummaries())
+}
@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Scopes granted by tokens. Admin implies read.
+const (
+	ScopeRead  = "read"
+	ScopeAdmin = "admin"
+)
+
+var (
+	ErrNoToken      = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+type Config struct {
+	// AllowedOrigins lists the browser origins that may call the API, such
+	// as https://dashboard.example.com. Empty allows same-origin requests
+	// only; "*" allows any origin.
+	AllowedOrigins []string
+	// JWTSecret verifies HS256 tokens. Their scope claim grants access.
+	JWTSecret []byte
+	// ReadTokens and AdminTokens are static bearer tokens.
+	ReadTokens  []string
+	AdminTokens []string
+}
+
+// Principal is an authenticated caller.
+type Principal struct {
+	Subject string
+	Scopes  []string
+}
+
+// Allows reports whether p was granted scope.
+func (p *Principal) Allows(scope string) bool {
+	return slices.Contains(p.Scopes, ScopeAdmin) || slices.Contains(p.Scopes, scope)
+}
+
+// anonymous is the principal of every request when authentication is off.
+var anonymous = &Principal{Subject: "anonymous", Scopes: []string{ScopeAdmin}}
+
+// Authenticator checks request origins and bearer tokens. Tokens are
+// verified offline: static tokens by comparison, JWTs with the shared secret.
+type Authenticator struct {
+	origins   []string
+	anyOrigin bool
+	secret    []byte
+	tokens    []staticToken
+	now       func() time.Time
+}
+
+type staticToken struct {
+	token string
+	scope string
+}
+
+func New(cfg Config) *Authenticator {
+	a := &Authenticator{secret: cfg.JWTSecret, now: time.Now}
+	a.anyOrigin = slices.Contains(cfg.AllowedOrigins, "*")
+	if !a.anyOrigin {
+		a.origins = cfg.AllowedOrigins
+	}
+	for _, t := range cfg.ReadTokens {
+		a.tokens = append(a.tokens, staticToken{token: t, scope: ScopeRead})
+	}
+	for _, t := range cfg.AdminTokens {
+		a.tokens = append(a.tokens, staticToken{token: t, scope: ScopeAdmin})
+	}
+	return a
+}
+
+// Enabled reports whether any credentials are configured. Without them every
+// request is treated as an admin.
+func (a *Authenticator) Enabled() bool {
+	return len(a.secret) > 0 || len(a.tokens) > 0
+}
+
+// CheckOrigin allows requests without an Origin header, which come from
+// non-browser clients, same-origin browser requests, and browser requests
+// from an allowed origin.
+func (a *Authenticator) CheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || sameOrigin(origin, r.Host) || a.originAllowed(origin)
+}
+
+func (a *Authenticator) originAllowed(origin string) bool {
+	return a.anyOrigin || slices.ContainsFunc(a.origins, func(o string) bool { return strings.EqualFold(o, origin) })
+}
+
+// sameOrigin reports whether origin is the host the request was sent to.
+func sameOrigin(origin, host string) bool {
+	u, err := url.Parse(origin)
+	return err == nil && u.Host != "" && strings.EqualFold(u.Host, host)
+}
+
+// Authenticate returns the caller of r. The token is read from the
+// Authorization header, or from the access_token query parameter since
+// browsers cannot set headers on WebSocket handshakes.
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if !a.Enabled() {
+		return anonymous, nil
+	}
+
+	token := r.URL.Query().Get("access_token")
+	if h := r.Header.Get("Authorization"); h != "" {
+		scheme, value, ok := strings.Cut(h, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return nil, ErrNoToken
+		}
+		token = strings.TrimSpace(value)
+	}
+	return a.AuthenticateToken(token)
+}
+
+// AuthenticateToken returns the caller presenting token, for transports
+// other than HTTP.
+func (a *Authenticator) AuthenticateToken(token string) (*Principal, error) {
+	if !a.Enabled() {
+		return anonymous, nil
+	}
+	if token == "" {
+		return nil, ErrNoToken
+	}
+
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t.token)) == 1 {
+			return &Principal{Subject: "token:" + t.scope, Scopes: []string{t.scope}}, nil
+		}
+	}
+	if len(a.secret) > 0 && strings.Count(token, ".") == 2 {
+		return verifyHS256(token, a.secret, a.now())
+	}
+	return nil, ErrInvalidToken
+}
+
+type principalKey struct{}
+
+// NewContext returns ctx carrying p.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored by Require.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// Require rejects requests from disallowed origins, without valid
+// credentials, or lacking scope.
+func (a *Authenticator) Require(scope string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.CheckOrigin(r) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		p, err := a.Authenticate(r)
+		if err != nil {
+			slog.Debug("Rejected unauthenticated request", "path", r.URL.Path, "error", err)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="arbitrage"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !p.Allows(scope) {
+			http.Error(w, "insufficient scope", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p)))
+	})
+}
+
+// CORS answers preflight requests and allows cross-origin reads from the
+// allowed origins only.
+func (a *Authenticator) CORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" && a.originAllowed(origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS, PUT, DELETE")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Last-Event-ID")
+		}
+		w.Header().Add("Vary", "Origin")
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+func sign(t *testing.T, alg string, key []byte, claims map[string]any) string {
+	t.Helper()
+	enc := func(v any) string {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	unsigned := enc(map[string]string{"alg": alg, "typ": "JWT"}) + "." + enc(claims)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func request(token string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r
+}
+
+func TestAuthenticate_JWT(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	a := New(Config{JWTSecret: secret})
+	a.now = func() time.Time { return now }
+
+	valid := sign(t, "HS256", secret, map[string]any{"sub": "dashboard", "scope": "read", "exp": now.Add(time.Hour).Unix()})
+	p, err := a.Authenticate(request(valid))
+	require.NoError(t, err)
+	assert.Equal(t, "dashboard", p.Subject)
+	assert.True(t, p.Allows(ScopeRead))
+	assert.False(t, p.Allows(ScopeAdmin))
+
+	admin := sign(t, "HS256", secret, map[string]any{"sub": "ops", "scope": "admin", "exp": now.Add(time.Hour).Unix()})
+	p, err = a.Authenticate(request(admin))
+	require.NoError(t, err)
+	assert.True(t, p.Allows(ScopeRead), "admin implies read")
+
+	tests := map[string]string{
+		"expired":       sign(t, "HS256", secret, map[string]any{"scope": "read", "exp": now.Add(-time.Minute).Unix()}),
+		"not yet valid": sign(t, "HS256", secret, map[string]any{"scope": "read", "exp": now.Add(time.Hour).Unix(), "nbf": now.Add(time.Minute).Unix()}),
+		"no expiry":     sign(t, "HS256", secret, map[string]any{"scope": "read"}),
+		"wrong key":     sign(t, "HS256", []byte("another-secret-another-secret-xx"), map[string]any{"scope": "read", "exp": now.Add(time.Hour).Unix()}),
+		"alg none":      sign(t, "none", secret, map[string]any{"scope": "read", "exp": now.Add(time.Hour).Unix()}),
+		"malformed":     "a.b.c",
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := a.Authenticate(request(token))
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+
+	_, err = a.Authenticate(request(""))
+	assert.ErrorIs(t, err, ErrNoToken)
+}
+
+func TestAuthenticate_StaticTokens(t *testing.T) {
+	a := New(Config{ReadTokens: []string{"reader-token-0001"}, AdminTokens: []string{"admin-token-00001"}})
+
+	p, err := a.Authenticate(request("reader-token-0001"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{ScopeRead}, p.Scopes)
+
+	r := httptest.NewRequest(http.MethodGet, "/ws?access_token=admin-token-00001", nil)
+	p, err = a.Authenticate(r)
+	require.NoError(t, err)
+	assert.True(t, p.Allows(ScopeAdmin))
+
+	_, err = a.Authenticate(request("unknown-token"))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	r = request("")
+	r.Header.Set("Authorization", "Basic cmVhZGVyOnB3")
+	_, err = a.Authenticate(r)
+	assert.ErrorIs(t, err, ErrNoToken)
+}
+
+func TestAuthenticate_Disabled(t *testing.T) {
+	a := New(Config{})
+	assert.False(t, a.Enabled())
+
+	p, err := a.Authenticate(request(""))
+	require.NoError(t, err)
+	assert.True(t, p.Allows(ScopeAdmin))
+}
+
+func TestCheckOrigin(t *testing.T) {
+	withOrigin := func(origin string) *http.Request {
+		r := request("")
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		return r
+	}
+
+	a := New(Config{AllowedOrigins: []string{"https://dash.example.com"}})
+	assert.True(t, a.CheckOrigin(withOrigin("https://dash.example.com")))
+	assert.True(t, a.CheckOrigin(withOrigin("HTTPS://DASH.EXAMPLE.COM")))
+	assert.False(t, a.CheckOrigin(withOrigin("https://evil.example.com")))
+	assert.True(t, a.CheckOrigin(withOrigin("")), "non-browser clients send no Origin")
+
+	assert.False(t, New(Config{}).CheckOrigin(withOrigin("https://evil.example.com")), "no allowlist means same-origin only")
+	assert.True(t, New(Config{}).CheckOrigin(withOrigin("http://example.com")))
+	assert.True(t, New(Config{}).CheckOrigin(withOrigin("")))
+	assert.True(t, New(Config{AllowedOrigins: []string{"*"}}).CheckOrigin(withOrigin("https://evil.example.com")))
+}
+
+func TestRequire(t *testing.T) {
+	a := New(Config{
+		AllowedOrigins: []string{"https://dash.example.com"},
+		ReadTokens:     []string{"reader-token-0001"},
+	})
+	var got *Principal
+	h := a.CORS(a.Require(ScopeAdmin, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+	})))
+
+	serve := func(token, origin string) *httptest.ResponseRecorder {
+		r := request(token)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve("", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer")
+
+	assert.Equal(t, http.StatusForbidden, serve("reader-token-0001", "").Code, "read scope cannot reach admin endpoints")
+	assert.Equal(t, http.StatusForbidden, serve("reader-token-0001", "https://evil.example.com").Code)
+	assert.Empty(t, serve("", "https://evil.example.com").Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "https://dash.example.com", serve("", "https://dash.example.com").Header().Get("Access-Control-Allow-Origin"))
+
+	h = a.Require(ScopeRead, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+	}))
+	w = serve("reader-token-0001", "https://dash.example.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, got)
+	assert.Equal(t, "token:read", got.Subject)
+}
@@ -2,44 +2,53 @@ package binance
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"time"
 
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/clock"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/httpclient"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/instruments"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/orderbook"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/ports"
-	"github.com/shopspring/decimal"
-	"github.com/sony/gobreaker"
-	"golang.org/x/time/rate"
 )
 
+const defaultDepth = 100
+
+// depthTiers are the steps adaptive depth moves through. Above 100 levels
+// each step costs more request weight; see requestWeight.
+var depthTiers = []int{5, 10, 20, 50, 100, 500, 1000, 5000}
+
 type Adapter struct {
-	client  *http.Client
-	cb      *gobreaker.CircuitBreaker
-	limiter *rate.Limiter
+	client  *httpclient.Client
 	baseURL string
+	weights *weightBudget
+	depth   *orderbook.DepthLimit
+	clock   *clock.Offset
+
+	instruments *instruments.Registry
 }
 
 func NewAdapter(baseURL string) ports.ExchangeAdapter {
-	settings := gobreaker.Settings{
-		Name:        "Binance",
-		MaxRequests: 1,
-		Interval:    0,
-		Timeout:     30 * time.Second,
-		ReadyToTrip: func(counts gobreaker.Counts) bool {
-			return counts.ConsecutiveFailures > 3
-		},
-	}
+	return newAdapter(baseURL)
+}
+
+func newAdapter(baseURL string) *Adapter {
+	weights := newWeightBudget()
+
+	cfg := httpclient.DefaultConfig("binance")
+	cfg.RateLimit = 20 // 20 req/s, burst 5
+	cfg.Budget = weights
 
-	return &Adapter{
-		client: &http.Client{
-			Timeout: 5 * time.Second,
-		},
-		cb:      gobreaker.NewCircuitBreaker(settings),
-		limiter: rate.NewLimiter(rate.Limit(20), 5), // 20 req/s, burst 5
+	a := &Adapter{
+		client:  httpclient.New(cfg),
 		baseURL: baseURL,
+		weights: weights,
+		depth:   orderbook.NewDepthLimit("binance", defaultDepth, depthTiers...),
 	}
+	a.clock = clock.NewOffset("binance", a.serverTime)
+	a.instruments = instruments.NewRegistry("binance", instruments.DefaultTTL, a.loadInstruments)
+	return a
 }
 
 type depthResponse struct {
@@ -49,60 +58,74 @@ type depthResponse struct {
 }
 
 func (a *Adapter) GetOrderBook(ctx context.Context, symbol string) (*domain.OrderBook, error) {
-	if err := a.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter wait failed: %w", err)
+	inst, err := a.instruments.Lookup(ctx, symbol)
+	if err != nil {
+		return nil, err
 	}
 
-	body, err := a.cb.Execute(func() (interface{}, error) {
-		url := fmt.Sprintf("%s/depth?symbol=%s&limit=100", a.baseURL, symbol)
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-
-		resp, err := a.client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute request: %w", err)
-		}
-		defer func() {
-			_ = resp.Body.Close()
-		}()
-
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("binance api returned status: %d", resp.StatusCode)
-		}
-
-		var depth depthResponse
-		if err := json.NewDecoder(resp.Body).Decode(&depth); err != nil {
-			return nil, fmt.Errorf("failed to decode response: %w", err)
-		}
-		fmt.Printf("DEBUG: Binance LastUpdateID: %d\n", depth.LastUpdateID)
-		return depth, nil
-	})
-
+	depth, err := a.fetchDepth(ctx, inst.VenueSymbol, a.depth.Levels())
 	if err != nil {
 		return nil, err
 	}
 
-	depth := body.(depthResponse)
+	bids, err := orderbook.ParseLevels(depth.Bids)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bids: %w", err)
+	}
+	asks, err := orderbook.ParseLevels(depth.Asks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asks: %w", err)
+	}
 
 	ob := &domain.OrderBook{
+		Bids:      bids,
+		Asks:      asks,
 		Timestamp: time.Now(),
-		Bids:      make([]domain.PriceLevel, 0, len(depth.Bids)),
-		Asks:      make([]domain.PriceLevel, 0, len(depth.Asks)),
 	}
+	return ob, nil
+}
+
+// SetBookDepth sets the configured number of levels fetched per side.
+func (a *Adapter) SetBookDepth(levels int) { a.depth.Configure(levels) }
+
+// DeepenBook fetches the next depth tier from now on, up to the 5000 levels Binance serves.
+func (a *Adapter) DeepenBook() bool { return a.depth.Deepen() }
+
+// BookFilled lets a deepened depth relax back toward the configured one.
+func (a *Adapter) BookFilled() { a.depth.Filled() }
+
+// SyncClock re-estimates the offset to Binance's server clock.
+func (a *Adapter) SyncClock(ctx context.Context) error {
+	return a.clock.Sync(ctx)
+}
 
-	for _, b := range depth.Bids {
-		price, _ := decimal.NewFromString(b[0])
-		amount, _ := decimal.NewFromString(b[1])
-		ob.Bids = append(ob.Bids, domain.PriceLevel{Price: price, Amount: amount})
+func (a *Adapter) serverTime(ctx context.Context) (time.Time, error) {
+	var resp struct {
+		ServerTime int64 `json:"serverTime"`
 	}
+	if err := a.client.GetJSON(ctx, a.baseURL+"/time", &resp); err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(resp.ServerTime), nil
+}
+
+// fetchDepth fetches limit levels per side of the book of a Binance symbol.
+func (a *Adapter) fetchDepth(ctx context.Context, venueSymbol string, limit int) (depthResponse, error) {
+	limit = a.depthLimit(limit)
 
-	for _, a := range depth.Asks {
-		price, _ := decimal.NewFromString(a[0])
-		amount, _ := decimal.NewFromString(a[1])
-		ob.Asks = append(ob.Asks, domain.PriceLevel{Price: price, Amount: amount})
+	var depth depthResponse
+	url := fmt.Sprintf("%s/depth?symbol=%s&limit=%d", a.baseURL, venueSymbol, limit)
+	if err := a.client.GetJSON(ctx, url, &depth); err != nil {
+		return depthResponse{}, err
 	}
+	return depth, nil
+}
 
-	return ob, nil
+// depthLimit returns limit, or the cheapest depth tier when the request
+// would leave the weight budget running low.
+func (a *Adapter) depthLimit(limit int) int {
+	if limit > cheapDepthLimit && a.weights.Low(depthWeight(limit)) {
+		return cheapDepthLimit
+	}
+	return limit
 }
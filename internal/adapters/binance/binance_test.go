@@ -7,13 +7,38 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+const exchangeInfoResponseJSON = `{
+	"rateLimits": [{"rateLimitType": "REQUEST_WEIGHT", "interval": "MINUTE", "intervalNum": 1, "limit": 6000}],
+	"symbols": [
+		{"symbol": "ETHUSDC", "status": "TRADING", "baseAsset": "ETH", "quoteAsset": "USDC", "filters": [
+			{"filterType": "PRICE_FILTER", "tickSize": "0.01000000"},
+			{"filterType": "LOT_SIZE", "stepSize": "0.00010000", "minQty": "0.00010000"}
+		]},
+		{"symbol": "BTCUSDC", "status": "BREAK", "baseAsset": "BTC", "quoteAsset": "USDC", "filters": []}
+	]
+}`
+
+// withExchangeInfo serves exchangeInfoResponseJSON and passes every other
+// request to next.
+func withExchangeInfo(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/exchangeInfo" {
+			_, _ = fmt.Fprintln(w, exchangeInfoResponseJSON)
+			return
+		}
+		next(w, r)
+	}
+}
+
 func TestGetOrderBook(t *testing.T) {
 	// Mock Server
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	ts := httptest.NewServer(withExchangeInfo(func(w http.ResponseWriter, r *http.Request) {
 		// Verify Request
 		if r.URL.Path != "/depth" {
 			t.Errorf("Expected path /depth, got %s", r.URL.Path)
@@ -62,7 +87,7 @@ func TestGetOrderBook(t *testing.T) {
 }
 
 func TestGetOrderBook_Error(t *testing.T) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	ts := httptest.NewServer(withExchangeInfo(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 	}))
 	defer ts.Close()
@@ -73,3 +98,50 @@ func TestGetOrderBook_Error(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "binance api returned status: 500")
 }
+
+func TestGetOrderBook_NotListed(t *testing.T) {
+	var depthCalls int
+	ts := httptest.NewServer(withExchangeInfo(func(w http.ResponseWriter, r *http.Request) {
+		depthCalls++
+	}))
+	defer ts.Close()
+
+	adapter := NewAdapter(ts.URL)
+	for _, symbol := range []string{"ETHUSDT", "BTCUSDC"} {
+		_, err := adapter.GetOrderBook(context.Background(), symbol)
+		assert.ErrorIs(t, err, domain.ErrInstrumentNotListed, symbol)
+	}
+	assert.Zero(t, depthCalls)
+}
+
+func TestGetOrderBook_MalformedLevel(t *testing.T) {
+	ts := httptest.NewServer(withExchangeInfo(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, `{"lastUpdateId": 1, "bids": [["4.00000000", "n/a"]], "asks": []}`)
+	}))
+	defer ts.Close()
+
+	adapter := NewAdapter(ts.URL)
+	_, err := adapter.GetOrderBook(context.Background(), "ETHUSDC")
+
+	assert.ErrorIs(t, err, domain.ErrMalformedBook)
+}
+
+func TestGetOrderBook_AdaptiveDepth(t *testing.T) {
+	var limits []string
+	ts := httptest.NewServer(withExchangeInfo(func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Query().Get("limit"))
+		_, _ = fmt.Fprintln(w, `{"lastUpdateId": 1, "bids": [], "asks": []}`)
+	}))
+	defer ts.Close()
+
+	adapter := newAdapter(ts.URL)
+	adapter.SetBookDepth(50)
+	_, err := adapter.GetOrderBook(context.Background(), "ETHUSDC")
+	require.NoError(t, err)
+
+	require.True(t, adapter.DeepenBook())
+	_, err = adapter.GetOrderBook(context.Background(), "ETHUSDC")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"50", "100"}, limits)
+}
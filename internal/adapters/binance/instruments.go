@@ -0,0 +1,86 @@
+package binance
+
+import (
+	"context"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+)
+
+type exchangeInfoResponse struct {
+	RateLimits []struct {
+		RateLimitType string `json:"rateLimitType"`
+		Interval      string `json:"interval"`
+		IntervalNum   int    `json:"intervalNum"`
+		Limit         int    `json:"limit"`
+	} `json:"rateLimits"`
+	Symbols []struct {
+		Symbol     string           `json:"symbol"`
+		Status     string           `json:"status"`
+		BaseAsset  string           `json:"baseAsset"`
+		QuoteAsset string           `json:"quoteAsset"`
+		Filters    []map[string]any `json:"filters"`
+	} `json:"symbols"`
+}
+
+// GetInstrument returns the exchangeInfo metadata for symbol.
+func (a *Adapter) GetInstrument(ctx context.Context, symbol string) (*domain.Instrument, error) {
+	return a.instruments.Lookup(ctx, symbol)
+}
+
+// loadInstruments fetches /exchangeInfo, which also carries the request
+// weight limit for the budget.
+func (a *Adapter) loadInstruments(ctx context.Context) ([]domain.Instrument, error) {
+	var info exchangeInfoResponse
+	if err := a.client.GetJSON(ctx, a.baseURL+"/exchangeInfo?permissions=SPOT", &info); err != nil {
+		return nil, err
+	}
+
+	for _, rl := range info.RateLimits {
+		if rl.RateLimitType == "REQUEST_WEIGHT" && rl.Interval == "MINUTE" && rl.IntervalNum == 1 {
+			a.weights.setLimit(rl.Limit)
+		}
+	}
+
+	list := make([]domain.Instrument, 0, len(info.Symbols))
+	for _, s := range info.Symbols {
+		if s.Status != "TRADING" {
+			continue
+		}
+
+		inst := domain.Instrument{
+			Symbol:      domain.CanonicalSymbol(s.BaseAsset, s.QuoteAsset),
+			VenueSymbol: s.Symbol,
+			Base:        s.BaseAsset,
+			Quote:       s.QuoteAsset,
+		}
+		for _, f := range s.Filters {
+			switch f["filterType"] {
+			case "PRICE_FILTER":
+				inst.TickSize = filterValue(f, "tickSize")
+			case "LOT_SIZE":
+				inst.LotSize = filterValue(f, "stepSize")
+				inst.MinQty = filterValue(f, "minQty")
+			case "NOTIONAL", "MIN_NOTIONAL":
+				inst.MinNotional = filterValue(f, "minNotional")
+			}
+		}
+		list = append(list, inst)
+	}
+	return list, nil
+}
+
+// filterValue reads a decimal filter field. Filters mix string decimals with
+// numeric and boolean fields, so anything that is not a decimal string is
+// treated as unset.
+func filterValue(f map[string]any, key string) decimal.Decimal {
+	s, ok := f[key].(string)
+	if !ok {
+		return decimal.Zero
+	}
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
@@ -0,0 +1,366 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/orderbook"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// WSURL is the public spot stream endpoint, the default for
+	// venues.binance.ws_url.
+	WSURL = "wss://stream.binance.com:9443/ws"
+
+	streamSnapshotLimit = 1000
+	streamReadTimeout   = 30 * time.Second
+	streamSyncTimeout   = 5 * time.Second
+	streamMaxBackoff    = 30 * time.Second
+)
+
+var errDepthGap = errors.New("depth stream gap detected")
+
+// StreamAdapter serves order books from local copies kept in sync with the
+// Binance diff-depth stream (<symbol>@depth@100ms). Each symbol is synced with
+// a REST snapshot using lastUpdateId as described in Binance's "How to manage
+// a local order book correctly", and resynced whenever a gap is detected.
+type StreamAdapter struct {
+	wsURL  string
+	rest   *Adapter
+	dialer *websocket.Dialer
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	books map[string]*localBook
+}
+
+func NewStreamAdapter(wsURL, restURL string) *StreamAdapter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &StreamAdapter{
+		wsURL:  strings.TrimSuffix(wsURL, "/"),
+		rest:   newAdapter(restURL),
+		dialer: websocket.DefaultDialer,
+		ctx:    ctx,
+		cancel: cancel,
+		books:  make(map[string]*localBook),
+	}
+}
+
+// GetOrderBook returns a copy of the in-memory book for symbol. The first call
+// for a symbol starts its stream; until the book is synced it falls back to
+// the REST depth endpoint.
+func (s *StreamAdapter) GetOrderBook(ctx context.Context, symbol string) (*domain.OrderBook, error) {
+	book := s.book(symbol)
+
+	timer := time.NewTimer(streamSyncTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-book.ready:
+	case <-timer.C:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if ob, ok := book.orderBook(); ok {
+		ob.ExchangeTime = s.rest.clock.ToLocal(ob.ExchangeTime)
+		return ob, nil
+	}
+
+	slog.Warn("Binance depth stream not synced, falling back to REST", "symbol", symbol)
+	return s.rest.GetOrderBook(ctx, symbol)
+}
+
+// GetInstrument returns the exchangeInfo metadata for symbol.
+func (s *StreamAdapter) GetInstrument(ctx context.Context, symbol string) (*domain.Instrument, error) {
+	return s.rest.GetInstrument(ctx, symbol)
+}
+
+// SyncClock re-estimates the offset to Binance's server clock, which is
+// applied to depth event times.
+func (s *StreamAdapter) SyncClock(ctx context.Context) error {
+	return s.rest.SyncClock(ctx)
+}
+
+// SetBookDepth sets the depth fetched while the stream is not synced. The
+// streamed book is built from a 1000-level snapshot and every diff, so the
+// depth only applies to the REST fallback.
+func (s *StreamAdapter) SetBookDepth(levels int) { s.rest.SetBookDepth(levels) }
+
+// DeepenBook deepens the REST fallback.
+func (s *StreamAdapter) DeepenBook() bool { return s.rest.DeepenBook() }
+
+// BookFilled lets a deepened REST fallback relax toward the configured depth.
+func (s *StreamAdapter) BookFilled() { s.rest.BookFilled() }
+
+// Close stops all depth streams.
+func (s *StreamAdapter) Close() error {
+	s.cancel()
+	return nil
+}
+
+func (s *StreamAdapter) book(symbol string) *localBook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	book, ok := s.books[symbol]
+	if !ok {
+		book = newLocalBook()
+		s.books[symbol] = book
+		go s.run(symbol, book)
+	}
+	return book
+}
+
+func (s *StreamAdapter) run(symbol string, book *localBook) {
+	backoff := time.Second
+
+	for {
+		synced, err := s.stream(symbol, book)
+		book.invalidate()
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		if synced {
+			backoff = time.Second
+		}
+		slog.Warn("Binance depth stream interrupted, resyncing", "symbol", symbol, "error", err, "backoff", backoff)
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > streamMaxBackoff {
+			backoff = streamMaxBackoff
+		}
+	}
+}
+
+type depthEvent struct {
+	EventType     string     `json:"e"`
+	EventTime     int64      `json:"E"`
+	Symbol        string     `json:"s"`
+	FirstUpdateID int64      `json:"U"`
+	FinalUpdateID int64      `json:"u"`
+	Bids          [][]string `json:"b"`
+	Asks          [][]string `json:"a"`
+}
+
+// stream runs a single connection until it fails. It reports whether the book
+// reached a synced state so the caller can reset its backoff.
+func (s *StreamAdapter) stream(symbol string, book *localBook) (bool, error) {
+	inst, err := s.rest.GetInstrument(s.ctx, symbol)
+	if err != nil {
+		return false, err
+	}
+
+	url := fmt.Sprintf("%s/%s@depth@100ms", s.wsURL, strings.ToLower(inst.VenueSymbol))
+	conn, _, err := s.dialer.DialContext(s.ctx, url, nil)
+	if err != nil {
+		return false, fmt.Errorf("dial failed: %w", err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-s.ctx.Done():
+		case <-done:
+		}
+		_ = conn.Close()
+	}()
+
+	// Events are buffered while the snapshot is fetched, as required by the
+	// sync procedure.
+	events := make(chan depthEvent, 1024)
+	errc := make(chan error, 1)
+	// Binance pings every 20 seconds. A ping is queued as an empty event
+	// behind the updates read before it, confirming the book once they are
+	// applied.
+	conn.SetPingHandler(func(data string) error {
+		_ = conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		select {
+		case events <- depthEvent{}:
+		case <-done:
+		}
+		_ = conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		return nil
+	})
+	go func() {
+		for {
+			_ = conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+			var ev depthEvent
+			if err := conn.ReadJSON(&ev); err != nil {
+				errc <- fmt.Errorf("read failed: %w", err)
+				return
+			}
+			select {
+			case events <- ev:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	snapshot, err := s.rest.fetchDepth(s.ctx, inst.VenueSymbol, streamSnapshotLimit)
+	if err != nil {
+		return false, fmt.Errorf("snapshot failed: %w", err)
+	}
+	if err := book.reset(snapshot); err != nil {
+		return false, fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return book.isSynced(), s.ctx.Err()
+		case err := <-errc:
+			return book.isSynced(), err
+		case ev := <-events:
+			if ev.EventType == "" {
+				book.confirm()
+				continue
+			}
+			if err := book.apply(ev); err != nil {
+				return book.isSynced(), err
+			}
+		}
+	}
+}
+
+type localBook struct {
+	mu           sync.RWMutex
+	book         *orderbook.Book
+	lastUpdateID int64
+	updatedAt    time.Time
+	eventTime    time.Time
+	liveAt       time.Time
+	synced       bool
+
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+func newLocalBook() *localBook {
+	return &localBook{
+		book:  orderbook.New(),
+		ready: make(chan struct{}),
+	}
+}
+
+// reset replaces the book with a REST snapshot. The book only becomes synced
+// once the first bridging event has been applied.
+func (b *localBook) reset(snapshot depthResponse) error {
+	bids, err := orderbook.ParseLevels(snapshot.Bids)
+	if err != nil {
+		return err
+	}
+	asks, err := orderbook.ParseLevels(snapshot.Asks)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.book.Clear()
+	b.book.UpdateBids(bids)
+	b.book.UpdateAsks(asks)
+	b.lastUpdateID = snapshot.LastUpdateID
+	b.updatedAt = time.Now()
+	b.eventTime = time.Time{}
+	b.synced = false
+	return nil
+}
+
+func (b *localBook) apply(ev depthEvent) error {
+	bids, err := orderbook.ParseLevels(ev.Bids)
+	if err != nil {
+		return err
+	}
+	asks, err := orderbook.ParseLevels(ev.Asks)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ev.FinalUpdateID <= b.lastUpdateID {
+		return nil
+	}
+
+	if b.synced {
+		if ev.FirstUpdateID != b.lastUpdateID+1 {
+			return fmt.Errorf("%w: expected U=%d, got %d", errDepthGap, b.lastUpdateID+1, ev.FirstUpdateID)
+		}
+	} else if ev.FirstUpdateID > b.lastUpdateID+1 {
+		return fmt.Errorf("%w: first event U=%d is past snapshot %d", errDepthGap, ev.FirstUpdateID, b.lastUpdateID)
+	}
+
+	b.book.UpdateBids(bids)
+	b.book.UpdateAsks(asks)
+	b.lastUpdateID = ev.FinalUpdateID
+	b.updatedAt = time.Now()
+	if ev.EventTime > 0 {
+		b.eventTime = time.UnixMilli(ev.EventTime)
+	}
+
+	b.liveAt = b.updatedAt
+	if !b.synced {
+		b.synced = true
+		b.readyOnce.Do(func() { close(b.ready) })
+	}
+	return nil
+}
+
+// confirm records that the stream is alive. While synced, the book is then
+// known to be current even if no update changed it.
+func (b *localBook) confirm() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.synced {
+		b.liveAt = time.Now()
+	}
+}
+
+func (b *localBook) invalidate() {
+	b.mu.Lock()
+	b.synced = false
+	b.mu.Unlock()
+}
+
+func (b *localBook) isSynced() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.synced
+}
+
+// orderBook returns a sorted copy of the book, or false if it is not synced.
+// ExchangeTime is the event time of the last applied update, in Binance's
+// clock; LiveAt is when the stream was last seen alive.
+func (b *localBook) orderBook() (*domain.OrderBook, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.synced {
+		return nil, false
+	}
+	ob := b.book.OrderBook(b.updatedAt)
+	ob.ExchangeTime = b.eventTime
+	ob.LiveAt = b.liveAt
+	return ob, true
+}
@@ -0,0 +1,148 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDepthServer starts a stand-in for both the Binance REST API and the
+// diff-depth stream. snapshots and sessions are served in order, one per
+// snapshot request and WebSocket connection respectively.
+func newDepthServer(t *testing.T, snapshots []string, sessions [][]string) (*httptest.Server, *int32) {
+	var snapshotCount, sessionCount int32
+	upgrader := websocket.Upgrader{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/exchangeInfo":
+			_, _ = fmt.Fprintln(w, exchangeInfoResponseJSON)
+		case r.URL.Path == "/depth":
+			i := int(atomic.AddInt32(&snapshotCount, 1)) - 1
+			if i >= len(snapshots) {
+				i = len(snapshots) - 1
+			}
+			_, _ = fmt.Fprintln(w, snapshots[i])
+		case r.URL.Path == "/ws/ethusdc@depth@100ms":
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				t.Errorf("upgrade failed: %v", err)
+				return
+			}
+			defer func() {
+				_ = conn.Close()
+			}()
+
+			i := int(atomic.AddInt32(&sessionCount, 1)) - 1
+			if i < len(sessions) {
+				for _, msg := range sessions[i] {
+					_ = conn.WriteMessage(websocket.TextMessage, []byte(msg))
+				}
+			}
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, &snapshotCount
+}
+
+func TestStreamAdapter_SyncsAndAppliesDiffs(t *testing.T) {
+	snapshot := `{"lastUpdateId": 100, "bids": [["2000.00", "1.0"], ["1999.00", "2.0"]], "asks": [["2001.00", "1.0"]]}`
+	events := []string{
+		// Fully covered by the snapshot, must be dropped.
+		`{"e":"depthUpdate","E":1,"s":"ETHUSDC","U":95,"u":100,"b":[["2000.00","9.0"]],"a":[]}`,
+		// Bridges the snapshot.
+		`{"e":"depthUpdate","E":2,"s":"ETHUSDC","U":99,"u":101,"b":[["1999.00","0"]],"a":[["2002.00","3.0"]]}`,
+		`{"e":"depthUpdate","E":3,"s":"ETHUSDC","U":102,"u":102,"b":[["2000.50","0.5"]],"a":[]}`,
+	}
+	ts, _ := newDepthServer(t, []string{snapshot}, [][]string{events})
+
+	adapter := NewStreamAdapter("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", ts.URL)
+	defer func() {
+		_ = adapter.Close()
+	}()
+
+	require.Eventually(t, func() bool {
+		ob, err := adapter.GetOrderBook(context.Background(), "ETHUSDC")
+		return err == nil && len(ob.Bids) == 2 && ob.Bids[0].Price.Equal(decimal.RequireFromString("2000.50"))
+	}, 2*time.Second, 20*time.Millisecond)
+
+	ob, err := adapter.GetOrderBook(context.Background(), "ETHUSDC")
+	require.NoError(t, err)
+
+	assert.True(t, ob.Bids[1].Price.Equal(decimal.RequireFromString("2000.00")))
+	assert.True(t, ob.Bids[1].Amount.Equal(decimal.RequireFromString("1.0")), "stale event must not be applied")
+	require.Len(t, ob.Asks, 2)
+	assert.True(t, ob.Asks[0].Price.Equal(decimal.RequireFromString("2001.00")))
+	assert.True(t, ob.Asks[1].Price.Equal(decimal.RequireFromString("2002.00")))
+}
+
+func TestStreamAdapter_ResyncsOnGap(t *testing.T) {
+	snapshots := []string{
+		`{"lastUpdateId": 100, "bids": [["2000.00", "1.0"]], "asks": [["2001.00", "1.0"]]}`,
+		`{"lastUpdateId": 300, "bids": [["2100.00", "1.0"]], "asks": [["2101.00", "1.0"]]}`,
+	}
+	sessions := [][]string{
+		{
+			`{"e":"depthUpdate","E":1,"s":"ETHUSDC","U":101,"u":101,"b":[],"a":[]}`,
+			// U should be 102: forces a resync.
+			`{"e":"depthUpdate","E":2,"s":"ETHUSDC","U":250,"u":260,"b":[],"a":[]}`,
+		},
+		{
+			`{"e":"depthUpdate","E":3,"s":"ETHUSDC","U":301,"u":301,"b":[["2100.50","2.0"]],"a":[]}`,
+		},
+	}
+	ts, snapshotCount := newDepthServer(t, snapshots, sessions)
+
+	adapter := NewStreamAdapter("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", ts.URL)
+	defer func() {
+		_ = adapter.Close()
+	}()
+
+	require.Eventually(t, func() bool {
+		ob, err := adapter.GetOrderBook(context.Background(), "ETHUSDC")
+		return err == nil && len(ob.Bids) > 0 && ob.Bids[0].Price.Equal(decimal.RequireFromString("2100.50"))
+	}, 5*time.Second, 50*time.Millisecond)
+
+	assert.GreaterOrEqual(t, atomic.LoadInt32(snapshotCount), int32(2))
+}
+
+func TestLocalBook_RejectsSnapshotGap(t *testing.T) {
+	book := newLocalBook()
+	require.NoError(t, book.reset(depthResponse{LastUpdateID: 100}))
+
+	err := book.apply(depthEvent{FirstUpdateID: 150, FinalUpdateID: 160})
+	assert.ErrorIs(t, err, errDepthGap)
+
+	_, ok := book.orderBook()
+	assert.False(t, ok)
+}
+
+func TestStreamAdapter_ForwardsDepthToREST(t *testing.T) {
+	adapter := NewStreamAdapter("ws://unused", "http://unused")
+	defer func() {
+		_ = adapter.Close()
+	}()
+
+	adapter.SetBookDepth(50)
+	assert.Equal(t, 50, adapter.rest.depth.Levels())
+	require.True(t, adapter.DeepenBook())
+	assert.Equal(t, 100, adapter.rest.depth.Levels())
+}
@@ -0,0 +1,187 @@
+package binance
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/observability"
+)
+
+const (
+	// defaultWeightLimit is the REQUEST_WEIGHT allowed per minute on
+	// api.binance.com. exchangeInfo reports the live value.
+	defaultWeightLimit = 6000
+	weightWindow       = time.Minute
+
+	// usedWeightHeader carries the weight used by this IP in the current
+	// minute, including the request it is returned with.
+	usedWeightHeader = "X-MBX-USED-WEIGHT-1M"
+
+	// Requests are held back once usage would pass weightThrottlePct of the
+	// limit, leaving room for requests already in flight and for other
+	// processes sharing the IP.
+	weightThrottlePct = 90
+
+	// Depth requests fall back to the cheapest tier once less than
+	// weightLowPct of the limit would be left.
+	weightLowPct = 25
+
+	// cheapDepthLimit is the largest depth in the cheapest weight tier.
+	cheapDepthLimit = 100
+)
+
+// weightBudget tracks the request weight used in the current one-minute
+// window. Usage is counted locally when a request is reserved and corrected
+// upwards from the X-MBX-USED-WEIGHT-1M header, which also covers other
+// clients on the same IP.
+type weightBudget struct {
+	mu     sync.Mutex
+	limit  int
+	used   int
+	window time.Time
+
+	now func() time.Time
+}
+
+func newWeightBudget() *weightBudget {
+	b := &weightBudget{limit: defaultWeightLimit, now: time.Now}
+	b.report()
+	return b
+}
+
+// Reserve blocks until the weight of the request fits in the budget.
+func (b *weightBudget) Reserve(ctx context.Context, rawURL string) error {
+	cost := requestWeight(rawURL)
+	for {
+		wait, ok := b.take(cost)
+		if ok {
+			return nil
+		}
+
+		observability.CEXWeightThrottled.WithLabelValues("binance").Inc()
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// take books cost if it fits, otherwise it returns how long until the window
+// resets. A request is always allowed into an empty window, however costly.
+func (b *weightBudget) take(cost int) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.roll(now)
+	if b.used > 0 && b.used+cost > b.limit*weightThrottlePct/100 {
+		return b.window.Add(weightWindow).Sub(now), false
+	}
+	b.used += cost
+	b.report()
+	return 0, true
+}
+
+func (b *weightBudget) Observe(resp *http.Response) {
+	used, err := strconv.Atoi(resp.Header.Get(usedWeightHeader))
+	if err != nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll(b.now())
+	if used > b.used {
+		b.used = used
+		b.report()
+	}
+}
+
+// Remaining returns the weight left in the current window.
+func (b *weightBudget) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll(b.now())
+	return b.limit - b.used
+}
+
+// Low reports whether spending cost would leave less than weightLowPct of the
+// limit in the current window.
+func (b *weightBudget) Low(cost int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll(b.now())
+	return b.limit-b.used-cost < b.limit*weightLowPct/100
+}
+
+func (b *weightBudget) setLimit(limit int) {
+	if limit <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.limit = limit
+	b.report()
+}
+
+// roll starts a new window at each minute boundary, matching how Binance
+// resets its counters.
+func (b *weightBudget) roll(now time.Time) {
+	if window := now.Truncate(weightWindow); !window.Equal(b.window) {
+		b.window = window
+		b.used = 0
+		b.report()
+	}
+}
+
+func (b *weightBudget) report() {
+	observability.CEXWeightRemaining.WithLabelValues("binance").Set(float64(b.limit - b.used))
+}
+
+// requestWeight returns the documented weight of a public endpoint.
+func requestWeight(rawURL string) int {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 1
+	}
+	q := u.Query()
+
+	switch path.Base(u.Path) {
+	case "depth":
+		limit, err := strconv.Atoi(q.Get("limit"))
+		if err != nil {
+			limit = cheapDepthLimit
+		}
+		return depthWeight(limit)
+	case "exchangeInfo":
+		return 20
+	case "price", "bookTicker":
+		if q.Get("symbol") == "" {
+			return 4
+		}
+		return 2
+	default:
+		return 1
+	}
+}
+
+func depthWeight(limit int) int {
+	switch {
+	case limit <= 100:
+		return 5
+	case limit <= 500:
+		return 25
+	case limit <= 1000:
+		return 50
+	default:
+		return 250
+	}
+}
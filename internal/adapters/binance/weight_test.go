@@ -0,0 +1,87 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixedBudget(now time.Time) *weightBudget {
+	b := newWeightBudget()
+	b.now = func() time.Time { return now }
+	return b
+}
+
+func TestRequestWeight(t *testing.T) {
+	assert.Equal(t, 5, requestWeight("https://api.binance.com/api/v3/depth?symbol=ETHUSDC&limit=100"))
+	assert.Equal(t, 25, requestWeight("https://api.binance.com/api/v3/depth?symbol=ETHUSDC&limit=500"))
+	assert.Equal(t, 50, requestWeight("https://api.binance.com/api/v3/depth?symbol=ETHUSDC&limit=1000"))
+	assert.Equal(t, 250, requestWeight("https://api.binance.com/api/v3/depth?symbol=ETHUSDC&limit=5000"))
+	assert.Equal(t, 20, requestWeight("https://api.binance.com/api/v3/exchangeInfo?permissions=SPOT"))
+	assert.Equal(t, 2, requestWeight("https://api.binance.com/api/v3/ticker/bookTicker?symbol=ETHUSDC"))
+	assert.Equal(t, 4, requestWeight("https://api.binance.com/api/v3/ticker/price"))
+	assert.Equal(t, 1, requestWeight("https://api.binance.com/api/v3/time"))
+}
+
+func TestWeightBudget_ThrottlesUntilNextWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 45, 0, time.UTC)
+	b := fixedBudget(now)
+	b.setLimit(100)
+
+	_, ok := b.take(50)
+	require.True(t, ok)
+	_, ok = b.take(40)
+	require.True(t, ok)
+
+	wait, ok := b.take(5)
+	assert.False(t, ok, "usage past 90% of the limit must be held back")
+	assert.Equal(t, 15*time.Second, wait)
+
+	b.now = func() time.Time { return now.Add(15 * time.Second) }
+	_, ok = b.take(5)
+	assert.True(t, ok)
+	assert.Equal(t, 95, b.Remaining())
+}
+
+func usedWeight(v string) *http.Response {
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set(usedWeightHeader, v)
+	return resp
+}
+
+func TestWeightBudget_ObserveFollowsHeader(t *testing.T) {
+	b := fixedBudget(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	b.Observe(usedWeight("5400"))
+	assert.Equal(t, defaultWeightLimit-5400, b.Remaining())
+
+	b.Observe(usedWeight("100"))
+	assert.Equal(t, defaultWeightLimit-5400, b.Remaining(), "stale responses must not lower usage")
+}
+
+func TestFetchDepth_ShrinksWhenBudgetLow(t *testing.T) {
+	var limits []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Query().Get("limit"))
+		w.Header().Set(usedWeightHeader, "5000")
+		_, _ = fmt.Fprintln(w, `{"lastUpdateId": 1, "bids": [], "asks": []}`)
+	}))
+	defer ts.Close()
+
+	adapter := newAdapter(ts.URL)
+	now := time.Now()
+	adapter.weights.now = func() time.Time { return now }
+
+	_, err := adapter.fetchDepth(context.Background(), "ETHUSDC", 1000)
+	require.NoError(t, err)
+	_, err = adapter.fetchDepth(context.Background(), "ETHUSDC", 1000)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"1000", "100"}, limits)
+}
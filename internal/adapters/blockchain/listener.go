@@ -8,6 +8,7 @@ import (
 
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/ports"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/observability"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"golang.org/x/time/rate"
@@ -48,6 +49,7 @@ func (l *Listener) SubscribeNewHeads(ctx context.Context) (<-chan *domain.Block,
 			default:
 				client, err := ethclient.DialContext(ctx, l.clientURL)
 				if err != nil {
+					observability.ListenerReconnects.WithLabelValues("dial_failed").Inc()
 					l.logError(errChan, fmt.Errorf("dial failed: %w", err))
 					time.Sleep(backoff)
 					backoff *= 2
@@ -74,9 +76,11 @@ func (l *Listener) SubscribeNewHeads(ctx context.Context) (<-chan *domain.Block,
 							}
 							block, blockErr := client.BlockByNumber(ctx, i)
 							if blockErr != nil {
+								observability.ListenerBackfillErrors.Inc()
 								l.logError(errChan, fmt.Errorf("backfill failed for block %s: %w", i, blockErr))
 								continue
 							}
+							observability.ListenerBackfilled.Inc()
 							out <- &domain.Block{
 								Number:    block.Number(),
 								Timestamp: time.Unix(int64(block.Time()), 0),
@@ -90,6 +94,7 @@ func (l *Listener) SubscribeNewHeads(ctx context.Context) (<-chan *domain.Block,
 				sub, err := client.SubscribeNewHead(ctx, headers)
 				if err != nil {
 					client.Close()
+					observability.ListenerReconnects.WithLabelValues("subscribe_failed").Inc()
 					l.logError(errChan, fmt.Errorf("sub failed: %w", err))
 					time.Sleep(backoff)
 					backoff *= 2
@@ -118,11 +123,13 @@ func (l *Listener) SubscribeNewHeads(ctx context.Context) (<-chan *domain.Block,
 						client.Close()
 						return
 					case err := <-sub.Err():
+						observability.ListenerReconnects.WithLabelValues("subscription_error").Inc()
 						l.logError(errChan, fmt.Errorf("sub err: %w", err))
 						sub.Unsubscribe()
 						client.Close()
 						break connLoop
 					case <-timer.C:
+						observability.ListenerReconnects.WithLabelValues("heartbeat_timeout").Inc()
 						l.logError(errChan, fmt.Errorf("heartbeat timeout (%v)", heartbeatInterval))
 						sub.Unsubscribe()
 						client.Close()
@@ -0,0 +1,176 @@
+package bybit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/clock"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/httpclient"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/instruments"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/orderbook"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/ports"
+)
+
+const (
+	BaseURL = "https://api.bybit.com"
+
+	// defaultDepth is also the maximum Bybit serves for spot order books.
+	defaultDepth = 200
+)
+
+// Bybit v5 retCodes that are handled explicitly.
+const (
+	retCodeOK            = 0
+	retCodeParamsError   = 10001
+	retCodeRateLimited   = 10006
+	retCodeIPBanned      = 10018
+	retCodeInvalidSymbol = 170121
+)
+
+// APIError is a non-zero retCode returned by the Bybit v5 API.
+type APIError struct {
+	Code int
+	Msg  string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("bybit api error: %d - %s", e.Code, e.Msg)
+}
+
+// RateLimited reports whether the request was rejected for exceeding the
+// venue's rate limit.
+func (e *APIError) RateLimited() bool {
+	return e.Code == retCodeRateLimited || e.Code == retCodeIPBanned
+}
+
+// isClientError reports whether err was caused by the request itself rather
+// than the venue, in which case it must not trip the circuit breaker.
+func isClientError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Code == retCodeParamsError || apiErr.Code == retCodeInvalidSymbol
+}
+
+type Adapter struct {
+	client  *httpclient.Client
+	baseURL string
+	depth   *orderbook.DepthLimit
+	clock   *clock.Offset
+
+	instruments *instruments.Registry
+}
+
+func NewAdapter(baseURL string) ports.ExchangeAdapter {
+	cfg := httpclient.DefaultConfig("bybit")
+	cfg.IsClientError = isClientError
+
+	a := &Adapter{
+		client:  httpclient.New(cfg),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		depth:   orderbook.NewDepthLimit("bybit", defaultDepth, 50, 100, 200),
+	}
+	a.clock = clock.NewOffset("bybit", a.serverTime)
+	a.instruments = instruments.NewRegistry("bybit", instruments.DefaultTTL, a.loadInstruments)
+	return a
+}
+
+// apiStatus is the envelope shared by all v5 responses.
+type apiStatus struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+}
+
+func (s apiStatus) Validate() error {
+	if s.RetCode != retCodeOK {
+		return &APIError{Code: s.RetCode, Msg: s.RetMsg}
+	}
+	return nil
+}
+
+type orderbookResponse struct {
+	apiStatus
+	Result orderbookResult `json:"result"`
+	Time   int64           `json:"time"`
+}
+
+type orderbookResult struct {
+	Symbol   string     `json:"s"`
+	Bids     [][]string `json:"b"`
+	Asks     [][]string `json:"a"`
+	Ts       int64      `json:"ts"`
+	UpdateID int64      `json:"u"`
+}
+
+func (a *Adapter) GetOrderBook(ctx context.Context, symbol string) (*domain.OrderBook, error) {
+	inst, err := a.instruments.Lookup(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var book orderbookResponse
+	url := fmt.Sprintf("%s/v5/market/orderbook?category=spot&symbol=%s&limit=%d", a.baseURL, inst.VenueSymbol, a.depth.Levels())
+	if err := a.client.GetJSON(ctx, url, &book); err != nil {
+		return nil, err
+	}
+
+	result := book.Result
+
+	bids, err := orderbook.ParseLevels(result.Bids)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bids: %w", err)
+	}
+	asks, err := orderbook.ParseLevels(result.Asks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asks: %w", err)
+	}
+
+	ob := &domain.OrderBook{
+		Bids:      bids,
+		Asks:      asks,
+		Timestamp: time.Now(),
+	}
+	if result.Ts > 0 {
+		ob.ExchangeTime = a.clock.ToLocal(time.UnixMilli(result.Ts))
+	}
+	return ob, nil
+}
+
+// SetBookDepth sets the configured number of levels fetched per side.
+func (a *Adapter) SetBookDepth(levels int) { a.depth.Configure(levels) }
+
+// DeepenBook fetches the next depth tier from now on, up to the 200 levels Bybit serves for spot.
+func (a *Adapter) DeepenBook() bool { return a.depth.Deepen() }
+
+// BookFilled lets a deepened depth relax back toward the configured one.
+func (a *Adapter) BookFilled() { a.depth.Filled() }
+
+// SyncClock re-estimates the offset to Bybit's server clock.
+func (a *Adapter) SyncClock(ctx context.Context) error {
+	return a.clock.Sync(ctx)
+}
+
+type timeResponse struct {
+	apiStatus
+	Result struct {
+		TimeNano string `json:"timeNano"`
+	} `json:"result"`
+}
+
+func (a *Adapter) serverTime(ctx context.Context) (time.Time, error) {
+	var resp timeResponse
+	if err := a.client.GetJSON(ctx, a.baseURL+"/v5/market/time", &resp); err != nil {
+		return time.Time{}, err
+	}
+	ns, err := strconv.ParseInt(resp.Result.TimeNano, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid server time %q: %w", resp.Result.TimeNano, err)
+	}
+	return time.Unix(0, ns), nil
+}
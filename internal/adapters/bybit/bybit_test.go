@@ -0,0 +1,174 @@
+package bybit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const instrumentsResponseJSON = `{
+	"retCode": 0,
+	"retMsg": "OK",
+	"result": {"list": [
+		{"symbol": "ETHUSDC", "baseCoin": "ETH", "quoteCoin": "USDC", "status": "Trading",
+			"lotSizeFilter": {"basePrecision": "0.00001", "minOrderQty": "0.00001", "minOrderAmt": "1"},
+			"priceFilter": {"tickSize": "0.01"}},
+		{"symbol": "FOOBAR", "baseCoin": "FOO", "quoteCoin": "BAR", "status": "Trading",
+			"lotSizeFilter": {"basePrecision": "1", "minOrderQty": "1", "minOrderAmt": "1"},
+			"priceFilter": {"tickSize": "1"}},
+		{"symbol": "SOLUSDC", "baseCoin": "SOL", "quoteCoin": "USDC", "status": "Trading",
+			"lotSizeFilter": {"basePrecision": "0.001", "minOrderQty": "0.001", "minOrderAmt": "1"},
+			"priceFilter": {"tickSize": ""}}
+	]}
+}`
+
+// withInstruments serves instrumentsResponseJSON and passes every other
+// request to next.
+func withInstruments(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v5/market/instruments-info" {
+			_, _ = fmt.Fprintln(w, instrumentsResponseJSON)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func TestGetOrderBook(t *testing.T) {
+	// Mock Server
+	ts := httptest.NewServer(withInstruments(func(w http.ResponseWriter, r *http.Request) {
+		// Verify Request
+		if r.URL.Path != "/v5/market/orderbook" {
+			t.Errorf("Expected path /v5/market/orderbook, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("category") != "spot" {
+			t.Errorf("Expected category spot, got %s", r.URL.Query().Get("category"))
+		}
+		if r.URL.Query().Get("symbol") != "ETHUSDC" {
+			t.Errorf("Expected symbol ETHUSDC, got %s", r.URL.Query().Get("symbol"))
+		}
+
+		// Mock Response
+		response := `{
+			"retCode": 0,
+			"retMsg": "OK",
+			"result": {
+				"s": "ETHUSDC",
+				"b": [
+					["2000.10", "1.25"],
+					["2000.00", "3.5"]
+				],
+				"a": [
+					["2000.20", "0.8"]
+				],
+				"ts": 1716863719031,
+				"u": 230704
+			},
+			"retExtInfo": {},
+			"time": 1716863719382
+		}`
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintln(w, response)
+	}))
+	defer ts.Close()
+
+	adapter := NewAdapter(ts.URL)
+
+	ob, err := adapter.GetOrderBook(context.Background(), "eth-usdc")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, ob)
+	assert.Len(t, ob.Bids, 2)
+	assert.Len(t, ob.Asks, 1)
+
+	assert.True(t, ob.Bids[0].Price.Equal(decimal.RequireFromString("2000.10")), "Bid price mismatch")
+	assert.True(t, ob.Bids[1].Amount.Equal(decimal.RequireFromString("3.5")), "Bid amount mismatch")
+	assert.True(t, ob.Asks[0].Price.Equal(decimal.RequireFromString("2000.20")), "Ask price mismatch")
+	assert.True(t, ob.Asks[0].Amount.Equal(decimal.RequireFromString("0.8")), "Ask amount mismatch")
+}
+
+func TestGetOrderBook_Error(t *testing.T) {
+	ts := httptest.NewServer(withInstruments(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	adapter := NewAdapter(ts.URL)
+	_, err := adapter.GetOrderBook(context.Background(), "ETHUSDC")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bybit api returned status: 500")
+}
+
+func TestGetOrderBook_NotListed(t *testing.T) {
+	var bookCalls int32
+	ts := httptest.NewServer(withInstruments(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&bookCalls, 1)
+	}))
+	defer ts.Close()
+
+	adapter := NewAdapter(ts.URL)
+	_, err := adapter.GetOrderBook(context.Background(), "ETHUSDT")
+
+	assert.ErrorIs(t, err, domain.ErrInstrumentNotListed)
+	assert.Zero(t, atomic.LoadInt32(&bookCalls))
+}
+
+func TestGetInstrument(t *testing.T) {
+	ts := httptest.NewServer(withInstruments(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	adapter := NewAdapter(ts.URL).(*Adapter)
+
+	inst, err := adapter.GetInstrument(ctx, "ETHUSDC")
+	require.NoError(t, err)
+	assert.True(t, inst.TickSize.Equal(decimal.RequireFromString("0.01")))
+	assert.True(t, inst.MinNotional.Equal(decimal.NewFromInt(1)))
+
+	_, err = adapter.GetInstrument(ctx, "SOLUSDC")
+	assert.ErrorIs(t, err, domain.ErrInstrumentNotListed, "instruments with malformed metadata are skipped")
+}
+
+func TestGetOrderBook_RetCode(t *testing.T) {
+	ts := httptest.NewServer(withInstruments(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, `{"retCode":10006,"retMsg":"Too many visits!","result":{},"time":1716863719382}`)
+	}))
+	defer ts.Close()
+
+	adapter := NewAdapter(ts.URL)
+	_, err := adapter.GetOrderBook(context.Background(), "ETHUSDC")
+
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 10006, apiErr.Code)
+	assert.True(t, apiErr.RateLimited())
+}
+
+func TestGetOrderBook_InvalidSymbolDoesNotTripBreaker(t *testing.T) {
+	var calls int32
+	// FOOBAR is listed but delisted before the registry refreshes.
+	ts := httptest.NewServer(withInstruments(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = fmt.Fprintln(w, `{"retCode":170121,"retMsg":"Invalid symbol.","result":{},"time":1716863719382}`)
+	}))
+	defer ts.Close()
+
+	adapter := NewAdapter(ts.URL)
+	for i := 0; i < 6; i++ {
+		_, err := adapter.GetOrderBook(context.Background(), "FOOBAR")
+		assert.NotErrorIs(t, err, gobreaker.ErrOpenState)
+	}
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls))
+}
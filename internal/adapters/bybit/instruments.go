@@ -0,0 +1,99 @@
+package bybit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+)
+
+type instrumentsResponse struct {
+	apiStatus
+	Result struct {
+		List []instrumentInfo `json:"list"`
+	} `json:"result"`
+}
+
+type instrumentInfo struct {
+	Symbol        string `json:"symbol"`
+	BaseCoin      string `json:"baseCoin"`
+	QuoteCoin     string `json:"quoteCoin"`
+	Status        string `json:"status"`
+	LotSizeFilter struct {
+		BasePrecision string `json:"basePrecision"`
+		MinOrderQty   string `json:"minOrderQty"`
+		MinOrderAmt   string `json:"minOrderAmt"`
+	} `json:"lotSizeFilter"`
+	PriceFilter struct {
+		TickSize string `json:"tickSize"`
+	} `json:"priceFilter"`
+}
+
+// GetInstrument returns the spot instruments-info metadata for symbol.
+func (a *Adapter) GetInstrument(ctx context.Context, symbol string) (*domain.Instrument, error) {
+	return a.instruments.Lookup(ctx, symbol)
+}
+
+// loadInstruments fetches spot instruments-info.
+func (a *Adapter) loadInstruments(ctx context.Context) ([]domain.Instrument, error) {
+	var info instrumentsResponse
+	if err := a.client.GetJSON(ctx, a.baseURL+"/v5/market/instruments-info?category=spot", &info); err != nil {
+		return nil, err
+	}
+
+	list := make([]domain.Instrument, 0, len(info.Result.List))
+	for _, s := range info.Result.List {
+		if s.Status != "Trading" {
+			continue
+		}
+
+		inst, err := s.instrument()
+		if err != nil {
+			slog.Error("Skipping Bybit instrument with malformed metadata", "symbol", s.Symbol, "error", err)
+			continue
+		}
+		list = append(list, inst)
+	}
+	return list, nil
+}
+
+// instrument converts s. Tick size and precision are required; an empty
+// minimum is read as no minimum.
+func (s instrumentInfo) instrument() (domain.Instrument, error) {
+	tick, err := decimal.NewFromString(s.PriceFilter.TickSize)
+	if err != nil {
+		return domain.Instrument{}, fmt.Errorf("tickSize: %w", err)
+	}
+	lot, err := decimal.NewFromString(s.LotSizeFilter.BasePrecision)
+	if err != nil {
+		return domain.Instrument{}, fmt.Errorf("basePrecision: %w", err)
+	}
+	minQty, err := optionalDecimal(s.LotSizeFilter.MinOrderQty)
+	if err != nil {
+		return domain.Instrument{}, fmt.Errorf("minOrderQty: %w", err)
+	}
+	minNotional, err := optionalDecimal(s.LotSizeFilter.MinOrderAmt)
+	if err != nil {
+		return domain.Instrument{}, fmt.Errorf("minOrderAmt: %w", err)
+	}
+
+	return domain.Instrument{
+		Symbol:      domain.CanonicalSymbol(s.BaseCoin, s.QuoteCoin),
+		VenueSymbol: s.Symbol,
+		Base:        s.BaseCoin,
+		Quote:       s.QuoteCoin,
+		TickSize:    tick,
+		LotSize:     lot,
+		MinQty:      minQty,
+		MinNotional: minNotional,
+	}, nil
+}
+
+func optionalDecimal(s string) (decimal.Decimal, error) {
+	if s == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(s)
+}
@@ -0,0 +1,87 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/observability"
+)
+
+// samples is the number of server-time requests made per sync. The one with
+// the shortest round trip gives the tightest estimate.
+const samples = 3
+
+// ServerTime fetches a venue's current clock reading.
+type ServerTime func(ctx context.Context) (time.Time, error)
+
+// Offset estimates how far a venue's clock runs ahead of the local clock, so
+// exchange timestamps can be compared with local and block times. Until the
+// first successful Sync the offset is zero.
+type Offset struct {
+	venue string
+	fetch ServerTime
+
+	mu     sync.RWMutex
+	offset time.Duration
+}
+
+func NewOffset(venue string, fetch ServerTime) *Offset {
+	return &Offset{venue: venue, fetch: fetch}
+}
+
+// Sync samples the venue's server time and keeps the estimate from the sample
+// with the shortest round trip, assuming the server read its clock halfway
+// through it. The previous estimate is kept if every sample fails.
+func (o *Offset) Sync(ctx context.Context) error {
+	var (
+		best    time.Duration = -1
+		offset  time.Duration
+		lastErr error
+	)
+	for i := 0; i < samples; i++ {
+		sent := time.Now()
+		server, err := o.fetch(ctx)
+		rtt := time.Since(sent)
+		if err != nil {
+			lastErr = err
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		if best < 0 || rtt < best {
+			best = rtt
+			offset = server.Sub(sent.Add(rtt / 2))
+		}
+	}
+	if best < 0 {
+		return fmt.Errorf("%s server time unavailable: %w", o.venue, lastErr)
+	}
+
+	o.mu.Lock()
+	o.offset = offset
+	o.mu.Unlock()
+
+	observability.CEXClockOffset.WithLabelValues(o.venue).Set(offset.Seconds())
+	slog.Debug("Synced venue clock", "venue", o.venue, "offset", offset, "rtt", best)
+	return nil
+}
+
+// Offset returns the current estimate of venue time minus local time.
+func (o *Offset) Offset() time.Duration {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.offset
+}
+
+// ToLocal converts a venue timestamp to the local clock. The zero time, used
+// for books without an exchange timestamp, is returned unchanged.
+func (o *Offset) ToLocal(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.Add(-o.Offset())
+}
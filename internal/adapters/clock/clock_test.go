@@ -0,0 +1,41 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffset_Sync(t *testing.T) {
+	o := NewOffset("test", func(ctx context.Context) (time.Time, error) {
+		return time.Now().Add(2 * time.Second), nil
+	})
+
+	require.NoError(t, o.Sync(context.Background()))
+	assert.InDelta(t, float64(2*time.Second), float64(o.Offset()), float64(50*time.Millisecond))
+
+	venue := time.Date(2024, 1, 1, 12, 0, 10, 0, time.UTC)
+	assert.WithinDuration(t, venue.Add(-2*time.Second), o.ToLocal(venue), 50*time.Millisecond)
+	assert.True(t, o.ToLocal(time.Time{}).IsZero())
+}
+
+func TestOffset_SyncKeepsEstimateOnFailure(t *testing.T) {
+	fail := false
+	o := NewOffset("test", func(ctx context.Context) (time.Time, error) {
+		if fail {
+			return time.Time{}, errors.New("unavailable")
+		}
+		return time.Now().Add(-time.Second), nil
+	})
+	require.NoError(t, o.Sync(context.Background()))
+
+	fail = true
+	err := o.Sync(context.Background())
+
+	assert.ErrorContains(t, err, "test server time unavailable")
+	assert.InDelta(t, float64(-time.Second), float64(o.Offset()), float64(50*time.Millisecond))
+}
@@ -0,0 +1,120 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/clock"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/httpclient"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/instruments"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/orderbook"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/ports"
+)
+
+const (
+	BaseURL = "https://api.exchange.coinbase.com"
+
+	// Coinbase rejects requests without a User-Agent.
+	userAgent = "cex-dex-arbitrage-bot"
+)
+
+type Adapter struct {
+	client    *httpclient.Client
+	baseURL   string
+	clock     *clock.Offset
+	usdcAlias bool
+
+	instruments *instruments.Registry
+}
+
+// NewAdapter creates a Coinbase adapter. With usdcAlias, USDC pairs that
+// Coinbase does not list resolve to their USD product.
+func NewAdapter(baseURL string, usdcAlias bool) ports.ExchangeAdapter {
+	cfg := httpclient.DefaultConfig("coinbase") // public endpoints allow 10 req/s
+	cfg.Header = http.Header{"User-Agent": {userAgent}}
+	cfg.ErrorMessage = errorMessage
+
+	a := &Adapter{
+		client:    httpclient.New(cfg),
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		usdcAlias: usdcAlias,
+	}
+	a.clock = clock.NewOffset("coinbase", a.serverTime)
+	a.instruments = instruments.NewRegistry("coinbase", instruments.DefaultTTL, a.loadInstruments)
+	return a
+}
+
+// bookResponse is the level 2 (aggregated) product book. Each level is
+// [price, size, num_orders] where num_orders is a JSON number.
+type bookResponse struct {
+	Bids     [][]any `json:"bids"`
+	Asks     [][]any `json:"asks"`
+	Sequence int64   `json:"sequence"`
+	Time     string  `json:"time"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+func errorMessage(body []byte) string {
+	var apiErr errorResponse
+	_ = json.Unmarshal(body, &apiErr)
+	return apiErr.Message
+}
+
+func (a *Adapter) GetOrderBook(ctx context.Context, symbol string) (*domain.OrderBook, error) {
+	inst, err := a.instruments.Lookup(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var book bookResponse
+	url := fmt.Sprintf("%s/products/%s/book?level=2", a.baseURL, inst.VenueSymbol)
+	if err := a.client.GetJSON(ctx, url, &book); err != nil {
+		return nil, err
+	}
+
+	bids, err := orderbook.ParseMixedLevels(book.Bids)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bids: %w", err)
+	}
+	asks, err := orderbook.ParseMixedLevels(book.Asks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asks: %w", err)
+	}
+
+	ob := &domain.OrderBook{
+		Bids:      bids,
+		Asks:      asks,
+		Timestamp: time.Now(),
+	}
+	if t, err := time.Parse(time.RFC3339Nano, book.Time); err == nil {
+		ob.ExchangeTime = a.clock.ToLocal(t)
+	}
+	return ob, nil
+}
+
+// SyncClock re-estimates the offset to Coinbase's server clock.
+func (a *Adapter) SyncClock(ctx context.Context) error {
+	return a.clock.Sync(ctx)
+}
+
+func (a *Adapter) serverTime(ctx context.Context) (time.Time, error) {
+	var resp struct {
+		ISO string `json:"iso"`
+	}
+	if err := a.client.GetJSON(ctx, a.baseURL+"/time", &resp); err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, resp.ISO)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid server time %q: %w", resp.ISO, err)
+	}
+	return t, nil
+}
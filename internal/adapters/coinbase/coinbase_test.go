@@ -0,0 +1,123 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const productsResponse = `[
+	{"id": "ETH-USD", "base_currency": "ETH", "quote_currency": "USD", "quote_increment": "0.01", "base_increment": "0.00000001", "min_market_funds": "1", "status": "online", "trading_disabled": false},
+	{"id": "BTC-USD", "base_currency": "BTC", "quote_currency": "USD", "quote_increment": "0.01", "base_increment": "0.00000001", "min_market_funds": "1", "status": "online", "trading_disabled": false},
+	{"id": "BTC-USDC", "base_currency": "BTC", "quote_currency": "USDC", "quote_increment": "0.01", "base_increment": "0.00000001", "min_market_funds": "1", "status": "online", "trading_disabled": false},
+	{"id": "SOL-USD", "base_currency": "SOL", "quote_currency": "USD", "quote_increment": "n/a", "base_increment": "0.001", "status": "online", "trading_disabled": false},
+	{"id": "ETH-USDT", "base_currency": "ETH", "quote_currency": "USDT", "quote_increment": "0.01", "base_increment": "0.00000001", "min_market_funds": "1", "status": "delisted", "trading_disabled": true}
+]`
+
+func TestGetOrderBook(t *testing.T) {
+	// Mock Server
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/products" {
+			_, _ = fmt.Fprintln(w, productsResponse)
+			return
+		}
+
+		// Verify Request
+		if r.URL.Path != "/products/ETH-USD/book" {
+			t.Errorf("Expected path /products/ETH-USD/book, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("level") != "2" {
+			t.Errorf("Expected level 2, got %s", r.URL.Query().Get("level"))
+		}
+
+		// Mock Response
+		response := `{
+			"bids": [
+				["2000.01", "1.5", 3],
+				["2000.00", "4.25", 7]
+			],
+			"asks": [
+				["2000.02", "0.75", 1]
+			],
+			"sequence": 3,
+			"time": "2024-01-01T00:00:00.000000Z"
+		}`
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintln(w, response)
+	}))
+	defer ts.Close()
+
+	adapter := NewAdapter(ts.URL, false)
+
+	ob, err := adapter.GetOrderBook(context.Background(), "ETHUSD")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, ob)
+	assert.Len(t, ob.Bids, 2)
+	assert.Len(t, ob.Asks, 1)
+
+	assert.True(t, ob.Bids[0].Price.Equal(decimal.RequireFromString("2000.01")), "Bid price mismatch")
+	assert.True(t, ob.Bids[1].Amount.Equal(decimal.RequireFromString("4.25")), "Bid amount mismatch")
+	assert.True(t, ob.Asks[0].Price.Equal(decimal.RequireFromString("2000.02")), "Ask price mismatch")
+	assert.True(t, ob.Asks[0].Amount.Equal(decimal.RequireFromString("0.75")), "Ask amount mismatch")
+}
+
+func TestGetOrderBook_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/products" {
+			_, _ = fmt.Fprintln(w, productsResponse)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprintln(w, `{"message":"NotFound"}`)
+	}))
+	defer ts.Close()
+
+	adapter := NewAdapter(ts.URL, false)
+	_, err := adapter.GetOrderBook(context.Background(), "ETHUSD")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "coinbase api returned status: 404 (NotFound)")
+}
+
+func TestGetInstrument(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, productsResponse)
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	adapter := NewAdapter(ts.URL, false).(*Adapter)
+	inst, err := adapter.GetInstrument(ctx, "ETHUSD")
+	require.NoError(t, err)
+	assert.Equal(t, "ETH-USD", inst.VenueSymbol)
+	assert.Empty(t, inst.QuoteAlias)
+	assert.True(t, inst.MinNotional.Equal(decimal.NewFromInt(1)))
+
+	_, err = adapter.GetInstrument(ctx, "ETHUSDC")
+	assert.ErrorIs(t, err, domain.ErrInstrumentNotListed, "USD books only serve USDC when asked to")
+	_, err = adapter.GetInstrument(ctx, "ETHUSDT")
+	assert.ErrorIs(t, err, domain.ErrInstrumentNotListed)
+	_, err = adapter.GetInstrument(ctx, "SOLUSD")
+	assert.ErrorIs(t, err, domain.ErrInstrumentNotListed, "products with malformed increments are skipped")
+
+	adapter = NewAdapter(ts.URL, true).(*Adapter)
+	inst, err = adapter.GetInstrument(ctx, "ETHUSDC")
+	require.NoError(t, err)
+	assert.Equal(t, "ETH-USD", inst.VenueSymbol, "USDC resolves to the unified USD book")
+	assert.Equal(t, "USD", inst.Quote)
+	assert.Equal(t, "USDC", inst.QuoteAlias)
+
+	inst, err = adapter.GetInstrument(ctx, "BTCUSDC")
+	require.NoError(t, err)
+	assert.Equal(t, "BTC-USDC", inst.VenueSymbol, "listed USDC products win over the alias")
+	assert.Empty(t, inst.QuoteAlias)
+}
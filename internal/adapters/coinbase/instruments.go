@@ -0,0 +1,102 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+)
+
+type product struct {
+	ID              string `json:"id"`
+	BaseCurrency    string `json:"base_currency"`
+	QuoteCurrency   string `json:"quote_currency"`
+	QuoteIncrement  string `json:"quote_increment"`
+	BaseIncrement   string `json:"base_increment"`
+	BaseMinSize     string `json:"base_min_size"`
+	MinMarketFunds  string `json:"min_market_funds"`
+	Status          string `json:"status"`
+	TradingDisabled bool   `json:"trading_disabled"`
+}
+
+// GetInstrument returns the product metadata for symbol.
+func (a *Adapter) GetInstrument(ctx context.Context, symbol string) (*domain.Instrument, error) {
+	return a.instruments.Lookup(ctx, symbol)
+}
+
+// loadInstruments fetches /products. Coinbase folded its USDC books into USD;
+// if usdcAlias is set, every USD product is also registered under its USDC
+// symbol, with QuoteAlias set, unless a dedicated USDC product is listed.
+func (a *Adapter) loadInstruments(ctx context.Context) ([]domain.Instrument, error) {
+	var products []product
+	if err := a.client.GetJSON(ctx, a.baseURL+"/products", &products); err != nil {
+		return nil, err
+	}
+
+	list := make([]domain.Instrument, 0, len(products))
+	var aliases []domain.Instrument
+	for _, p := range products {
+		if p.Status != "online" || p.TradingDisabled {
+			continue
+		}
+
+		inst, err := p.instrument()
+		if err != nil {
+			slog.Error("Skipping Coinbase product with malformed metadata", "product", p.ID, "error", err)
+			continue
+		}
+		list = append(list, inst)
+
+		if a.usdcAlias && p.QuoteCurrency == "USD" {
+			alias := inst
+			alias.Symbol = domain.CanonicalSymbol(p.BaseCurrency, "USDC")
+			alias.QuoteAlias = "USDC"
+			aliases = append(aliases, alias)
+		}
+	}
+
+	// The registry keeps the first instrument per symbol, so listed products
+	// take precedence over aliases.
+	return append(list, aliases...), nil
+}
+
+// instrument converts p. Increments are required; Coinbase leaves the
+// minimums empty on some products, which is read as no minimum.
+func (p product) instrument() (domain.Instrument, error) {
+	tick, err := decimal.NewFromString(p.QuoteIncrement)
+	if err != nil {
+		return domain.Instrument{}, fmt.Errorf("quote_increment: %w", err)
+	}
+	lot, err := decimal.NewFromString(p.BaseIncrement)
+	if err != nil {
+		return domain.Instrument{}, fmt.Errorf("base_increment: %w", err)
+	}
+	minQty, err := optionalDecimal(p.BaseMinSize)
+	if err != nil {
+		return domain.Instrument{}, fmt.Errorf("base_min_size: %w", err)
+	}
+	minNotional, err := optionalDecimal(p.MinMarketFunds)
+	if err != nil {
+		return domain.Instrument{}, fmt.Errorf("min_market_funds: %w", err)
+	}
+
+	return domain.Instrument{
+		Symbol:      domain.CanonicalSymbol(p.BaseCurrency, p.QuoteCurrency),
+		VenueSymbol: p.ID,
+		Base:        p.BaseCurrency,
+		Quote:       p.QuoteCurrency,
+		TickSize:    tick,
+		LotSize:     lot,
+		MinQty:      minQty,
+		MinNotional: minNotional,
+	}, nil
+}
+
+func optionalDecimal(s string) (decimal.Decimal, error) {
+	if s == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(s)
+}
@@ -29,9 +29,13 @@ type Adapter struct {
 	gasMu     sync.Mutex
 	gasPrice  *big.Int
 	gasExpiry time.Time
+
+	tokens *TokenRegistry
 }
 
-func NewAdapter(clientURL string) (ports.PriceProvider, error) {
+// NewAdapter connects to an Ethereum node. ERC-20 metadata resolved through
+// the adapter is cached at tokenCachePath; an empty path keeps it in memory.
+func NewAdapter(clientURL, tokenCachePath string) (ports.PriceProvider, error) {
 	client, err := ethclient.Dial(clientURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to ethereum node: %w", err)
@@ -42,12 +46,23 @@ func NewAdapter(clientURL string) (ports.PriceProvider, error) {
 		return nil, fmt.Errorf("failed to parse ABI: %w", err)
 	}
 
+	tokens, err := NewTokenRegistry(client, tokenCachePath)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Adapter{
 		client:    client,
 		parsedABI: parsed,
+		tokens:    tokens,
 	}, nil
 }
 
+// GetToken returns the ERC-20 metadata of the token at address.
+func (a *Adapter) GetToken(ctx context.Context, address string) (*domain.Token, error) {
+	return a.tokens.GetToken(ctx, address)
+}
+
 type QuoteExactInputSingleParams struct {
 	TokenIn           common.Address
 	TokenOut          common.Address
@@ -85,20 +100,20 @@ func (a *Adapter) GetQuote(ctx context.Context, tokenIn, tokenOut string, amount
 
 	result, err := a.client.CallContract(ctx, msg, nil)
 	if err != nil {
-		return nil, fmt.Errorf("eth_call failed: %w", err)
+		return nil, callError(err)
 	}
 
 	unpacked, err := a.parsedABI.Unpack("quoteExactInputSingle", result)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unpack result: %w", err)
+		return nil, fmt.Errorf("%w: %w", domain.ErrQuoteMalformed, err)
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to unpack result: %w", err)
+		return nil, fmt.Errorf("%w: %w", domain.ErrQuoteMalformed, err)
 	}
 
 	if len(unpacked) < 4 {
-		return nil, fmt.Errorf("unexpected result length")
+		return nil, fmt.Errorf("%w: unexpected result length", domain.ErrQuoteMalformed)
 	}
 
 	amountOut := unpacked[0].(*big.Int)
@@ -141,16 +156,16 @@ func (a *Adapter) GetQuoteExactOutput(ctx context.Context, tokenIn, tokenOut str
 
 	result, err := a.client.CallContract(ctx, msg, nil)
 	if err != nil {
-		return nil, fmt.Errorf("eth_call failed: %w", err)
+		return nil, callError(err)
 	}
 
 	unpacked, err := a.parsedABI.Unpack("quoteExactOutputSingle", result)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unpack result: %w", err)
+		return nil, fmt.Errorf("%w: %w", domain.ErrQuoteMalformed, err)
 	}
 
 	if len(unpacked) < 4 {
-		return nil, fmt.Errorf("unexpected result length")
+		return nil, fmt.Errorf("%w: unexpected result length", domain.ErrQuoteMalformed)
 	}
 
 	amountIn := unpacked[0].(*big.Int)
@@ -165,6 +180,15 @@ func (a *Adapter) GetQuoteExactOutput(ctx context.Context, tokenIn, tokenOut str
 	}, nil
 }
 
+// callError wraps a failed quoter call, telling reverts apart from node and
+// network errors.
+func callError(err error) error {
+	if strings.Contains(err.Error(), "execution reverted") {
+		return fmt.Errorf("%w: %w", domain.ErrQuoteReverted, err)
+	}
+	return fmt.Errorf("eth_call failed: %w", err)
+}
+
 func (a *Adapter) GetGasPrice(ctx context.Context) (*big.Int, error) {
 	a.gasMu.Lock()
 	defer a.gasMu.Unlock()
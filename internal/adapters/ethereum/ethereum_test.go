@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/shopspring/decimal"
@@ -51,7 +52,7 @@ func TestGetQuote(t *testing.T) {
 	defer ts.Close()
 
 	// Initialize Adapter
-	adapter, err := NewAdapter(ts.URL)
+	adapter, err := NewAdapter(ts.URL, "")
 	assert.NoError(t, err)
 
 	// Test
@@ -72,6 +73,32 @@ func TestGetQuote(t *testing.T) {
 	assert.Equal(t, expectedGas, quote.GasEstimate, "Gas estimate mismatch")
 }
 
+func TestGetQuote_Failures(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     error
+	}{
+		{"reverted", `{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted","data":"0x"}}`, domain.ErrQuoteReverted},
+		{"malformed", `{"jsonrpc":"2.0","id":1,"result":"0x1234"}`, domain.ErrQuoteMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintln(w, tt.response)
+			}))
+			defer ts.Close()
+
+			adapter, err := NewAdapter(ts.URL, "")
+			assert.NoError(t, err)
+
+			_, err = adapter.GetQuoteExactOutput(context.Background(), "0xUSDC", "0xWETH", big.NewInt(1e18), 3000)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
 func TestGetGasPrice(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Mock eth_gasPrice response
@@ -82,7 +109,7 @@ func TestGetGasPrice(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	adapter, err := NewAdapter(ts.URL)
+	adapter, err := NewAdapter(ts.URL, "")
 	assert.NoError(t, err)
 
 	gasPrice, err := adapter.GetGasPrice(context.Background())
@@ -100,7 +127,7 @@ func TestGetGasPrice_Caching(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	adapter, err := NewAdapter(ts.URL)
+	adapter, err := NewAdapter(ts.URL, "")
 	assert.NoError(t, err)
 
 	// First call
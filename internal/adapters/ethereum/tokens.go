@@ -0,0 +1,233 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/sync/singleflight"
+)
+
+// resolveTimeout bounds a metadata lookup. Lookups are shared between callers,
+// so they do not end with the context of the one that started them.
+const resolveTimeout = 30 * time.Second
+
+const erc20ABI = `[{"inputs":[],"name":"decimals","outputs":[{"internalType":"uint8","name":"","type":"uint8"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"symbol","outputs":[{"internalType":"string","name":"","type":"string"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"name","outputs":[{"internalType":"string","name":"","type":"string"}],"stateMutability":"view","type":"function"}]`
+
+// Some early tokens (e.g. MKR) return symbol and name as bytes32.
+const erc20Bytes32ABI = `[{"inputs":[],"name":"symbol","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"name","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"}]`
+
+// TokenRegistry resolves ERC-20 metadata on chain and caches it in memory and,
+// if a cache path is set, on disk. Token metadata is immutable in practice, so
+// cached entries never expire. Lookups run outside the lock, and concurrent
+// lookups of the same token share a single one.
+type TokenRegistry struct {
+	caller       ethereum.ContractCaller
+	erc20        abi.ABI
+	erc20Bytes32 abi.ABI
+	cachePath    string
+	resolving    singleflight.Group
+
+	mu     sync.Mutex
+	tokens map[common.Address]domain.Token
+
+	// saveMu orders cache writes, so that an older snapshot never replaces a
+	// newer one.
+	saveMu sync.Mutex
+}
+
+func NewTokenRegistry(caller ethereum.ContractCaller, cachePath string) (*TokenRegistry, error) {
+	parsed, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC-20 ABI: %w", err)
+	}
+	parsedBytes32, err := abi.JSON(strings.NewReader(erc20Bytes32ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC-20 bytes32 ABI: %w", err)
+	}
+
+	r := &TokenRegistry{
+		caller:       caller,
+		erc20:        parsed,
+		erc20Bytes32: parsedBytes32,
+		cachePath:    cachePath,
+		tokens:       make(map[common.Address]domain.Token),
+	}
+	if err := r.load(); err != nil {
+		slog.Warn("Ignoring unreadable token cache", "path", cachePath, "error", err)
+	}
+	return r, nil
+}
+
+// GetToken returns the metadata of the ERC-20 token at address.
+func (r *TokenRegistry) GetToken(ctx context.Context, address string) (*domain.Token, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid token address: %q", address)
+	}
+	addr := common.HexToAddress(address)
+
+	r.mu.Lock()
+	token, ok := r.tokens[addr]
+	r.mu.Unlock()
+	if ok {
+		return &token, nil
+	}
+
+	resolved := r.resolving.DoChan(addr.Hex(), func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), resolveTimeout)
+		defer cancel()
+
+		token, err := r.resolve(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		r.mu.Lock()
+		r.tokens[addr] = token
+		r.mu.Unlock()
+		if err := r.save(); err != nil {
+			slog.Warn("Failed to write token cache", "path", r.cachePath, "error", err)
+		}
+		return token, nil
+	})
+	select {
+	case res := <-resolved:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		token := res.Val.(domain.Token)
+		return &token, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *TokenRegistry) resolve(ctx context.Context, addr common.Address) (domain.Token, error) {
+	out, err := r.call(ctx, addr, "decimals")
+	if err != nil {
+		return domain.Token{}, err
+	}
+	unpacked, err := r.erc20.Unpack("decimals", out)
+	if err != nil {
+		return domain.Token{}, fmt.Errorf("failed to unpack decimals of %s: %w", addr.Hex(), err)
+	}
+
+	symbol, err := r.callString(ctx, addr, "symbol")
+	if err != nil {
+		return domain.Token{}, err
+	}
+	name, err := r.callString(ctx, addr, "name")
+	if err != nil {
+		return domain.Token{}, err
+	}
+
+	return domain.Token{
+		Address:  addr.Hex(),
+		Symbol:   symbol,
+		Name:     name,
+		Decimals: int32(unpacked[0].(uint8)),
+	}, nil
+}
+
+func (r *TokenRegistry) call(ctx context.Context, addr common.Address, method string) ([]byte, error) {
+	data, err := r.erc20.Pack(method)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+
+	out, err := r.caller.CallContract(ctx, ethereum.CallMsg{To: &addr, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s call on %s failed: %w", method, addr.Hex(), err)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%s call on %s returned no data, not an ERC-20 token", method, addr.Hex())
+	}
+	return out, nil
+}
+
+func (r *TokenRegistry) callString(ctx context.Context, addr common.Address, method string) (string, error) {
+	out, err := r.call(ctx, addr, method)
+	if err != nil {
+		return "", err
+	}
+
+	if unpacked, err := r.erc20.Unpack(method, out); err == nil {
+		return unpacked[0].(string), nil
+	}
+
+	unpacked, err := r.erc20Bytes32.Unpack(method, out)
+	if err != nil {
+		return "", fmt.Errorf("failed to unpack %s of %s: %w", method, addr.Hex(), err)
+	}
+	b := unpacked[0].([32]byte)
+	return string(bytes.TrimRight(b[:], "\x00")), nil
+}
+
+func (r *TokenRegistry) load() error {
+	if r.cachePath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(r.cachePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var cached map[string]domain.Token
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return err
+	}
+	for address, token := range cached {
+		if common.IsHexAddress(address) {
+			r.tokens[common.HexToAddress(address)] = token
+		}
+	}
+	return nil
+}
+
+// save writes the cache through a temporary file so that a crash never leaves
+// a truncated cache behind.
+func (r *TokenRegistry) save() error {
+	if r.cachePath == "" {
+		return nil
+	}
+
+	r.saveMu.Lock()
+	defer r.saveMu.Unlock()
+
+	r.mu.Lock()
+	cached := make(map[string]domain.Token, len(r.tokens))
+	for addr, token := range r.tokens {
+		cached[addr.Hex()] = token
+	}
+	r.mu.Unlock()
+
+	data, err := json.MarshalIndent(cached, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.cachePath), 0o755); err != nil {
+		return err
+	}
+	tmp := r.cachePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.cachePath)
+}
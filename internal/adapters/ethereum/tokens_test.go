@@ -0,0 +1,171 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const usdcAddress = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+
+// newERC20Server serves eth_call for decimals, symbol and name. String results
+// are ABI-encoded as strings, or as bytes32 when bytes32 is set.
+func newERC20Server(t *testing.T, decimals uint8, symbol, name string, bytes32 bool, calls *int) *httptest.Server {
+	t.Helper()
+
+	encodeString := func(s string) []byte {
+		if bytes32 {
+			return common.RightPadBytes([]byte(s), 32)
+		}
+		out := common.LeftPadBytes(big.NewInt(32).Bytes(), 32)
+		out = append(out, common.LeftPadBytes(big.NewInt(int64(len(s))).Bytes(), 32)...)
+		return append(out, common.RightPadBytes([]byte(s), (len(s)+31)/32*32)...)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var arg struct {
+			Input hexutil.Bytes `json:"input"`
+		}
+		require.NoError(t, json.Unmarshal(req.Params[0], &arg))
+		*calls++
+
+		var result []byte
+		switch hexutil.Encode(arg.Input[:4]) {
+		case "0x313ce567":
+			result = common.LeftPadBytes([]byte{decimals}, 32)
+		case "0x95d89b41":
+			result = encodeString(symbol)
+		case "0x06fdde03":
+			result = encodeString(name)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": hexutil.Encode(result)})
+	}))
+}
+
+func TestTokenRegistry_GetToken(t *testing.T) {
+	calls := 0
+	ts := newERC20Server(t, 6, "USDC", "USD Coin", false, &calls)
+	defer ts.Close()
+
+	client, err := ethclient.Dial(ts.URL)
+	require.NoError(t, err)
+
+	cachePath := filepath.Join(t.TempDir(), "tokens.json")
+	registry, err := NewTokenRegistry(client, cachePath)
+	require.NoError(t, err)
+
+	token, err := registry.GetToken(context.Background(), strings.ToLower(usdcAddress))
+	require.NoError(t, err)
+	assert.Equal(t, usdcAddress, token.Address)
+	assert.Equal(t, "USDC", token.Symbol)
+	assert.Equal(t, "USD Coin", token.Name)
+	assert.Equal(t, int32(6), token.Decimals)
+	assert.Equal(t, 3, calls)
+
+	// A fresh registry is served from the disk cache without touching the node.
+	cached, err := NewTokenRegistry(client, cachePath)
+	require.NoError(t, err)
+	token, err = cached.GetToken(context.Background(), usdcAddress)
+	require.NoError(t, err)
+	assert.Equal(t, int32(6), token.Decimals)
+	assert.Equal(t, 3, calls)
+}
+
+// gatedCaller holds the first call until release is closed.
+type gatedCaller struct {
+	ethereum.ContractCaller
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (c *gatedCaller) CallContract(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	c.once.Do(func() {
+		close(c.started)
+		<-c.release
+	})
+	return c.ContractCaller.CallContract(ctx, msg, block)
+}
+
+func TestTokenRegistry_ConcurrentLookups(t *testing.T) {
+	calls := 0
+	ts := newERC20Server(t, 6, "USDC", "USD Coin", false, &calls)
+	defer ts.Close()
+
+	client, err := ethclient.Dial(ts.URL)
+	require.NoError(t, err)
+
+	caller := &gatedCaller{ContractCaller: client, started: make(chan struct{}), release: make(chan struct{})}
+	registry, err := NewTokenRegistry(caller, filepath.Join(t.TempDir(), "tokens.json"))
+	require.NoError(t, err)
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	registry.tokens[weth] = domain.Token{Address: weth.Hex(), Symbol: "WETH", Decimals: 18}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := registry.GetToken(context.Background(), usdcAddress)
+			if assert.NoError(t, err) {
+				assert.Equal(t, int32(6), token.Decimals)
+			}
+		}()
+	}
+	<-caller.started
+
+	token, err := registry.GetToken(context.Background(), weth.Hex())
+	require.NoError(t, err, "cached tokens are served while a lookup is in flight")
+	assert.Equal(t, "WETH", token.Symbol)
+
+	close(caller.release)
+	wg.Wait()
+	assert.Equal(t, 3, calls, "concurrent lookups share one resolution")
+}
+
+func TestTokenRegistry_Bytes32Metadata(t *testing.T) {
+	calls := 0
+	ts := newERC20Server(t, 18, "MKR", "Maker", true, &calls)
+	defer ts.Close()
+
+	client, err := ethclient.Dial(ts.URL)
+	require.NoError(t, err)
+
+	registry, err := NewTokenRegistry(client, "")
+	require.NoError(t, err)
+
+	token, err := registry.GetToken(context.Background(), "0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")
+	require.NoError(t, err)
+	assert.Equal(t, "MKR", token.Symbol)
+	assert.Equal(t, "Maker", token.Name)
+	assert.Equal(t, int32(18), token.Decimals)
+}
+
+func TestTokenRegistry_InvalidAddress(t *testing.T) {
+	registry, err := NewTokenRegistry(nil, "")
+	require.NoError(t, err)
+
+	_, err = registry.GetToken(context.Background(), "0xWETH")
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package grpcapi
+
+import (
+	"fmt"
+	"time"
+
+	arbitragev1 "github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/api/arbitrage/v1"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	eventTypes = map[string]arbitragev1.EventType{
+		domain.EventHeartbeat:   arbitragev1.EventType_EVENT_TYPE_HEARTBEAT,
+		domain.EventOpportunity: arbitragev1.EventType_EVENT_TYPE_OPPORTUNITY,
+
+		domain.EventOpportunityOpened:  arbitragev1.EventType_EVENT_TYPE_OPPORTUNITY_OPENED,
+		domain.EventOpportunityUpdated: arbitragev1.EventType_EVENT_TYPE_OPPORTUNITY_UPDATED,
+		domain.EventOpportunityClosed:  arbitragev1.EventType_EVENT_TYPE_OPPORTUNITY_CLOSED,
+	}
+	directions = map[string]arbitragev1.Direction{
+		domain.DirectionCEXToDEX: arbitragev1.Direction_DIRECTION_CEX_TO_DEX,
+		domain.DirectionDEXToCEX: arbitragev1.Direction_DIRECTION_DEX_TO_CEX,
+	}
+)
+
+func toProtoEvent(e domain.ArbitrageEvent) *arbitragev1.ArbitrageEvent {
+	return &arbitragev1.ArbitrageEvent{
+		Seq:         e.Seq,
+		Type:        eventTypes[e.Type],
+		BlockNumber: e.BlockNumber,
+		Timestamp:   timestamp(e.Timestamp),
+		Data:        toProtoTrade(e.Data),
+		Opportunity: toProtoOpportunity(e.Opportunity),
+	}
+}
+
+func toProtoOpportunity(o *domain.Opportunity) *arbitragev1.Opportunity {
+	if o == nil {
+		return nil
+	}
+	return &arbitragev1.Opportunity{
+		Id:          o.ID,
+		Key:         o.Key,
+		OpenedBlock: o.OpenedBlock,
+		OpenedAt:    timestamp(o.OpenedAt),
+		LastBlock:   o.LastBlock,
+
+		Blocks:        o.Blocks,
+		PeakProfit:    o.PeakProfit,
+		PeakBlock:     o.PeakBlock,
+		AvgProfit:     o.AvgProfit,
+		ClosedBlock:   o.ClosedBlock,
+		ClosedAt:      timestamp(o.ClosedAt),
+		DecayPerBlock: o.DecayPerBlock,
+		ClosedBy:      o.ClosedBy,
+	}
+}
+
+func toProtoSummary(s domain.OpportunitySummary) *arbitragev1.OpportunitySummary {
+	closedBy := make(map[string]uint32, len(s.ClosedBy))
+	for cause, n := range s.ClosedBy {
+		closedBy[cause] = uint32(n)
+	}
+	return &arbitragev1.OpportunitySummary{
+		Symbol:             s.Symbol,
+		Venue:              s.Venue,
+		Closed:             uint32(s.Closed),
+		AvgBlocks:          s.AvgBlocks,
+		MaxBlocks:          s.MaxBlocks,
+		AvgDurationSeconds: s.AvgDuration.Seconds(),
+		AvgPeakProfit:      s.AvgPeakProfit,
+		MaxPeakProfit:      s.MaxPeakProfit,
+		AvgProfit:          s.AvgProfit,
+		AvgDecayPerBlock:   s.AvgDecayPerBlock,
+		ClosedBy:           closedBy,
+	}
+}
+
+func toProtoTrade(d *domain.TradeData) *arbitragev1.TradeData {
+	if d == nil {
+		return nil
+	}
+	return &arbitragev1.TradeData{
+		CexPrice:        d.CexPrice,
+		DexPrice:        d.DexPrice,
+		SpreadPct:       d.SpreadPct,
+		EstimatedProfit: d.EstimatedProfit,
+		GasCost:         d.GasCost,
+		Symbol:          d.Symbol,
+		Venue:           d.Venue,
+		Direction:       directions[d.Direction],
+		BookSkewMs:      d.BookSkewMs,
+		StaleBook:       d.StaleBook,
+		Size:            d.Size,
+		PoolFee:         d.PoolFee,
+	}
+}
+
+func toProtoEvaluation(e domain.Evaluation) *arbitragev1.Evaluation {
+	return &arbitragev1.Evaluation{
+		Symbol:      e.Symbol,
+		Venue:       e.Venue,
+		BlockNumber: e.BlockNumber,
+		Timestamp:   timestamp(e.Timestamp),
+		Best:        toProtoTrade(e.Best),
+		Error:       e.Err,
+	}
+}
+
+// fromProtoFilter converts and normalizes a request filter. A nil filter
+// matches everything.
+func fromProtoFilter(f *arbitragev1.EventFilter) (domain.EventFilter, error) {
+	filter := domain.EventFilter{
+		Symbols:   f.GetSymbols(),
+		Venues:    f.GetVenues(),
+		MinProfit: f.GetMinProfit(),
+	}
+	for _, t := range f.GetTypes() {
+		name, ok := lookup(eventTypes, t)
+		if !ok {
+			return filter, fmt.Errorf("%w: unknown event type %s", domain.ErrInvalidFilter, t)
+		}
+		filter.Types = append(filter.Types, name)
+	}
+	for _, d := range f.GetDirections() {
+		name, ok := lookup(directions, d)
+		if !ok {
+			return filter, fmt.Errorf("%w: unknown direction %s", domain.ErrInvalidFilter, d)
+		}
+		filter.Directions = append(filter.Directions, name)
+	}
+	return filter, filter.Normalize()
+}
+
+// lookup returns the domain name of an enum value.
+func lookup[E comparable](names map[string]E, value E) (string, bool) {
+	for name, v := range names {
+		if v == value {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// timestamp converts t, leaving zero times unset.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,228 @@
+// Package grpcapi serves the event stream and engine state over gRPC, as
+// defined in api/arbitrage/v1.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	arbitragev1 "github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/api/arbitrage/v1"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/analytics"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/auth"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/websocket"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/ports"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// keepaliveTime is how long a connection may be idle before the server pings
+// it, like the pings on /ws.
+const keepaliveTime = time.Minute
+
+// Server implements ArbitrageService. Streams are fed by the same broadcast
+// as the WebSocket and SSE endpoints, so sequences and replay are shared.
+type Server struct {
+	arbitragev1.UnimplementedArbitrageServiceServer
+
+	events *websocket.Server
+	status ports.StatusProvider
+	stats  ports.OpportunityStatsProvider
+	auth   *auth.Authenticator
+	grpc   *grpc.Server
+}
+
+func NewServer(events *websocket.Server, status ports.StatusProvider, stats ports.OpportunityStatsProvider, authn *auth.Authenticator) *Server {
+	s := &Server{events: events, status: status, stats: stats, auth: authn}
+	s.grpc = grpc.NewServer(
+		grpc.UnaryInterceptor(s.authorizeUnary),
+		grpc.StreamInterceptor(s.authorizeStream),
+		grpc.KeepaliveParams(keepalive.ServerParameters{Time: keepaliveTime}),
+	)
+	arbitragev1.RegisterArbitrageServiceServer(s.grpc, s)
+	return s
+}
+
+// Serve handles connections on lis until Stop is called.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+func (s *Server) Start(addr string) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		slog.Error("gRPC server failed", "error", err)
+		return
+	}
+	slog.Info("gRPC server starting", "addr", addr, "auth", s.auth.Enabled())
+	if err := s.Serve(lis); err != nil {
+		slog.Error("gRPC server failed", "error", err)
+	}
+}
+
+// Stop closes all connections, ending open streams.
+func (s *Server) Stop() {
+	s.grpc.Stop()
+}
+
+func (s *Server) Subscribe(req *arbitragev1.SubscribeRequest, stream arbitragev1.ArbitrageService_SubscribeServer) error {
+	filter, err := fromProtoFilter(req.GetFilter())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	var resumeFrom *uint64
+	if req.ResumeFrom != nil {
+		seq := req.GetResumeFrom()
+		resumeFrom = &seq
+	}
+
+	sub := s.events.Subscribe(filter, resumeFrom)
+	defer sub.Close()
+	slog.Info("New gRPC subscriber", "resume_from", resumeFrom)
+
+	if g := sub.Gap; g != nil {
+		gap := &arbitragev1.Gap{From: g.From, To: g.To, Reason: g.Reason}
+		if err := stream.Send(&arbitragev1.SubscribeResponse{Message: &arbitragev1.SubscribeResponse_Gap{Gap: gap}}); err != nil {
+			return err
+		}
+	}
+	for {
+		e, err := sub.Next(stream.Context())
+		switch {
+		case errors.Is(err, websocket.ErrSlowSubscriber):
+			return status.Error(codes.ResourceExhausted, "subscriber too slow, resume from the last seq received")
+		case errors.Is(err, websocket.ErrSubscriptionClosed):
+			return status.Error(codes.Unavailable, err.Error())
+		case err != nil:
+			return status.FromContextError(err).Err()
+		}
+		if err := stream.Send(&arbitragev1.SubscribeResponse{Message: &arbitragev1.SubscribeResponse_Event{Event: toProtoEvent(e)}}); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) GetStatus(context.Context, *arbitragev1.GetStatusRequest) (*arbitragev1.GetStatusResponse, error) {
+	st := s.status.Status()
+	return &arbitragev1.GetStatusResponse{
+		Venue:         st.Venue,
+		Symbols:       st.Symbols,
+		StartedAt:     timestamp(st.StartedAt),
+		LastBlock:     st.LastBlock,
+		LastBlockAt:   timestamp(st.LastBlockAt),
+		ActiveWorkers: int32(st.ActiveWorkers),
+		MaxWorkers:    int32(st.MaxWorkers),
+		MinProfit:     st.MinProfit.String(),
+		LastSeq:       s.events.LastSeq(),
+		Subscribers:   int32(s.events.Subscribers()),
+	}, nil
+}
+
+func (s *Server) GetLatestEvaluations(_ context.Context, req *arbitragev1.GetLatestEvaluationsRequest) (*arbitragev1.GetLatestEvaluationsResponse, error) {
+	symbols := make([]string, len(req.GetSymbols()))
+	for i, sym := range req.GetSymbols() {
+		symbols[i] = domain.NormalizeSymbol(sym)
+	}
+
+	resp := &arbitragev1.GetLatestEvaluationsResponse{}
+	for _, e := range s.status.LatestEvaluations() {
+		if len(symbols) > 0 && !slices.Contains(symbols, domain.NormalizeSymbol(e.Symbol)) {
+			continue
+		}
+		resp.Evaluations = append(resp.Evaluations, toProtoEvaluation(e))
+	}
+	return resp, nil
+}
+
+func (s *Server) GetOpportunityStats(_ context.Context, req *arbitragev1.GetOpportunityStatsRequest) (*arbitragev1.GetOpportunityStatsResponse, error) {
+	selected := pairVenueFilter(req.GetSymbols(), req.GetVenues())
+
+	resp := &arbitragev1.GetOpportunityStatsResponse{}
+	for _, sum := range s.stats.OpportunitySummaries() {
+		if selected(sum.Symbol, sum.Venue) {
+			resp.Summaries = append(resp.Summaries, toProtoSummary(sum))
+		}
+	}
+	// Take every recent close, as some may be filtered out.
+	limit := int(min(req.GetRecent(), analytics.MaxRecent))
+	for _, e := range s.stats.RecentOpportunities(analytics.MaxRecent) {
+		if len(resp.Recent) == limit {
+			break
+		}
+		if selected(e.Data.Symbol, e.Data.Venue) {
+			resp.Recent = append(resp.Recent, toProtoEvent(e))
+		}
+	}
+	return resp, nil
+}
+
+// pairVenueFilter reports whether a pair and venue are among those requested.
+func pairVenueFilter(requested, venues []string) func(symbol, venue string) bool {
+	symbols := make([]string, len(requested))
+	for i, sym := range requested {
+		symbols[i] = domain.NormalizeSymbol(sym)
+	}
+	return func(symbol, venue string) bool {
+		if len(symbols) > 0 && !slices.Contains(symbols, domain.NormalizeSymbol(symbol)) {
+			return false
+		}
+		return len(venues) == 0 || slices.ContainsFunc(venues, func(v string) bool { return strings.EqualFold(v, venue) })
+	}
+}
+
+// authorize checks the bearer token in the request metadata. Every RPC needs
+// the read scope.
+func (s *Server) authorize(ctx context.Context) (context.Context, error) {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			scheme, value, ok := strings.Cut(v[0], " ")
+			if ok && strings.EqualFold(scheme, "Bearer") {
+				token = strings.TrimSpace(value)
+			}
+		}
+	}
+
+	p, err := s.auth.AuthenticateToken(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if !p.Allows(auth.ScopeRead) {
+		return nil, status.Error(codes.PermissionDenied, "insufficient scope")
+	}
+	return auth.NewContext(ctx, p), nil
+}
+
+func (s *Server) authorizeUnary(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) authorizeStream(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorize(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorizedStream carries the caller's principal in its context.
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
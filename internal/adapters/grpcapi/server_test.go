@@ -0,0 +1,230 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	arbitragev1 "github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/api/arbitrage/v1"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/auth"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/websocket"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type stubStatus struct {
+	status domain.EngineStatus
+	evals  []domain.Evaluation
+}
+
+type stubStats struct {
+	summaries []domain.OpportunitySummary
+	recent    []domain.ArbitrageEvent
+}
+
+func (s *stubStats) OpportunitySummaries() []domain.OpportunitySummary { return s.summaries }
+func (s *stubStats) RecentOpportunities(limit int) []domain.ArbitrageEvent {
+	return s.recent[:min(limit, len(s.recent))]
+}
+
+func (s *stubStatus) Status() domain.EngineStatus            { return s.status }
+func (s *stubStatus) LatestEvaluations() []domain.Evaluation { return s.evals }
+
+func startServer(t *testing.T, events *websocket.Server, st *stubStatus, cfg auth.Config) arbitragev1.ArbitrageServiceClient {
+	t.Helper()
+	return startServerWithStats(t, events, st, &stubStats{}, cfg)
+}
+
+func startServerWithStats(t *testing.T, events *websocket.Server, st *stubStatus, stats *stubStats, cfg auth.Config) arbitragev1.ArbitrageServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := NewServer(events, st, stats, auth.New(cfg))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return arbitragev1.NewArbitrageServiceClient(conn)
+}
+
+func opportunity(symbol string, profit float64) domain.ArbitrageEvent {
+	return domain.ArbitrageEvent{
+		Type:        domain.EventOpportunity,
+		BlockNumber: 7,
+		Timestamp:   time.Unix(1_700_000_000, 0),
+		Data:        &domain.TradeData{Symbol: symbol, Venue: "binance", Direction: domain.DirectionCEXToDEX, EstimatedProfit: profit},
+	}
+}
+
+func TestSubscribe_FiltersAndResumes(t *testing.T) {
+	events := websocket.NewServer(auth.New(auth.Config{}))
+	client := startServer(t, events, &stubStatus{}, auth.Config{})
+
+	events.Broadcast(domain.ArbitrageEvent{Type: domain.EventHeartbeat, BlockNumber: 7})
+	events.Broadcast(opportunity("ETHUSDC", 100))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Subscribe(ctx, &arbitragev1.SubscribeRequest{
+		Filter: &arbitragev1.EventFilter{
+			Types:      []arbitragev1.EventType{arbitragev1.EventType_EVENT_TYPE_OPPORTUNITY},
+			Directions: []arbitragev1.Direction{arbitragev1.Direction_DIRECTION_CEX_TO_DEX},
+			MinProfit:  50,
+		},
+		ResumeFrom: new(uint64),
+	})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	e := resp.GetEvent()
+	require.NotNil(t, e, "replayed event")
+	assert.Equal(t, uint64(2), e.Seq)
+	assert.Equal(t, arbitragev1.EventType_EVENT_TYPE_OPPORTUNITY, e.Type)
+	assert.Equal(t, arbitragev1.Direction_DIRECTION_CEX_TO_DEX, e.Data.Direction)
+	assert.Equal(t, int64(1_700_000_000), e.Timestamp.GetSeconds())
+
+	require.Eventually(t, func() bool { return events.Subscribers() == 1 }, time.Second, 10*time.Millisecond)
+	events.Broadcast(opportunity("ETHUSDC", 10))
+	events.Broadcast(opportunity("ETHUSDC", 60))
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), resp.GetEvent().GetSeq())
+	assert.Equal(t, 60.0, resp.GetEvent().GetData().GetEstimatedProfit())
+}
+
+func TestSubscribe_ReportsGap(t *testing.T) {
+	events := websocket.NewServer(auth.New(auth.Config{}))
+	client := startServer(t, events, &stubStatus{}, auth.Config{})
+	events.Broadcast(opportunity("ETHUSDC", 100))
+
+	resumeFrom := uint64(9)
+	stream, err := client.Subscribe(context.Background(), &arbitragev1.SubscribeRequest{ResumeFrom: &resumeFrom})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "reset", resp.GetGap().GetReason())
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), resp.GetEvent().GetSeq())
+}
+
+func TestSubscribe_RejectsInvalidFilter(t *testing.T) {
+	client := startServer(t, websocket.NewServer(auth.New(auth.Config{})), &stubStatus{}, auth.Config{})
+
+	stream, err := client.Subscribe(context.Background(), &arbitragev1.SubscribeRequest{
+		Filter: &arbitragev1.EventFilter{Types: []arbitragev1.EventType{arbitragev1.EventType_EVENT_TYPE_UNSPECIFIED}},
+	})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestUnaryRPCs(t *testing.T) {
+	events := websocket.NewServer(auth.New(auth.Config{}))
+	started := time.Unix(1_700_000_000, 0)
+	st := &stubStatus{
+		status: domain.EngineStatus{
+			Venue:      "binance",
+			Symbols:    []string{"ETHUSDC"},
+			StartedAt:  started,
+			LastBlock:  42,
+			MaxWorkers: 5,
+			MinProfit:  decimal.RequireFromString("10.5"),
+		},
+		evals: []domain.Evaluation{
+			{Symbol: "BTCUSDC", BlockNumber: 41, Err: "cex fetch failed"},
+			{Symbol: "ETHUSDC", BlockNumber: 42, Best: opportunity("ETHUSDC", 25).Data},
+		},
+	}
+	client := startServer(t, events, st, auth.Config{ReadTokens: []string{"reader-token-0001"}})
+	events.Broadcast(opportunity("ETHUSDC", 25))
+
+	_, err := client.GetStatus(context.Background(), &arbitragev1.GetStatusRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer reader-token-0001")
+	resp, err := client.GetStatus(ctx, &arbitragev1.GetStatusRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "binance", resp.Venue)
+	assert.Equal(t, uint64(42), resp.LastBlock)
+	assert.Equal(t, "10.5", resp.MinProfit)
+	assert.Equal(t, uint64(1), resp.LastSeq)
+	assert.True(t, resp.StartedAt.AsTime().Equal(started))
+	assert.Nil(t, resp.LastBlockAt, "zero times are left unset")
+
+	evals, err := client.GetLatestEvaluations(ctx, &arbitragev1.GetLatestEvaluationsRequest{Symbols: []string{"eth-usdc"}})
+	require.NoError(t, err)
+	require.Len(t, evals.Evaluations, 1)
+	assert.Equal(t, uint64(42), evals.Evaluations[0].BlockNumber)
+	assert.Equal(t, 25.0, evals.Evaluations[0].Best.EstimatedProfit)
+
+	evals, err = client.GetLatestEvaluations(ctx, &arbitragev1.GetLatestEvaluationsRequest{})
+	require.NoError(t, err)
+	require.Len(t, evals.Evaluations, 2)
+	assert.Equal(t, "cex fetch failed", evals.Evaluations[0].Error)
+	assert.Nil(t, evals.Evaluations[0].Best)
+}
+
+func TestGetOpportunityStats(t *testing.T) {
+	closed := func(symbol, venue, id string) domain.ArbitrageEvent {
+		e := opportunity(symbol, 30)
+		e.Type = domain.EventOpportunityClosed
+		e.Data.Venue = venue
+		e.Opportunity = &domain.Opportunity{ID: id, Blocks: 3, PeakProfit: 80, ClosedBy: domain.ClosedByDEX, ClosedAt: e.Timestamp}
+		return e
+	}
+	stats := &stubStats{
+		summaries: []domain.OpportunitySummary{
+			{Symbol: "BTCUSDC", Venue: "binance", Closed: 1},
+			{Symbol: "ETHUSDC", Venue: "binance", Closed: 2, AvgBlocks: 2.5, AvgDuration: 30 * time.Second, ClosedBy: map[string]int{"dex": 2}},
+			{Symbol: "ETHUSDC", Venue: "kraken", Closed: 1},
+		},
+		recent: []domain.ArbitrageEvent{
+			closed("ETHUSDC", "binance", "c"),
+			closed("ETHUSDC", "kraken", "b"),
+			closed("ETHUSDC", "binance", "a"),
+		},
+	}
+	client := startServerWithStats(t, websocket.NewServer(auth.New(auth.Config{})), &stubStatus{}, stats, auth.Config{})
+
+	resp, err := client.GetOpportunityStats(context.Background(), &arbitragev1.GetOpportunityStatsRequest{
+		Symbols: []string{"eth-usdc"},
+		Venues:  []string{"Binance"},
+		Recent:  5,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Summaries, 1)
+	sum := resp.Summaries[0]
+	assert.Equal(t, uint32(2), sum.Closed)
+	assert.Equal(t, 2.5, sum.AvgBlocks)
+	assert.Equal(t, 30.0, sum.AvgDurationSeconds)
+	assert.Equal(t, map[string]uint32{"dex": 2}, sum.ClosedBy)
+
+	require.Len(t, resp.Recent, 2)
+	assert.Equal(t, "c", resp.Recent[0].Opportunity.Id)
+	assert.Equal(t, "a", resp.Recent[1].Opportunity.Id)
+	assert.Equal(t, arbitragev1.EventType_EVENT_TYPE_OPPORTUNITY_CLOSED, resp.Recent[0].Type)
+	assert.Equal(t, float64(80), resp.Recent[0].Opportunity.PeakProfit)
+	assert.Equal(t, "dex", resp.Recent[0].Opportunity.ClosedBy)
+
+	resp, err = client.GetOpportunityStats(context.Background(), &arbitragev1.GetOpportunityStatsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, resp.Summaries, 3)
+	assert.Empty(t, resp.Recent, "recent closes are only sent when asked for")
+}
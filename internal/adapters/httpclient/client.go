@@ -0,0 +1,354 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/observability"
+	"github.com/sony/gobreaker"
+	"golang.org/x/time/rate"
+)
+
+// ErrRateLimited is returned when a venue rejected a request for exceeding
+// its rate limit, and for requests made while its cool-down is still running.
+var ErrRateLimited = errors.New("rate limited by venue")
+
+const (
+	// defaultRateLimitWait is the cool-down after a 429 or rate-limit API
+	// error that carries no Retry-After header.
+	defaultRateLimitWait = time.Second
+
+	// defaultBanWait is the cool-down after a 418 (Binance IP ban) without a
+	// Retry-After header.
+	defaultBanWait = 2 * time.Minute
+
+	maxErrorBody = 4096
+)
+
+type Config struct {
+	// Venue names the breaker, log lines and metric labels.
+	Venue string
+
+	RateLimit rate.Limit
+	Burst     int
+
+	// Timeout bounds each attempt, including reading the response.
+	Timeout time.Duration
+
+	// MaxRetries is the number of extra attempts after transport errors,
+	// timeouts and 5xx responses. Backoff doubles from RetryBackoff up to
+	// MaxRetryBackoff, with full jitter.
+	MaxRetries      int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+
+	// Header is added to every request.
+	Header http.Header
+
+	// IsClientError reports venue-specific errors caused by the request
+	// itself. Like 4xx responses they do not count against the breaker.
+	IsClientError func(error) bool
+
+	// ErrorMessage extracts the venue's message from a non-200 response body.
+	ErrorMessage func(body []byte) string
+
+	// Budget, if set, meters requests by venue-specific cost on top of the
+	// token bucket.
+	Budget Budget
+}
+
+// Budget is implemented by venues that limit clients by request weight rather
+// than request count. Reserve is called before every attempt and may block
+// until the cost fits; Observe sees every response so the budget can follow
+// the usage reported by the venue.
+type Budget interface {
+	Reserve(ctx context.Context, rawURL string) error
+	Observe(resp *http.Response)
+}
+
+// DefaultConfig returns the settings shared by all venues: 10 req/s with a
+// burst of 5, 5s per attempt and two retries.
+func DefaultConfig(venue string) Config {
+	return Config{
+		Venue:           venue,
+		RateLimit:       10,
+		Burst:           5,
+		Timeout:         5 * time.Second,
+		MaxRetries:      2,
+		RetryBackoff:    100 * time.Millisecond,
+		MaxRetryBackoff: 2 * time.Second,
+	}
+}
+
+// Validator is implemented by response types that report API errors inside
+// a 200 response. Errors with a RateLimited() bool method that returns true
+// start the venue cool-down like a 429.
+type Validator interface {
+	Validate() error
+}
+
+// StatusError is a non-200 HTTP response.
+type StatusError struct {
+	Venue      string
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s api returned status: %d (%s)", strings.ToLower(e.Venue), e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%s api returned status: %d", strings.ToLower(e.Venue), e.StatusCode)
+}
+
+func (e *StatusError) Unwrap() error {
+	if e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusTeapot {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// Client is an HTTP client for a single venue's REST API. All requests share
+// the venue's token-bucket limiter, circuit breaker and rate-limit cool-down.
+type Client struct {
+	cfg     Config
+	http    *http.Client
+	cb      *gobreaker.CircuitBreaker
+	limiter *rate.Limiter
+
+	mu            sync.Mutex
+	cooldownUntil time.Time
+}
+
+func New(cfg Config) *Client {
+	c := &Client{
+		cfg:     cfg,
+		http:    &http.Client{},
+		limiter: rate.NewLimiter(cfg.RateLimit, cfg.Burst),
+	}
+
+	c.cb = gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:        cfg.Venue,
+		MaxRequests: 1,
+		Interval:    0,
+		Timeout:     30 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures > 3
+		},
+		IsSuccessful: c.isSuccessful,
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			observability.CEXBreakerState.WithLabelValues(name).Set(float64(to))
+		},
+	})
+	observability.CEXBreakerState.WithLabelValues(cfg.Venue).Set(float64(gobreaker.StateClosed))
+	return c
+}
+
+// GetJSON fetches url and decodes a 200 response into out. Transient failures
+// are retried; rate-limit responses are not, but start a cool-down during
+// which requests fail fast with ErrRateLimited.
+func (c *Client) GetJSON(ctx context.Context, url string, out any) error {
+	for attempt := 0; ; attempt++ {
+		err := c.attempt(ctx, url, out)
+		if err == nil || attempt >= c.cfg.MaxRetries || !c.retryable(ctx, err) {
+			return err
+		}
+
+		observability.CEXRequestRetries.WithLabelValues(c.cfg.Venue).Inc()
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(c.backoff(attempt)):
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, url string, out any) error {
+	if until := c.cooldown(); !until.IsZero() {
+		observability.CEXRequestErrors.WithLabelValues(c.cfg.Venue, "rate_limited").Inc()
+		return fmt.Errorf("%w: %s cooling down until %s", ErrRateLimited, c.cfg.Venue, until.Format(time.RFC3339))
+	}
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limiter wait failed: %w", err)
+	}
+	if c.cfg.Budget != nil {
+		if err := c.cfg.Budget.Reserve(ctx, url); err != nil {
+			return fmt.Errorf("request budget wait failed: %w", err)
+		}
+	}
+
+	_, err := c.cb.Execute(func() (interface{}, error) {
+		return nil, c.do(ctx, url, out)
+	})
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		observability.CEXRequestErrors.WithLabelValues(c.cfg.Venue, "breaker_open").Inc()
+	}
+	return err
+}
+
+func (c *Client) do(ctx context.Context, url string, out any) error {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range c.cfg.Header {
+		req.Header[k] = v
+	}
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	observability.CEXRequestDuration.WithLabelValues(c.cfg.Venue).Observe(time.Since(start).Seconds())
+	if err != nil {
+		observability.CEXRequests.WithLabelValues(c.cfg.Venue, "error").Inc()
+		reason := "network"
+		if errors.Is(err, context.DeadlineExceeded) {
+			reason = "timeout"
+		}
+		observability.CEXRequestErrors.WithLabelValues(c.cfg.Venue, reason).Inc()
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	observability.CEXRequests.WithLabelValues(c.cfg.Venue, strconv.Itoa(resp.StatusCode)).Inc()
+	if c.cfg.Budget != nil {
+		c.cfg.Budget.Observe(resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return c.statusError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		observability.CEXRequestErrors.WithLabelValues(c.cfg.Venue, "decode").Inc()
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if v, ok := out.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			if isRateLimited(err) {
+				observability.CEXRequestErrors.WithLabelValues(c.cfg.Venue, "rate_limited").Inc()
+				c.startCooldown(defaultRateLimitWait)
+			} else {
+				observability.CEXRequestErrors.WithLabelValues(c.cfg.Venue, "api").Inc()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) statusError(resp *http.Response) error {
+	serr := &StatusError{Venue: c.cfg.Venue, StatusCode: resp.StatusCode}
+	if c.cfg.ErrorMessage != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		serr.Message = c.cfg.ErrorMessage(body)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		serr.RetryAfter = retryAfter(resp.Header, defaultRateLimitWait)
+	case http.StatusTeapot:
+		serr.RetryAfter = retryAfter(resp.Header, defaultBanWait)
+	default:
+		observability.CEXRequestErrors.WithLabelValues(c.cfg.Venue, "status").Inc()
+		return serr
+	}
+
+	observability.CEXRequestErrors.WithLabelValues(c.cfg.Venue, "rate_limited").Inc()
+	c.startCooldown(serr.RetryAfter)
+	return serr
+}
+
+func (c *Client) cooldown() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Now().Before(c.cooldownUntil) {
+		return c.cooldownUntil
+	}
+	return time.Time{}
+}
+
+func (c *Client) startCooldown(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if until := time.Now().Add(d); until.After(c.cooldownUntil) {
+		c.cooldownUntil = until
+	}
+}
+
+// isSuccessful decides what counts against the breaker: only failures that
+// point at the venue itself. Rate limits are handled by the cool-down.
+func (c *Client) isSuccessful(err error) bool {
+	if err == nil || isRateLimited(err) || errors.Is(err, context.Canceled) {
+		return true
+	}
+	var serr *StatusError
+	if errors.As(err, &serr) && serr.StatusCode >= 400 && serr.StatusCode < 500 && serr.StatusCode != http.StatusRequestTimeout {
+		return true
+	}
+	return c.cfg.IsClientError != nil && c.cfg.IsClientError(err)
+}
+
+func (c *Client) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || isRateLimited(err) {
+		return false
+	}
+	var serr *StatusError
+	if errors.As(err, &serr) {
+		return serr.StatusCode >= 500 || serr.StatusCode == http.StatusRequestTimeout
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// backoff returns a random delay in [0, RetryBackoff*2^attempt], capped at
+// MaxRetryBackoff.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.cfg.RetryBackoff << attempt
+	if d <= 0 || d > c.cfg.MaxRetryBackoff {
+		d = c.cfg.MaxRetryBackoff
+	}
+	return rand.N(d + 1)
+}
+
+func isRateLimited(err error) bool {
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+	var rl interface{ RateLimited() bool }
+	return errors.As(err, &rl) && rl.RateLimited()
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(h http.Header, fallback time.Duration) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return fallback
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return fallback
+}
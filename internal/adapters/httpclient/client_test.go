@@ -0,0 +1,162 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type payload struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+func (p payload) Validate() error {
+	if p.Error != "" {
+		return errors.New(p.Error)
+	}
+	return nil
+}
+
+func testConfig() Config {
+	cfg := DefaultConfig("test")
+	cfg.RateLimit = 1000
+	cfg.RetryBackoff = time.Millisecond
+	cfg.MaxRetryBackoff = 5 * time.Millisecond
+	return cfg
+}
+
+func TestGetJSON_RetriesServerErrors(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = fmt.Fprintln(w, `{"ok":true}`)
+	}))
+	defer ts.Close()
+
+	var out payload
+	err := New(testConfig()).GetJSON(context.Background(), ts.URL, &out)
+
+	require.NoError(t, err)
+	assert.True(t, out.OK)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestGetJSON_ClientErrorsAreNotRetried(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprintln(w, `{"error":"bad symbol"}`)
+	}))
+	defer ts.Close()
+
+	cfg := testConfig()
+	cfg.ErrorMessage = func(body []byte) string { return "bad symbol" }
+	c := New(cfg)
+
+	for i := 0; i < 6; i++ {
+		err := c.GetJSON(context.Background(), ts.URL, &payload{})
+
+		var serr *StatusError
+		require.ErrorAs(t, err, &serr)
+		assert.Equal(t, http.StatusBadRequest, serr.StatusCode)
+		assert.EqualError(t, err, "test api returned status: 400 (bad symbol)")
+	}
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls), "4xx responses must neither retry nor trip the breaker")
+}
+
+func TestGetJSON_RetryAfterStartsCooldown(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	c := New(testConfig())
+
+	err := c.GetJSON(context.Background(), ts.URL, &payload{})
+	var serr *StatusError
+	require.ErrorAs(t, err, &serr)
+	assert.Equal(t, time.Minute, serr.RetryAfter)
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	err = c.GetJSON(context.Background(), ts.URL, &payload{})
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "requests during the cool-down must not reach the venue")
+}
+
+func TestGetJSON_TeapotUsesBanWait(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer ts.Close()
+
+	c := New(testConfig())
+	err := c.GetJSON(context.Background(), ts.URL, &payload{})
+
+	var serr *StatusError
+	require.ErrorAs(t, err, &serr)
+	assert.Equal(t, defaultBanWait, serr.RetryAfter)
+	assert.WithinDuration(t, time.Now().Add(defaultBanWait), c.cooldown(), time.Second)
+}
+
+func TestGetJSON_BreakerOpensOnServerErrors(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	cfg := testConfig()
+	cfg.MaxRetries = 0
+	c := New(cfg)
+
+	for i := 0; i < 4; i++ {
+		_ = c.GetJSON(context.Background(), ts.URL, &payload{})
+	}
+	err := c.GetJSON(context.Background(), ts.URL, &payload{})
+
+	assert.ErrorIs(t, err, gobreaker.ErrOpenState)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+func TestGetJSON_Validate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, `{"error":"maintenance"}`)
+	}))
+	defer ts.Close()
+
+	err := New(testConfig()).GetJSON(context.Background(), ts.URL, &payload{})
+
+	assert.EqualError(t, err, "maintenance")
+}
+
+func TestRetryAfter(t *testing.T) {
+	h := http.Header{}
+	assert.Equal(t, time.Second, retryAfter(h, time.Second))
+
+	h.Set("Retry-After", "3")
+	assert.Equal(t, 3*time.Second, retryAfter(h, time.Second))
+
+	h.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.Equal(t, time.Duration(0), retryAfter(h, time.Second))
+
+	h.Set("Retry-After", "soon")
+	assert.Equal(t, time.Second, retryAfter(h, time.Second))
+}
@@ -0,0 +1,145 @@
+package instruments
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	DefaultTTL = time.Hour
+
+	// retryInterval bounds how often a failed load is retried so that a venue
+	// outage does not turn every lookup into a request.
+	retryInterval = 30 * time.Second
+)
+
+// Loader fetches the full instrument list of a venue.
+type Loader func(ctx context.Context) ([]domain.Instrument, error)
+
+// Registry caches the instruments of a single venue, keyed by canonical
+// symbol. The list is loaded on first use and refreshed after the TTL; a stale
+// list keeps being served if a refresh fails. Loads run outside the lock, and
+// concurrent lookups share a single one.
+type Registry struct {
+	venue string
+	ttl   time.Duration
+	load  Loader
+	loads singleflight.Group
+
+	mu          sync.Mutex
+	bySymbol    map[string]domain.Instrument
+	loadedAt    time.Time
+	lastErr     error
+	lastAttempt time.Time
+}
+
+func NewRegistry(venue string, ttl time.Duration, load Loader) *Registry {
+	return &Registry{
+		venue: venue,
+		ttl:   ttl,
+		load:  load,
+	}
+}
+
+// Lookup returns the instrument for a canonical symbol. Separators and case
+// are ignored, so ETH-USDC and ethusdc resolve like ETHUSDC.
+func (r *Registry) Lookup(ctx context.Context, symbol string) (*domain.Instrument, error) {
+	bySymbol, err := r.instruments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inst, ok := bySymbol[domain.NormalizeSymbol(symbol)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s on %s", domain.ErrInstrumentNotListed, symbol, r.venue)
+	}
+	return &inst, nil
+}
+
+// instruments returns the current list, waiting for a load when it is missing
+// or expired. The load is not tied to the context of the lookup that started
+// it, since others may be waiting on it; a lookup whose context ends stops
+// waiting and gets the stale list if there is one.
+func (r *Registry) instruments(ctx context.Context) (map[string]domain.Instrument, error) {
+	r.mu.Lock()
+	bySymbol, current, err := r.cached(time.Now())
+	r.mu.Unlock()
+	if current {
+		return bySymbol, err
+	}
+
+	loaded := r.loads.DoChan("", func() (any, error) {
+		return r.refresh(context.WithoutCancel(ctx))
+	})
+	select {
+	case res := <-loaded:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(map[string]domain.Instrument), nil
+	case <-ctx.Done():
+		if bySymbol != nil {
+			return bySymbol, nil
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// cached returns the list held at now, and whether it can be served without
+// a load: it is within its TTL, or the last load failed too recently to
+// retry. Must be called with r.mu held.
+func (r *Registry) cached(now time.Time) (map[string]domain.Instrument, bool, error) {
+	if r.bySymbol != nil && now.Sub(r.loadedAt) < r.ttl {
+		return r.bySymbol, true, nil
+	}
+	if r.lastErr != nil && now.Sub(r.lastAttempt) < retryInterval {
+		if r.bySymbol != nil {
+			return r.bySymbol, true, nil
+		}
+		return nil, true, r.lastErr
+	}
+	return r.bySymbol, false, nil
+}
+
+// refresh loads the list and swaps it in. On failure the previous list, if
+// any, is kept and returned.
+func (r *Registry) refresh(ctx context.Context) (map[string]domain.Instrument, error) {
+	started := time.Now()
+	list, err := r.load(ctx)
+
+	var bySymbol map[string]domain.Instrument
+	if err == nil {
+		bySymbol = make(map[string]domain.Instrument, len(list))
+		for _, inst := range list {
+			inst.Venue = r.venue
+			if _, dup := bySymbol[inst.Symbol]; !dup {
+				bySymbol[inst.Symbol] = inst
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastAttempt = started
+	if err != nil {
+		r.lastErr = fmt.Errorf("failed to load %s instruments: %w", r.venue, err)
+		if r.bySymbol != nil {
+			slog.Warn("Instrument refresh failed, serving cached list", "venue", r.venue, "error", err)
+			return r.bySymbol, nil
+		}
+		return nil, r.lastErr
+	}
+
+	r.bySymbol = bySymbol
+	r.loadedAt = started
+	r.lastErr = nil
+	slog.Info("Loaded instruments", "venue", r.venue, "count", len(bySymbol))
+	return bySymbol, nil
+}
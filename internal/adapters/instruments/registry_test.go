@@ -0,0 +1,98 @@
+package instruments
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Lookup(t *testing.T) {
+	calls := 0
+	r := NewRegistry("test", time.Hour, func(ctx context.Context) ([]domain.Instrument, error) {
+		calls++
+		return []domain.Instrument{
+			{Symbol: "ETHUSDC", VenueSymbol: "ETH-USDC", Base: "ETH", Quote: "USDC"},
+			{Symbol: "ETHUSDC", VenueSymbol: "ETH-USDC-ALIAS", Base: "ETH", Quote: "USDC"},
+		}, nil
+	})
+
+	inst, err := r.Lookup(context.Background(), "eth-usdc")
+	require.NoError(t, err)
+	assert.Equal(t, "ETH-USDC", inst.VenueSymbol)
+	assert.Equal(t, "test", inst.Venue)
+
+	_, err = r.Lookup(context.Background(), "BTCUSDC")
+	assert.ErrorIs(t, err, domain.ErrInstrumentNotListed)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRegistry_ServesStaleListOnRefreshFailure(t *testing.T) {
+	fail := false
+	r := NewRegistry("test", time.Nanosecond, func(ctx context.Context) ([]domain.Instrument, error) {
+		if fail {
+			return nil, errors.New("unavailable")
+		}
+		return []domain.Instrument{{Symbol: "ETHUSDC", VenueSymbol: "ETHUSDC"}}, nil
+	})
+
+	_, err := r.Lookup(context.Background(), "ETHUSDC")
+	require.NoError(t, err)
+
+	fail = true
+	time.Sleep(time.Millisecond)
+	inst, err := r.Lookup(context.Background(), "ETHUSDC")
+	require.NoError(t, err)
+	assert.Equal(t, "ETHUSDC", inst.VenueSymbol)
+}
+
+func TestRegistry_ThrottlesFailedLoads(t *testing.T) {
+	calls := 0
+	r := NewRegistry("test", time.Hour, func(ctx context.Context) ([]domain.Instrument, error) {
+		calls++
+		return nil, errors.New("unavailable")
+	})
+
+	_, err := r.Lookup(context.Background(), "ETHUSDC")
+	require.Error(t, err)
+	_, err = r.Lookup(context.Background(), "ETHUSDC")
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRegistry_LoadsOnceWithoutHoldingTheLock(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	r := NewRegistry("test", time.Hour, func(ctx context.Context) ([]domain.Instrument, error) {
+		calls.Add(1)
+		<-release
+		return []domain.Instrument{{Symbol: "ETHUSDC", VenueSymbol: "ETHUSDC"}}, nil
+	})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := r.Lookup(context.Background(), "ETHUSDC")
+			assert.NoError(t, err)
+		}()
+	}
+
+	// While the load is in flight, a lookup that gives up is not blocked.
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := r.Lookup(ctx, "ETHUSDC")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,84 @@
+package kraken
+
+import (
+	"context"
+	"strings"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+)
+
+type assetPairsResponse struct {
+	apiStatus
+	Result map[string]assetPair `json:"result"`
+}
+
+type assetPair struct {
+	Altname      string `json:"altname"`
+	Wsname       string `json:"wsname"`
+	Status       string `json:"status"`
+	PairDecimals int32  `json:"pair_decimals"`
+	LotDecimals  int32  `json:"lot_decimals"`
+	TickSize     string `json:"tick_size"`
+	OrderMin     string `json:"ordermin"`
+	CostMin      string `json:"costmin"`
+}
+
+// krakenAssets maps Kraken's legacy asset codes to their common names.
+var krakenAssets = map[string]string{
+	"XBT": "BTC",
+	"XDG": "DOGE",
+}
+
+// GetInstrument returns the AssetPairs metadata for symbol.
+func (a *Adapter) GetInstrument(ctx context.Context, symbol string) (*domain.Instrument, error) {
+	return a.instruments.Lookup(ctx, symbol)
+}
+
+func (a *Adapter) loadInstruments(ctx context.Context) ([]domain.Instrument, error) {
+	var pairs assetPairsResponse
+	if err := a.client.GetJSON(ctx, a.baseURL+"/0/public/AssetPairs", &pairs); err != nil {
+		return nil, err
+	}
+
+	list := make([]domain.Instrument, 0, len(pairs.Result))
+	for _, p := range pairs.Result {
+		if p.Status != "" && p.Status != "online" {
+			continue
+		}
+
+		// wsname is the only field carrying both assets without Kraken's
+		// X/Z prefixes (e.g. XETHZUSD is "ETH/USD").
+		base, quote, ok := strings.Cut(p.Wsname, "/")
+		if !ok {
+			continue
+		}
+		base, quote = krakenAsset(base), krakenAsset(quote)
+
+		tick, err := decimal.NewFromString(p.TickSize)
+		if err != nil {
+			tick = decimal.New(1, -p.PairDecimals)
+		}
+		minQty, _ := decimal.NewFromString(p.OrderMin)
+		minNotional, _ := decimal.NewFromString(p.CostMin)
+
+		list = append(list, domain.Instrument{
+			Symbol:      domain.CanonicalSymbol(base, quote),
+			VenueSymbol: p.Altname,
+			Base:        base,
+			Quote:       quote,
+			TickSize:    tick,
+			LotSize:     decimal.New(1, -p.LotDecimals),
+			MinQty:      minQty,
+			MinNotional: minNotional,
+		})
+	}
+	return list, nil
+}
+
+func krakenAsset(asset string) string {
+	if name, ok := krakenAssets[asset]; ok {
+		return name
+	}
+	return asset
+}
@@ -2,77 +2,120 @@ package kraken
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"strings"
 	"time"
 
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/clock"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/httpclient"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/instruments"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/orderbook"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/ports"
-	"github.com/shopspring/decimal"
 )
 
 const (
 	BaseURL = "https://api.kraken.com"
+
+	defaultDepth = 100
 )
 
-type Adapter struct {
-	client *http.Client
+// APIError carries the messages of a non-empty error array returned by the
+// Kraken API.
+type APIError struct {
+	Errors []string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("kraken api error: %v", e.Errors)
 }
 
-func NewAdapter() ports.ExchangeAdapter {
-	return &Adapter{
-		client: &http.Client{
-			Timeout: 5 * time.Second,
-		},
+// RateLimited reports whether the request was rejected for exceeding the
+// venue's rate limit.
+func (e *APIError) RateLimited() bool {
+	for _, msg := range e.Errors {
+		if strings.HasPrefix(msg, "EAPI:Rate limit exceeded") || strings.HasPrefix(msg, "EGeneral:Too many requests") {
+			return true
+		}
 	}
+	return false
 }
 
-type krakenDepthResponse struct {
-	Error  []string               `json:"error"`
-	Result map[string]krakenDepth `json:"result"`
+// isClientError reports whether err was caused by the request itself rather
+// than the venue, in which case it must not trip the circuit breaker.
+func isClientError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, msg := range apiErr.Errors {
+		if strings.HasPrefix(msg, "EQuery:") || strings.HasPrefix(msg, "EGeneral:Invalid arguments") {
+			return true
+		}
+	}
+	return false
 }
 
-type krakenDepth struct {
-	Asks [][]string `json:"asks"` // [price, volume, timestamp]
-	Bids [][]string `json:"bids"`
+// apiStatus is the error array shared by all public responses.
+type apiStatus struct {
+	Error []string `json:"error"`
 }
 
-func (a *Adapter) GetOrderBook(ctx context.Context, symbol string) (*domain.OrderBook, error) {
-	krakenSymbol := convertToKrakenSymbol(symbol)
+func (s apiStatus) Validate() error {
+	if len(s.Error) > 0 {
+		return &APIError{Errors: s.Error}
+	}
+	return nil
+}
 
-	url := fmt.Sprintf("%s/0/public/Depth?pair=%s&count=100", BaseURL, krakenSymbol)
+type Adapter struct {
+	client  *httpclient.Client
+	baseURL string
+	depth   *orderbook.DepthLimit
+	clock   *clock.Offset
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	instruments *instruments.Registry
+}
 
-	resp, err := a.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
+func NewAdapter(baseURL string) ports.ExchangeAdapter {
+	return newAdapter(baseURL)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+func newAdapter(baseURL string) *Adapter {
+	cfg := httpclient.DefaultConfig("kraken")
+	cfg.IsClientError = isClientError
+
+	a := &Adapter{
+		client:  httpclient.New(cfg),
+		baseURL: baseURL,
+		depth:   orderbook.NewDepthLimit("kraken", defaultDepth, 10, 25, 100, 250, 500),
 	}
+	a.clock = clock.NewOffset("kraken", a.serverTime)
+	a.instruments = instruments.NewRegistry("kraken", instruments.DefaultTTL, a.loadInstruments)
+	return a
+}
+
+type krakenDepthResponse struct {
+	apiStatus
+	Result map[string]krakenDepth `json:"result"`
+}
 
-	body, err := io.ReadAll(resp.Body)
+type krakenDepth struct {
+	Asks [][]any `json:"asks"` // [price, volume, timestamp], timestamp is a number
+	Bids [][]any `json:"bids"`
+}
+
+func (a *Adapter) GetOrderBook(ctx context.Context, symbol string) (*domain.OrderBook, error) {
+	inst, err := a.instruments.Lookup(ctx, symbol)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
 
 	var krakenResp krakenDepthResponse
-	if err := json.Unmarshal(body, &krakenResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if len(krakenResp.Error) > 0 {
-		return nil, fmt.Errorf("kraken api error: %v", krakenResp.Error)
+	url := fmt.Sprintf("%s/0/public/Depth?pair=%s&count=%d", a.baseURL, inst.VenueSymbol, a.depth.Levels())
+	if err := a.client.GetJSON(ctx, url, &krakenResp); err != nil {
+		return nil, err
 	}
 
 	var depth krakenDepth
@@ -81,62 +124,50 @@ func (a *Adapter) GetOrderBook(ctx context.Context, symbol string) (*domain.Orde
 		break
 	}
 
-	orderBook := &domain.OrderBook{
-		Asks:      make([]domain.PriceLevel, 0, len(depth.Asks)),
-		Bids:      make([]domain.PriceLevel, 0, len(depth.Bids)),
-		Timestamp: time.Now(),
+	bids, err := orderbook.ParseMixedLevels(depth.Bids)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bids: %w", err)
 	}
-
-	for _, ask := range depth.Asks {
-		if len(ask) < 2 {
-			continue
-		}
-		price, err := decimal.NewFromString(ask[0])
-		if err != nil {
-			continue
-		}
-		quantity, err := decimal.NewFromString(ask[1])
-		if err != nil {
-			continue
-		}
-		orderBook.Asks = append(orderBook.Asks, domain.PriceLevel{
-			Price:  price,
-			Amount: quantity,
-		})
+	asks, err := orderbook.ParseMixedLevels(depth.Asks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asks: %w", err)
 	}
 
-	for _, bid := range depth.Bids {
-		if len(bid) < 2 {
-			continue
-		}
-		price, err := decimal.NewFromString(bid[0])
-		if err != nil {
-			continue
-		}
-		quantity, err := decimal.NewFromString(bid[1])
-		if err != nil {
-			continue
-		}
-		orderBook.Bids = append(orderBook.Bids, domain.PriceLevel{
-			Price:  price,
-			Amount: quantity,
-		})
+	orderBook := &domain.OrderBook{
+		Asks:      asks,
+		Bids:      bids,
+		Timestamp: time.Now(),
 	}
 
 	return orderBook, nil
 }
 
-func convertToKrakenSymbol(symbol string) string {
-	switch symbol {
-	case "ETHUSDC":
-		return "XETHZUSD"
-	case "ETHUSD":
-		return "XETHZUSD"
-	case "BTCUSDC":
-		return "XXBTZUSD"
-	case "BTCUSD":
-		return "XXBTZUSD"
-	default:
-		return symbol
+// SetBookDepth sets the configured number of levels fetched per side.
+func (a *Adapter) SetBookDepth(levels int) { a.depth.Configure(levels) }
+
+// DeepenBook fetches the next depth tier from now on, up to the 500 levels Kraken serves.
+func (a *Adapter) DeepenBook() bool { return a.depth.Deepen() }
+
+// BookFilled lets a deepened depth relax back toward the configured one.
+func (a *Adapter) BookFilled() { a.depth.Filled() }
+
+// SyncClock re-estimates the offset to Kraken's server clock. Kraken reports
+// whole seconds, so the estimate is only good to about a second.
+func (a *Adapter) SyncClock(ctx context.Context) error {
+	return a.clock.Sync(ctx)
+}
+
+type timeResponse struct {
+	apiStatus
+	Result struct {
+		UnixTime int64 `json:"unixtime"`
+	} `json:"result"`
+}
+
+func (a *Adapter) serverTime(ctx context.Context) (time.Time, error) {
+	var resp timeResponse
+	if err := a.client.GetJSON(ctx, a.baseURL+"/0/public/Time", &resp); err != nil {
+		return time.Time{}, err
 	}
+	return time.Unix(resp.Result.UnixTime, 0), nil
 }
@@ -0,0 +1,79 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const assetPairsResponseJSON = `{
+	"error": [],
+	"result": {
+		"XETHZUSD": {"altname": "ETHUSD", "wsname": "ETH/USD", "status": "online", "pair_decimals": 2, "lot_decimals": 8, "tick_size": "0.01", "ordermin": "0.002", "costmin": "0.5"},
+		"ETHUSDC": {"altname": "ETHUSDC", "wsname": "ETH/USDC", "status": "online", "pair_decimals": 2, "lot_decimals": 8, "tick_size": "0.01", "ordermin": "0.002", "costmin": "0.5"},
+		"XXBTZUSD": {"altname": "XBTUSD", "wsname": "XBT/USD", "status": "online", "pair_decimals": 1, "lot_decimals": 8, "tick_size": "0.1", "ordermin": "0.0001", "costmin": "0.5"}
+	}
+}`
+
+func TestGetOrderBook_ResolvesSymbolFromAssetPairs(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/0/public/AssetPairs":
+			_, _ = fmt.Fprintln(w, assetPairsResponseJSON)
+		case "/0/public/Depth":
+			if r.URL.Query().Get("pair") != "ETHUSDC" {
+				t.Errorf("Expected pair ETHUSDC, got %s", r.URL.Query().Get("pair"))
+			}
+			_, _ = fmt.Fprintln(w, `{"error":[],"result":{"ETHUSDC":{"asks":[["2001.00","1.0",1700000000]],"bids":[["2000.00","2.0",1700000000]]}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	adapter := NewAdapter(ts.URL)
+	ob, err := adapter.GetOrderBook(context.Background(), "ETHUSDC")
+
+	require.NoError(t, err)
+	require.Len(t, ob.Bids, 1)
+	assert.True(t, ob.Bids[0].Price.Equal(decimal.RequireFromString("2000")))
+}
+
+func TestGetOrderBook_NotListed(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, assetPairsResponseJSON)
+	}))
+	defer ts.Close()
+
+	adapter := NewAdapter(ts.URL)
+	_, err := adapter.GetOrderBook(context.Background(), "SOLUSDC")
+
+	assert.ErrorIs(t, err, domain.ErrInstrumentNotListed)
+}
+
+func TestGetInstrument(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, assetPairsResponseJSON)
+	}))
+	defer ts.Close()
+
+	adapter := newAdapter(ts.URL)
+
+	inst, err := adapter.GetInstrument(context.Background(), "BTCUSD")
+	require.NoError(t, err)
+	assert.Equal(t, "XBTUSD", inst.VenueSymbol)
+	assert.Equal(t, "BTC", inst.Base)
+	assert.True(t, inst.TickSize.Equal(decimal.RequireFromString("0.1")))
+	assert.True(t, inst.LotSize.Equal(decimal.RequireFromString("0.00000001")))
+
+	inst, err = adapter.GetInstrument(context.Background(), "ETHUSDC")
+	require.NoError(t, err)
+	assert.Equal(t, "ETHUSDC", inst.VenueSymbol, "ETHUSDC must not resolve to the USD pair")
+}
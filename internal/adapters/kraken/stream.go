@@ -0,0 +1,480 @@
+package kraken
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/orderbook"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// WSURL is the public WebSocket v2 endpoint, the default for
+	// venues.kraken.ws_url.
+	WSURL = "wss://ws.kraken.com/v2"
+
+	defaultStreamDepth  = 100
+	streamChecksumDepth = 10
+	streamReadTimeout   = 30 * time.Second
+	streamSyncTimeout   = 5 * time.Second
+	streamMaxBackoff    = 30 * time.Second
+)
+
+var errChecksumMismatch = errors.New("book checksum mismatch")
+
+// StreamAdapter serves order books from local copies maintained from the
+// Kraken WebSocket v2 book channel. Every snapshot and update is verified
+// against the CRC32 checksum sent by Kraken; on mismatch the book is dropped
+// and the channel is resubscribed to obtain a fresh snapshot. A change of
+// depth also resubscribes, at the new depth.
+type StreamAdapter struct {
+	wsURL  string
+	rest   *Adapter
+	dialer *websocket.Dialer
+	depth  *orderbook.DepthLimit
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	books map[string]*streamBook
+}
+
+func NewStreamAdapter(wsURL, restURL string) *StreamAdapter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &StreamAdapter{
+		wsURL:  wsURL,
+		rest:   newAdapter(restURL),
+		dialer: websocket.DefaultDialer,
+		depth:  orderbook.NewDepthLimit("kraken", defaultStreamDepth, 10, 25, 100, 500, 1000),
+		ctx:    ctx,
+		cancel: cancel,
+		books:  make(map[string]*streamBook),
+	}
+}
+
+// GetOrderBook returns a copy of the in-memory book for symbol. The first call
+// for a symbol starts its stream; until the book is synced it falls back to
+// the REST Depth endpoint.
+func (s *StreamAdapter) GetOrderBook(ctx context.Context, symbol string) (*domain.OrderBook, error) {
+	book := s.book(symbol)
+
+	timer := time.NewTimer(streamSyncTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-book.ready:
+	case <-timer.C:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if ob, ok := book.orderBook(); ok {
+		ob.ExchangeTime = s.rest.clock.ToLocal(ob.ExchangeTime)
+		return ob, nil
+	}
+
+	slog.Warn("Kraken book stream not synced, falling back to REST", "symbol", symbol)
+	return s.rest.GetOrderBook(ctx, symbol)
+}
+
+// GetInstrument returns the AssetPairs metadata for symbol.
+func (s *StreamAdapter) GetInstrument(ctx context.Context, symbol string) (*domain.Instrument, error) {
+	return s.rest.GetInstrument(ctx, symbol)
+}
+
+// SyncClock re-estimates the offset to Kraken's server clock, which is
+// applied to book update times.
+func (s *StreamAdapter) SyncClock(ctx context.Context) error {
+	return s.rest.SyncClock(ctx)
+}
+
+// SetBookDepth sets the depth of the book subscription and of the REST
+// fallback.
+func (s *StreamAdapter) SetBookDepth(levels int) {
+	s.rest.SetBookDepth(levels)
+	s.depth.Configure(levels)
+}
+
+// DeepenBook resubscribes at the next depth tier, up to the 1000 levels the
+// book channel serves.
+func (s *StreamAdapter) DeepenBook() bool { return s.depth.Deepen() }
+
+// BookFilled lets a deepened subscription relax back toward the configured
+// depth.
+func (s *StreamAdapter) BookFilled() { s.depth.Filled() }
+
+// Close stops all book streams.
+func (s *StreamAdapter) Close() error {
+	s.cancel()
+	return nil
+}
+
+func (s *StreamAdapter) book(symbol string) *streamBook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	book, ok := s.books[symbol]
+	if !ok {
+		book = newStreamBook()
+		s.books[symbol] = book
+		go s.run(symbol, book)
+	}
+	return book
+}
+
+func (s *StreamAdapter) run(symbol string, book *streamBook) {
+	backoff := time.Second
+
+	for {
+		synced, err := s.stream(symbol, book)
+		book.invalidate()
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		if synced {
+			backoff = time.Second
+		}
+		slog.Warn("Kraken book stream interrupted, reconnecting", "symbol", symbol, "error", err, "backoff", backoff)
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > streamMaxBackoff {
+			backoff = streamMaxBackoff
+		}
+	}
+}
+
+type wsRequest struct {
+	Method string         `json:"method"`
+	Params map[string]any `json:"params"`
+}
+
+type wsMessage struct {
+	Method  string          `json:"method"`
+	Success *bool           `json:"success"`
+	Error   string          `json:"error"`
+	Channel string          `json:"channel"`
+	Type    string          `json:"type"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type instrumentData struct {
+	Pairs []struct {
+		Symbol         string `json:"symbol"`
+		PricePrecision int32  `json:"price_precision"`
+		QtyPrecision   int32  `json:"qty_precision"`
+	} `json:"pairs"`
+}
+
+type bookData struct {
+	Symbol    string      `json:"symbol"`
+	Bids      []bookLevel `json:"bids"`
+	Asks      []bookLevel `json:"asks"`
+	Checksum  uint32      `json:"checksum"`
+	Timestamp string      `json:"timestamp"` // updates only
+}
+
+type bookLevel struct {
+	Price json.Number `json:"price"`
+	Qty   json.Number `json:"qty"`
+}
+
+// stream runs a single connection until it fails. It reports whether the book
+// reached a synced state so the caller can reset its backoff.
+func (s *StreamAdapter) stream(symbol string, book *streamBook) (bool, error) {
+	inst, err := s.rest.GetInstrument(s.ctx, symbol)
+	if err != nil {
+		return false, err
+	}
+	wsSymbol := inst.Base + "/" + inst.Quote
+
+	conn, _, err := s.dialer.DialContext(s.ctx, s.wsURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("dial failed: %w", err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-s.ctx.Done():
+		case <-done:
+		}
+		_ = conn.Close()
+	}()
+
+	// The checksum is computed over prices and quantities formatted with the
+	// pair's precision, which is only published on the instrument channel.
+	if err := conn.WriteJSON(wsRequest{
+		Method: "subscribe",
+		Params: map[string]any{"channel": "instrument", "snapshot": true},
+	}); err != nil {
+		return false, fmt.Errorf("instrument subscribe failed: %w", err)
+	}
+
+	// subscribed is the depth of the current book subscription, 0 before
+	// the first.
+	subscribed := 0
+	subscribeBook := func(depth int) error {
+		if subscribed != 0 {
+			if err := conn.WriteJSON(wsRequest{
+				Method: "unsubscribe",
+				Params: map[string]any{"channel": "book", "symbol": []string{wsSymbol}, "depth": subscribed},
+			}); err != nil {
+				return fmt.Errorf("book unsubscribe failed: %w", err)
+			}
+		}
+		book.setDepth(depth)
+		if err := conn.WriteJSON(wsRequest{
+			Method: "subscribe",
+			Params: map[string]any{"channel": "book", "symbol": []string{wsSymbol}, "depth": depth},
+		}); err != nil {
+			return fmt.Errorf("book subscribe failed: %w", err)
+		}
+		subscribed = depth
+		return nil
+	}
+
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return book.isSynced(), fmt.Errorf("read failed: %w", err)
+		}
+
+		if msg.Success != nil && !*msg.Success {
+			return book.isSynced(), fmt.Errorf("%s failed: %s", msg.Method, msg.Error)
+		}
+		if depth := s.depth.Levels(); subscribed != 0 && depth != subscribed {
+			slog.Info("Kraken book depth changed, resubscribing", "symbol", wsSymbol, "depth", depth)
+			if err := subscribeBook(depth); err != nil {
+				return book.isSynced(), err
+			}
+		}
+		// Heartbeats arrive every second; book messages confirm the book as
+		// they are applied.
+		if msg.Channel != "book" {
+			book.confirm()
+		}
+
+		switch msg.Channel {
+		case "instrument":
+			if msg.Type != "snapshot" || book.hasPrecision() {
+				continue
+			}
+			var data instrumentData
+			if err := json.Unmarshal(msg.Data, &data); err != nil {
+				return false, fmt.Errorf("failed to parse instrument data: %w", err)
+			}
+			found := false
+			for _, p := range data.Pairs {
+				if p.Symbol == wsSymbol {
+					book.setPrecision(p.PricePrecision, p.QtyPrecision)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false, fmt.Errorf("%w: %s on kraken ws", domain.ErrInstrumentNotListed, wsSymbol)
+			}
+			if err := subscribeBook(s.depth.Levels()); err != nil {
+				return false, err
+			}
+		case "book":
+			var data []bookData
+			if err := json.Unmarshal(msg.Data, &data); err != nil {
+				return book.isSynced(), fmt.Errorf("failed to parse book data: %w", err)
+			}
+			for _, d := range data {
+				err := book.apply(msg.Type == "snapshot", d)
+				if errors.Is(err, errChecksumMismatch) {
+					slog.Warn("Kraken book checksum mismatch, resubscribing", "symbol", wsSymbol, "error", err)
+					if err := subscribeBook(subscribed); err != nil {
+						return true, err
+					}
+					break
+				}
+				if err != nil {
+					return book.isSynced(), err
+				}
+			}
+		}
+	}
+}
+
+type streamBook struct {
+	mu        sync.RWMutex
+	book      *orderbook.Book
+	updatedAt time.Time
+	eventTime time.Time
+	liveAt    time.Time
+	synced    bool
+	// depth is the subscribed depth; levels beyond it are no longer
+	// updated and must be dropped.
+	depth int
+
+	precisionSet   bool
+	pricePrecision int32
+	qtyPrecision   int32
+
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+func newStreamBook() *streamBook {
+	return &streamBook{
+		book:  orderbook.New(),
+		ready: make(chan struct{}),
+	}
+}
+
+func (b *streamBook) setPrecision(price, qty int32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pricePrecision = price
+	b.qtyPrecision = qty
+	b.precisionSet = true
+}
+
+func (b *streamBook) setDepth(depth int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.depth = depth
+}
+
+func (b *streamBook) hasPrecision() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.precisionSet
+}
+
+// apply applies a snapshot or update and verifies the resulting checksum.
+// Updates received before the first snapshot are ignored. A checksum mismatch
+// leaves the book unsynced until the next snapshot.
+func (b *streamBook) apply(snapshot bool, data bookData) error {
+	bids, err := parseBookLevels(data.Bids)
+	if err != nil {
+		return err
+	}
+	asks, err := parseBookLevels(data.Asks)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if snapshot {
+		b.book.Clear()
+		b.eventTime = time.Time{}
+	} else if !b.synced {
+		return nil
+	}
+
+	b.book.UpdateBids(bids)
+	b.book.UpdateAsks(asks)
+	b.book.Truncate(b.depth)
+	b.updatedAt = time.Now()
+	if ts, err := time.Parse(time.RFC3339Nano, data.Timestamp); err == nil {
+		b.eventTime = ts
+	}
+
+	if sum := checksum(b.book, b.pricePrecision, b.qtyPrecision); sum != data.Checksum {
+		b.synced = false
+		return fmt.Errorf("%w: expected %d, computed %d", errChecksumMismatch, data.Checksum, sum)
+	}
+
+	b.liveAt = b.updatedAt
+	if !b.synced {
+		b.synced = true
+		b.readyOnce.Do(func() { close(b.ready) })
+	}
+	return nil
+}
+
+// confirm records that the stream is alive. While synced, the book is then
+// known to be current even if no update changed it.
+func (b *streamBook) confirm() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.synced {
+		b.liveAt = time.Now()
+	}
+}
+
+func (b *streamBook) invalidate() {
+	b.mu.Lock()
+	b.synced = false
+	b.mu.Unlock()
+}
+
+func (b *streamBook) isSynced() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.synced
+}
+
+// orderBook returns a sorted copy of the book, or false if it is not synced.
+// ExchangeTime is the time of the last applied message, in Kraken's clock;
+// LiveAt is when the stream was last seen alive.
+func (b *streamBook) orderBook() (*domain.OrderBook, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.synced {
+		return nil, false
+	}
+	ob := b.book.OrderBook(b.updatedAt)
+	ob.ExchangeTime = b.eventTime
+	ob.LiveAt = b.liveAt
+	return ob, true
+}
+
+// checksum computes Kraken's v2 book checksum: the CRC32 of the top ten asks
+// (ascending) followed by the top ten bids (descending), each level written as
+// price then quantity with the decimal point and leading zeros removed.
+func checksum(book *orderbook.Book, pricePrecision, qtyPrecision int32) uint32 {
+	var sb strings.Builder
+	write := func(levels []domain.PriceLevel) {
+		for i := 0; i < len(levels) && i < streamChecksumDepth; i++ {
+			sb.WriteString(checksumField(levels[i].Price, pricePrecision))
+			sb.WriteString(checksumField(levels[i].Amount, qtyPrecision))
+		}
+	}
+	write(book.Asks())
+	write(book.Bids())
+	return crc32.ChecksumIEEE([]byte(sb.String()))
+}
+
+func checksumField(d decimal.Decimal, precision int32) string {
+	s := strings.Replace(d.StringFixed(precision), ".", "", 1)
+	return strings.TrimLeft(s, "0")
+}
+
+func parseBookLevels(raw []bookLevel) ([]domain.PriceLevel, error) {
+	levels := make([]domain.PriceLevel, 0, len(raw))
+	for _, l := range raw {
+		level, err := orderbook.ParseLevel(l.Price.String(), l.Qty.String())
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
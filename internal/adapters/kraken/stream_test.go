@@ -0,0 +1,284 @@
+package kraken
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/orderbook"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/ports"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func level(price, qty string) domain.PriceLevel {
+	return domain.PriceLevel{Price: decimal.RequireFromString(price), Amount: decimal.RequireFromString(qty)}
+}
+
+// bookMessage builds a book channel message whose checksum matches the state
+// of want after the levels have been applied.
+func bookMessage(t *testing.T, typ string, want *orderbook.Book, bids, asks []domain.PriceLevel) []byte {
+	t.Helper()
+
+	want.UpdateBids(bids)
+	want.UpdateAsks(asks)
+
+	toRaw := func(levels []domain.PriceLevel) []map[string]json.Number {
+		out := make([]map[string]json.Number, 0, len(levels))
+		for _, l := range levels {
+			out = append(out, map[string]json.Number{
+				"price": json.Number(l.Price.String()),
+				"qty":   json.Number(l.Amount.String()),
+			})
+		}
+		return out
+	}
+
+	msg, err := json.Marshal(map[string]any{
+		"channel": "book",
+		"type":    typ,
+		"data": []map[string]any{{
+			"symbol":   "ETH/USDC",
+			"bids":     toRaw(bids),
+			"asks":     toRaw(asks),
+			"checksum": checksum(want, 2, 8),
+		}},
+	})
+	require.NoError(t, err)
+	return msg
+}
+
+func TestChecksumField(t *testing.T) {
+	assert.Equal(t, "452852", checksumField(decimal.RequireFromString("45285.2"), 1))
+	assert.Equal(t, "100000", checksumField(decimal.RequireFromString("0.001"), 8))
+	assert.Equal(t, "200000", checksumField(decimal.RequireFromString("2000"), 2))
+}
+
+func TestStreamAdapter_ResubscribesOnChecksumMismatch(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+
+	upgrader := websocket.Upgrader{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/0/public/AssetPairs" {
+			_, _ = w.Write([]byte(assetPairsResponseJSON))
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		bookSubs := 0
+		for {
+			var req wsRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			channel, _ := req.Params["channel"].(string)
+
+			mu.Lock()
+			requests = append(requests, req.Method+":"+channel)
+			mu.Unlock()
+
+			switch {
+			case channel == "instrument":
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"channel":"instrument","type":"snapshot","data":{"assets":[],"pairs":[{"symbol":"ETH/USDC","price_precision":2,"qty_precision":8}]}}`))
+			case channel == "book" && req.Method == "subscribe":
+				bookSubs++
+				want := orderbook.New()
+				if bookSubs == 1 {
+					_ = conn.WriteMessage(websocket.TextMessage, bookMessage(t, "snapshot", want,
+						[]domain.PriceLevel{level("2000.00", "1.5")},
+						[]domain.PriceLevel{level("2001.00", "2.0")}))
+					_ = conn.WriteMessage(websocket.TextMessage, bookMessage(t, "update", want,
+						[]domain.PriceLevel{level("1999.50", "3.0")}, nil))
+					_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"channel":"book","type":"update","data":[{"symbol":"ETH/USDC","bids":[{"price":1999.0,"qty":1.0}],"asks":[],"checksum":1}]}`))
+				} else {
+					_ = conn.WriteMessage(websocket.TextMessage, bookMessage(t, "snapshot", want,
+						[]domain.PriceLevel{level("2100.00", "1.0")},
+						[]domain.PriceLevel{level("2101.00", "1.0")}))
+				}
+			}
+		}
+	}))
+	defer ts.Close()
+
+	adapter := NewStreamAdapter("ws"+strings.TrimPrefix(ts.URL, "http"), ts.URL)
+	defer func() {
+		_ = adapter.Close()
+	}()
+
+	require.Eventually(t, func() bool {
+		ob, ok := adapter.book("ETHUSDC").orderBook()
+		return ok && len(ob.Bids) == 1 && ob.Bids[0].Price.Equal(decimal.RequireFromString("2100"))
+	}, 2*time.Second, 20*time.Millisecond)
+
+	ob, err := adapter.GetOrderBook(context.Background(), "ETHUSDC")
+	require.NoError(t, err)
+	require.Len(t, ob.Asks, 1)
+	assert.True(t, ob.Asks[0].Price.Equal(decimal.RequireFromString("2101")))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"subscribe:instrument", "subscribe:book", "unsubscribe:book", "subscribe:book"}, requests)
+}
+
+func TestStreamAdapter_HeartbeatsKeepQuietBookCurrent(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/0/public/AssetPairs" {
+			_, _ = w.Write([]byte(assetPairsResponseJSON))
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		for {
+			var req wsRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			switch req.Params["channel"] {
+			case "instrument":
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"channel":"instrument","type":"snapshot","data":{"assets":[],"pairs":[{"symbol":"ETH/USDC","price_precision":2,"qty_precision":8}]}}`))
+			case "book":
+				want := orderbook.New()
+				_ = conn.WriteMessage(websocket.TextMessage, bookMessage(t, "snapshot", want,
+					[]domain.PriceLevel{level("2000.00", "1.5")},
+					[]domain.PriceLevel{level("2001.00", "2.0")}))
+
+				// The last change was a minute ago; since then only
+				// heartbeats arrive.
+				var update map[string]any
+				require.NoError(t, json.Unmarshal(bookMessage(t, "update", want, []domain.PriceLevel{level("1999.50", "3.0")}, nil), &update))
+				update["data"].([]any)[0].(map[string]any)["timestamp"] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339Nano)
+				_ = conn.WriteJSON(update)
+
+				go func() {
+					for {
+						if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"channel":"heartbeat"}`)); err != nil {
+							return
+						}
+						time.Sleep(20 * time.Millisecond)
+					}
+				}()
+			}
+		}
+	}))
+	defer ts.Close()
+
+	adapter := NewStreamAdapter("ws"+strings.TrimPrefix(ts.URL, "http"), ts.URL)
+	defer func() {
+		_ = adapter.Close()
+	}()
+
+	// Timestamp is when the last update was applied; only heartbeats can
+	// move LiveAt past it.
+	require.Eventually(t, func() bool {
+		ob, ok := adapter.book("ETHUSDC").orderBook()
+		return ok && len(ob.Bids) == 2 && ob.LiveAt.Sub(ob.Timestamp) > 100*time.Millisecond
+	}, 2*time.Second, 20*time.Millisecond)
+
+	ob, err := adapter.GetOrderBook(context.Background(), "ETHUSDC")
+	require.NoError(t, err)
+	assert.Greater(t, time.Since(ob.ExchangeTime), 50*time.Second, "the exchange time still tells when the book last changed")
+	assert.Less(t, time.Since(ob.Time()), time.Second, "the book is as old as the last heartbeat")
+}
+
+func TestStreamAdapter_SubscribesAtConfiguredDepth(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+
+	upgrader := websocket.Upgrader{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/0/public/AssetPairs" {
+			_, _ = w.Write([]byte(assetPairsResponseJSON))
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		var writeMu sync.Mutex
+		write := func(msg []byte) error {
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			return conn.WriteMessage(websocket.TextMessage, msg)
+		}
+		go func() {
+			for write([]byte(`{"channel":"heartbeat"}`)) == nil {
+				time.Sleep(20 * time.Millisecond)
+			}
+		}()
+
+		for {
+			var req wsRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			switch req.Params["channel"] {
+			case "instrument":
+				_ = write([]byte(`{"channel":"instrument","type":"snapshot","data":{"assets":[],"pairs":[{"symbol":"ETH/USDC","price_precision":2,"qty_precision":8}]}}`))
+			case "book":
+				mu.Lock()
+				requests = append(requests, fmt.Sprintf("%s:%v", req.Method, req.Params["depth"]))
+				mu.Unlock()
+				if req.Method == "subscribe" {
+					_ = write(bookMessage(t, "snapshot", orderbook.New(),
+						[]domain.PriceLevel{level("2000.00", "1.5")},
+						[]domain.PriceLevel{level("2001.00", "2.0")}))
+				}
+			}
+		}
+	}))
+	defer ts.Close()
+
+	adapter := NewStreamAdapter("ws"+strings.TrimPrefix(ts.URL, "http"), ts.URL)
+	defer func() {
+		_ = adapter.Close()
+	}()
+	require.Implements(t, (*ports.DepthAdjuster)(nil), adapter)
+
+	adapter.SetBookDepth(20)
+	_, err := adapter.GetOrderBook(context.Background(), "ETHUSDC")
+	require.NoError(t, err)
+
+	require.True(t, adapter.DeepenBook())
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(requests) == 3
+	}, 2*time.Second, 20*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"subscribe:25", "unsubscribe:25", "subscribe:100"}, requests, "20 rounds up to the 25 level tier")
+}
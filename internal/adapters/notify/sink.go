@@ -0,0 +1,352 @@
+// Package notify delivers events to outbound destinations such as chat
+// channels and webhooks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/ports"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/observability"
+)
+
+// Sink kinds.
+const (
+	KindWebhook  = "webhook"
+	KindSlack    = "slack"
+	KindTelegram = "telegram"
+)
+
+var ErrInvalidSink = errors.New("invalid notifier sink")
+
+const (
+	defaultQueueSize       = 100
+	defaultMaxAttempts     = 5
+	defaultRetryBackoff    = time.Second
+	defaultMaxRetryBackoff = time.Minute
+	defaultTimeout         = 10 * time.Second
+
+	maxErrorBody = 512
+)
+
+type Config struct {
+	Kind string
+	// Name labels log lines and metrics; it defaults to Kind.
+	Name string
+	// URL is the webhook or Slack incoming webhook URL, or the Telegram Bot
+	// API base URL.
+	URL string
+	// Secret signs webhook bodies.
+	Secret []byte
+	// BotToken and ChatID address Telegram messages.
+	BotToken string
+	ChatID   string
+
+	// Filter selects the events sent; without types only opportunity
+	// lifecycle events are. It must be normalized.
+	Filter domain.EventFilter
+	// Cooldown is the least time between messages about one opportunity.
+	// The close of an opportunity is sent whenever its opening or a change
+	// was, so receivers are not left with opportunities that never close.
+	Cooldown time.Duration
+	// Template is a text/template rendering the message from the
+	// domain.ArbitrageEvent. Empty uses the sink's default.
+	Template string
+
+	// QueueSize bounds the events waiting for delivery; more are dropped.
+	QueueSize int
+	// MaxAttempts is the number of tries per message. Backoff doubles from
+	// RetryBackoff up to MaxRetryBackoff, with full jitter. A wait asked for
+	// by the destination is honoured, also up to MaxRetryBackoff.
+	MaxAttempts     int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// Timeout bounds each attempt.
+	Timeout time.Duration
+}
+
+// request builds the HTTP request delivering one rendered message.
+type request func(ctx context.Context, msg []byte) (*http.Request, error)
+
+// Sink delivers the events matching its filter to one destination. Events
+// are queued and sent in order by Run, so a slow or failing destination
+// never holds up the broadcast.
+type Sink struct {
+	cfg     Config
+	tmpl    *template.Template
+	request request
+	// retryAfter reads a destination-specific wait from a 429 response.
+	retryAfter func(resp *http.Response, body []byte) time.Duration
+
+	http  *http.Client
+	queue chan domain.ArbitrageEvent
+
+	mu sync.Mutex
+	// sent records the last message about each opportunity key.
+	sent map[string]sentOpportunity
+}
+
+type sentOpportunity struct {
+	at time.Time
+	id string
+}
+
+// NewSink creates the sink described by cfg.
+func NewSink(cfg Config) (*Sink, error) {
+	switch cfg.Kind {
+	case KindWebhook:
+		return newWebhook(cfg)
+	case KindSlack:
+		return newSlack(cfg)
+	case KindTelegram:
+		return newTelegram(cfg)
+	}
+	return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidSink, cfg.Kind)
+}
+
+func newSink(cfg Config, defaultTemplate string, req request) (*Sink, error) {
+	if cfg.Name == "" {
+		cfg.Name = cfg.Kind
+	}
+	if len(cfg.Filter.Types) == 0 {
+		cfg.Filter.Types = []string{domain.EventOpportunityOpened, domain.EventOpportunityUpdated, domain.EventOpportunityClosed}
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+	if cfg.MaxRetryBackoff <= 0 {
+		cfg.MaxRetryBackoff = defaultMaxRetryBackoff
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	s := &Sink{
+		cfg:        cfg,
+		request:    req,
+		retryAfter: retryAfterHeader,
+		http:       &http.Client{},
+		queue:      make(chan domain.ArbitrageEvent, cfg.QueueSize),
+		sent:       make(map[string]sentOpportunity),
+	}
+	text := cfg.Template
+	if text == "" {
+		text = defaultTemplate
+	}
+	if text != "" {
+		tmpl, err := template.New(cfg.Name).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s template: %v", ErrInvalidSink, cfg.Name, err)
+		}
+		s.tmpl = tmpl
+	}
+	return s, nil
+}
+
+// Name returns the name the sink reports under.
+func (s *Sink) Name() string {
+	return s.cfg.Name
+}
+
+// Broadcast queues event for delivery if it matches the sink's filter and
+// cooldown. It never blocks; events arriving while the queue is full are
+// dropped.
+func (s *Sink) Broadcast(event domain.ArbitrageEvent) {
+	event, ok := s.admit(event)
+	if !ok {
+		return
+	}
+	select {
+	case s.queue <- event:
+	default:
+		observability.NotifierDeliveries.WithLabelValues(s.cfg.Name, "dropped").Inc()
+		slog.Warn("Notifier queue full, dropping event", "sink", s.cfg.Name, "block", event.BlockNumber)
+	}
+}
+
+// admit reports whether event should be sent, and returns it as it should
+// be sent.
+func (s *Sink) admit(event domain.ArbitrageEvent) (domain.ArbitrageEvent, bool) {
+	o := event.Opportunity
+	if o == nil {
+		return event, s.cfg.Filter.Matches(event)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, seen := s.sent[o.Key]
+	if event.Type == domain.EventOpportunityClosed {
+		// The rest of the filter applied to the opportunity when it was sent.
+		if !seen || last.id != o.ID || !slices.Contains(s.cfg.Filter.Types, event.Type) {
+			return event, false
+		}
+		s.sent[o.Key] = sentOpportunity{at: time.Now()}
+		return event, true
+	}
+	if last.id != o.ID && event.Type == domain.EventOpportunityUpdated && slices.Contains(s.cfg.Filter.Types, domain.EventOpportunityOpened) {
+		// Its opening was held back by the cooldown or the filter, so this
+		// is the first the destination hears of it, and it must pass the
+		// filter as an opening.
+		event.Type = domain.EventOpportunityOpened
+	}
+	if !s.cfg.Filter.Matches(event) || (seen && time.Since(last.at) < s.cfg.Cooldown) {
+		return event, false
+	}
+	s.sent[o.Key] = sentOpportunity{at: time.Now(), id: o.ID}
+	return event, true
+}
+
+// Run delivers queued events until ctx is cancelled.
+func (s *Sink) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-s.queue:
+			result := "sent"
+			if err := s.send(ctx, e); err != nil {
+				result = "failed"
+				if ctx.Err() == nil {
+					slog.Error("Notifier delivery failed", "sink", s.cfg.Name, "block", e.BlockNumber, "error", err)
+				}
+			}
+			observability.NotifierDeliveries.WithLabelValues(s.cfg.Name, result).Inc()
+		}
+	}
+}
+
+// send renders e and delivers it, retrying transient failures.
+func (s *Sink) send(ctx context.Context, e domain.ArbitrageEvent) error {
+	msg, err := s.render(e)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		wait, err := s.attempt(ctx, msg)
+		if err == nil {
+			return nil
+		}
+		if wait < 0 || attempt >= s.cfg.MaxAttempts {
+			return err
+		}
+		if wait == 0 {
+			wait = s.backoff(attempt - 1)
+		}
+		wait = min(wait, s.cfg.MaxRetryBackoff)
+
+		observability.NotifierRetries.WithLabelValues(s.cfg.Name).Inc()
+		slog.Debug("Retrying notifier delivery", "sink", s.cfg.Name, "attempt", attempt, "wait", wait, "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// render formats e with the sink's template, or as JSON if it has none.
+func (s *Sink) render(e domain.ArbitrageEvent) ([]byte, error) {
+	if s.tmpl == nil {
+		return json.Marshal(e)
+	}
+	var b bytes.Buffer
+	if err := s.tmpl.Execute(&b, e); err != nil {
+		return nil, fmt.Errorf("render template: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// attempt makes one delivery. On failure it returns how long to wait before
+// retrying: 0 for the default backoff, or -1 if retrying cannot help.
+func (s *Sink) attempt(ctx context.Context, msg []byte) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	req, err := s.request(ctx, msg)
+	if err != nil {
+		return -1, err
+	}
+	resp, err := s.http.Do(req)
+	if err != nil {
+		// The URL may embed a token, as Telegram's does.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, fmt.Errorf("%s request failed: %w", s.cfg.Name, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	switch {
+	case resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return s.retryAfter(resp, body), statusError(s.cfg.Name, resp.StatusCode, body)
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout:
+		return 0, statusError(s.cfg.Name, resp.StatusCode, body)
+	default:
+		return -1, statusError(s.cfg.Name, resp.StatusCode, body)
+	}
+}
+
+// backoff returns a random delay in [0, RetryBackoff*2^attempt], capped at
+// MaxRetryBackoff.
+func (s *Sink) backoff(attempt int) time.Duration {
+	d := s.cfg.RetryBackoff << attempt
+	if d <= 0 || d > s.cfg.MaxRetryBackoff {
+		d = s.cfg.MaxRetryBackoff
+	}
+	return rand.N(d + 1)
+}
+
+func statusError(name string, code int, body []byte) error {
+	if len(body) > 0 {
+		return fmt.Errorf("%s returned status %d: %s", name, code, bytes.TrimSpace(body))
+	}
+	return fmt.Errorf("%s returned status %d", name, code)
+}
+
+// retryAfterHeader reads a Retry-After header in seconds or as an HTTP date.
+func retryAfterHeader(resp *http.Response, _ []byte) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// Fanout broadcasts each event to every notifier in turn.
+type Fanout []ports.NotificationService
+
+func (f Fanout) Broadcast(event domain.ArbitrageEvent) {
+	for _, n := range f {
+		n.Broadcast(event)
+	}
+}
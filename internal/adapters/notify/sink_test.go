@@ -0,0 +1,291 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type received struct {
+	header http.Header
+	path   string
+	body   []byte
+}
+
+// destination records requests and answers them with the given statuses in
+// turn, then 200.
+func destination(t *testing.T, statuses ...int) (*httptest.Server, <-chan received, *atomic.Int32) {
+	t.Helper()
+	reqs := make(chan received, 10)
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		n := int(calls.Add(1))
+		if n <= len(statuses) && statuses[n-1] != http.StatusOK {
+			w.WriteHeader(statuses[n-1])
+			_, _ = w.Write([]byte(`{"ok":false,"description":"nope"}`))
+			return
+		}
+		reqs <- received{header: r.Header.Clone(), path: r.URL.Path, body: body}
+	}))
+	t.Cleanup(ts.Close)
+	return ts, reqs, &calls
+}
+
+func run(t *testing.T, s *Sink) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go s.Run(ctx)
+}
+
+func next(t *testing.T, reqs <-chan received) received {
+	t.Helper()
+	select {
+	case r := <-reqs:
+		return r
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a delivery")
+		return received{}
+	}
+}
+
+func opportunity(profit float64) domain.ArbitrageEvent {
+	return lifecycle(domain.EventOpportunityOpened, "op@19000000", profit)
+}
+
+func lifecycle(eventType, id string, profit float64) domain.ArbitrageEvent {
+	return domain.ArbitrageEvent{
+		Type:        eventType,
+		BlockNumber: 19_000_000,
+		Opportunity: &domain.Opportunity{ID: id, Key: "ETHUSDC/binance/CEX -> DEX/3000/1", OpenedBlock: 18_999_990},
+		Data: &domain.TradeData{
+			Symbol:          "ETHUSDC",
+			Venue:           "binance",
+			Direction:       domain.DirectionCEXToDEX,
+			CexPrice:        2000,
+			DexPrice:        2050,
+			SpreadPct:       2.5,
+			EstimatedProfit: profit,
+		},
+	}
+}
+
+func TestWebhook_SignsBody(t *testing.T) {
+	ts, reqs, _ := destination(t)
+	secret := []byte("webhook-secret")
+	s, err := NewSink(Config{Kind: KindWebhook, URL: ts.URL, Secret: secret, Filter: domain.EventFilter{MinProfit: 50}})
+	require.NoError(t, err)
+	run(t, s)
+
+	s.Broadcast(domain.ArbitrageEvent{Type: domain.EventHeartbeat, BlockNumber: 1})
+	s.Broadcast(domain.ArbitrageEvent{Type: domain.EventOpportunity, BlockNumber: 1, Data: opportunity(80).Data})
+	s.Broadcast(opportunity(10))
+	s.Broadcast(opportunity(75))
+
+	r := next(t, reqs)
+	sent := r.header.Get(TimestampHeader)
+	require.NotEmpty(t, sent)
+	assert.Equal(t, Sign(secret, sent, r.body), r.header.Get(SignatureHeader))
+	assert.Equal(t, "application/json", r.header.Get("Content-Type"))
+
+	var e domain.ArbitrageEvent
+	require.NoError(t, json.Unmarshal(r.body, &e))
+	assert.Equal(t, 75.0, e.Data.EstimatedProfit, "only lifecycle events above the minimum profit are sent")
+}
+
+func TestWebhook_TemplateIsPlainText(t *testing.T) {
+	ts, reqs, _ := destination(t)
+	s, err := NewSink(Config{Kind: KindWebhook, URL: ts.URL, Template: `{{.Data.Symbol}} opened`})
+	require.NoError(t, err)
+	run(t, s)
+
+	s.Broadcast(opportunity(42))
+
+	r := next(t, reqs)
+	assert.Equal(t, "ETHUSDC opened", string(r.body))
+	assert.Equal(t, "text/plain; charset=utf-8", r.header.Get("Content-Type"))
+}
+
+func TestSink_CapsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	delivered := make(chan struct{}, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		delivered <- struct{}{}
+	}))
+	t.Cleanup(ts.Close)
+
+	s, err := NewSink(Config{Kind: KindWebhook, URL: ts.URL, MaxRetryBackoff: 10 * time.Millisecond})
+	require.NoError(t, err)
+	run(t, s)
+
+	s.Broadcast(opportunity(42))
+
+	select {
+	case <-delivered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the retry to wait at most MaxRetryBackoff")
+	}
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestSlack_RetriesTransientFailures(t *testing.T) {
+	ts, reqs, calls := destination(t, http.StatusInternalServerError, http.StatusTooManyRequests)
+	s, err := NewSink(Config{Kind: KindSlack, URL: ts.URL, RetryBackoff: time.Millisecond, MaxRetryBackoff: time.Millisecond})
+	require.NoError(t, err)
+	run(t, s)
+
+	s.Broadcast(opportunity(42))
+
+	var msg struct{ Text string }
+	require.NoError(t, json.Unmarshal(next(t, reqs).body, &msg))
+	assert.Equal(t, "Opened: CEX -> DEX ETHUSDC on binance: est. profit 42.00, spread 2.500% (CEX 2000.00, DEX 2050.00) at block 19000000", msg.Text)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestTelegram_SendsTemplate(t *testing.T) {
+	ts, reqs, calls := destination(t, http.StatusBadRequest)
+	s, err := NewSink(Config{
+		Kind:         KindTelegram,
+		URL:          ts.URL,
+		BotToken:     "123:abc",
+		ChatID:       "-100200",
+		Template:     `{{.Data.Symbol}} +{{printf "%.0f" .Data.EstimatedProfit}}`,
+		RetryBackoff: time.Millisecond,
+	})
+	require.NoError(t, err)
+	run(t, s)
+
+	// The first message is rejected with a 400 and not retried.
+	s.Broadcast(opportunity(10))
+	s.Broadcast(opportunity(20))
+
+	r := next(t, reqs)
+	assert.Equal(t, "/bot123:abc/sendMessage", r.path)
+	var msg struct {
+		ChatID string `json:"chat_id"`
+		Text   string `json:"text"`
+	}
+	require.NoError(t, json.Unmarshal(r.body, &msg))
+	assert.Equal(t, "-100200", msg.ChatID)
+	assert.Equal(t, "ETHUSDC +20", msg.Text)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestSink_Cooldown(t *testing.T) {
+	ts, reqs, _ := destination(t)
+	s, err := NewSink(Config{Kind: KindWebhook, URL: ts.URL, Cooldown: time.Hour, Filter: domain.EventFilter{MinProfit: 50}})
+	require.NoError(t, err)
+	run(t, s)
+
+	s.Broadcast(lifecycle(domain.EventOpportunityOpened, "a", 60))
+	s.Broadcast(lifecycle(domain.EventOpportunityUpdated, "a", 90))
+	// Closes are sent for opportunities announced, even below the filter.
+	s.Broadcast(lifecycle(domain.EventOpportunityClosed, "a", 20))
+	// Within the cooldown, a reopening is held back along with its close.
+	s.Broadcast(lifecycle(domain.EventOpportunityOpened, "b", 70))
+	s.Broadcast(lifecycle(domain.EventOpportunityClosed, "b", 70))
+	// So is the close of an opportunity filtered out when it opened.
+	s.Broadcast(lifecycle(domain.EventOpportunityClosed, "c", 70))
+
+	var types []string
+	for range 2 {
+		var e domain.ArbitrageEvent
+		require.NoError(t, json.Unmarshal(next(t, reqs).body, &e))
+		types = append(types, e.Type+" "+e.Opportunity.ID)
+	}
+	assert.Equal(t, []string{"OPPORTUNITY_OPENED a", "OPPORTUNITY_CLOSED a"}, types)
+	select {
+	case r := <-reqs:
+		t.Fatalf("Unexpected delivery %s", r.body)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSink_ReopenWithinCooldown(t *testing.T) {
+	ts, reqs, _ := destination(t)
+	s, err := NewSink(Config{Kind: KindWebhook, URL: ts.URL, Cooldown: 50 * time.Millisecond})
+	require.NoError(t, err)
+	run(t, s)
+
+	s.Broadcast(lifecycle(domain.EventOpportunityOpened, "a", 60))
+	s.Broadcast(lifecycle(domain.EventOpportunityClosed, "a", 20))
+	// The reopening is held back by the cooldown; its first change after
+	// the cooldown announces it.
+	s.Broadcast(lifecycle(domain.EventOpportunityOpened, "b", 70))
+	time.Sleep(60 * time.Millisecond)
+	s.Broadcast(lifecycle(domain.EventOpportunityUpdated, "b", 90))
+	s.Broadcast(lifecycle(domain.EventOpportunityClosed, "b", 30))
+
+	var types []string
+	for range 4 {
+		var e domain.ArbitrageEvent
+		require.NoError(t, json.Unmarshal(next(t, reqs).body, &e))
+		types = append(types, e.Type+" "+e.Opportunity.ID)
+	}
+	assert.Equal(t, []string{"OPPORTUNITY_OPENED a", "OPPORTUNITY_CLOSED a", "OPPORTUNITY_OPENED b", "OPPORTUNITY_CLOSED b"}, types)
+}
+
+func TestTelegramRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": {"3"}}}
+	assert.Equal(t, 7*time.Second, telegramRetryAfter(resp, []byte(`{"ok":false,"error_code":429,"parameters":{"retry_after":7}}`)))
+	assert.Equal(t, 3*time.Second, telegramRetryAfter(resp, []byte(`{"ok":false}`)))
+}
+
+func TestSink_DropsWhenQueueFull(t *testing.T) {
+	s, err := NewSink(Config{Kind: KindWebhook, URL: "http://127.0.0.1:1", QueueSize: 1})
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Broadcast(opportunity(10))
+		s.Broadcast(opportunity(20))
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Broadcast blocked on a full queue")
+	}
+	assert.Len(t, s.queue, 1)
+}
+
+func TestNewSink_Validates(t *testing.T) {
+	for name, cfg := range map[string]Config{
+		"unknown kind":   {Kind: "pager"},
+		"no url":         {Kind: KindSlack},
+		"no chat":        {Kind: KindTelegram, BotToken: "123:abc"},
+		"bad template":   {Kind: KindSlack, URL: "http://x", Template: "{{.Data"},
+		"webhook no url": {Kind: KindWebhook, Secret: []byte("s")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewSink(cfg)
+			assert.ErrorIs(t, err, ErrInvalidSink)
+		})
+	}
+}
+
+type recorder struct{ events []domain.ArbitrageEvent }
+
+func (r *recorder) Broadcast(e domain.ArbitrageEvent) { r.events = append(r.events, e) }
+
+func TestFanout(t *testing.T) {
+	a, b := &recorder{}, &recorder{}
+	Fanout{a, b}.Broadcast(opportunity(1))
+	assert.Len(t, a.events, 1)
+	assert.Len(t, b.events, 1)
+}
@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// opportunityTemplate is the default chat message.
+const opportunityTemplate = `{{if eq .Type "OPPORTUNITY_OPENED"}}Opened: {{else if eq .Type "OPPORTUNITY_UPDATED"}}Changed: {{else if eq .Type "OPPORTUNITY_CLOSED"}}Closed: {{end}}{{with .Data}}{{.Direction}} {{.Symbol}}{{with .Venue}} on {{.}}{{end}}: est. profit {{printf "%.2f" .EstimatedProfit}}, spread {{printf "%.3f" .SpreadPct}}% (CEX {{printf "%.2f" .CexPrice}}, DEX {{printf "%.2f" .DexPrice}}){{if .StaleBook}}, stale book{{end}}{{end}} at block {{.BlockNumber}}{{if eq .Type "OPPORTUNITY_CLOSED"}}{{with .Opportunity}}, open since block {{.OpenedBlock}}{{end}}{{end}}`
+
+// newSlack posts the rendered message to a Slack incoming webhook.
+func newSlack(cfg Config) (*Sink, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("%w: slack webhook url is required", ErrInvalidSink)
+	}
+	return newSink(cfg, opportunityTemplate, func(ctx context.Context, msg []byte) (*http.Request, error) {
+		body, err := json.Marshal(map[string]string{"text": string(msg)})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+}
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultTelegramURL = "https://api.telegram.org"
+
+// newTelegram sends the rendered message as plain text through the Bot API.
+func newTelegram(cfg Config) (*Sink, error) {
+	if cfg.BotToken == "" || cfg.ChatID == "" {
+		return nil, fmt.Errorf("%w: telegram bot token and chat id are required", ErrInvalidSink)
+	}
+	base := strings.TrimSuffix(cfg.URL, "/")
+	if base == "" {
+		base = defaultTelegramURL
+	}
+	endpoint := base + "/bot" + cfg.BotToken + "/sendMessage"
+
+	s, err := newSink(cfg, opportunityTemplate, func(ctx context.Context, msg []byte) (*http.Request, error) {
+		body, err := json.Marshal(map[string]any{
+			"chat_id":                  cfg.ChatID,
+			"text":                     string(msg),
+			"disable_web_page_preview": true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.retryAfter = telegramRetryAfter
+	return s, nil
+}
+
+// telegramRetryAfter reads the wait the Bot API reports in the body of a 429.
+func telegramRetryAfter(resp *http.Response, body []byte) time.Duration {
+	var r struct {
+		Parameters struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	if json.Unmarshal(body, &r) == nil && r.Parameters.RetryAfter > 0 {
+		return time.Duration(r.Parameters.RetryAfter) * time.Second
+	}
+	return retryAfterHeader(resp, body)
+}
@@ -0,0 +1,55 @@
+package okx
+
+import (
+	"context"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+)
+
+type instrumentsResponse struct {
+	apiStatus
+	Data []struct {
+		InstID   string `json:"instId"`
+		BaseCcy  string `json:"baseCcy"`
+		QuoteCcy string `json:"quoteCcy"`
+		TickSz   string `json:"tickSz"`
+		LotSz    string `json:"lotSz"`
+		MinSz    string `json:"minSz"`
+		State    string `json:"state"`
+	} `json:"data"`
+}
+
+// GetInstrument returns the public instruments metadata for symbol.
+func (a *Adapter) GetInstrument(ctx context.Context, symbol string) (*domain.Instrument, error) {
+	return a.instruments.Lookup(ctx, symbol)
+}
+
+func (a *Adapter) loadInstruments(ctx context.Context) ([]domain.Instrument, error) {
+	var instResp instrumentsResponse
+	if err := a.client.GetJSON(ctx, a.baseURL+"/api/v5/public/instruments?instType=SPOT", &instResp); err != nil {
+		return nil, err
+	}
+
+	list := make([]domain.Instrument, 0, len(instResp.Data))
+	for _, d := range instResp.Data {
+		if d.State != "live" {
+			continue
+		}
+
+		tick, _ := decimal.NewFromString(d.TickSz)
+		lot, _ := decimal.NewFromString(d.LotSz)
+		minQty, _ := decimal.NewFromString(d.MinSz)
+
+		list = append(list, domain.Instrument{
+			Symbol:      domain.CanonicalSymbol(d.BaseCcy, d.QuoteCcy),
+			VenueSymbol: d.InstID,
+			Base:        d.BaseCcy,
+			Quote:       d.QuoteCcy,
+			TickSize:    tick,
+			LotSize:     lot,
+			MinQty:      minQty,
+		})
+	}
+	return list, nil
+}
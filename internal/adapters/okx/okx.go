@@ -2,36 +2,94 @@ package okx
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/clock"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/httpclient"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/instruments"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/orderbook"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/ports"
-	"github.com/shopspring/decimal"
 )
 
 const (
 	BaseURL = "https://www.okx.com"
+
+	codeRateLimited = "50011"
+
+	defaultDepth = 100
 )
 
+// APIError is a non-zero code returned by the OKX v5 API.
+type APIError struct {
+	Code string
+	Msg  string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("okx api error: %s - %s", e.Code, e.Msg)
+}
+
+// RateLimited reports whether the request was rejected for exceeding the
+// venue's rate limit.
+func (e *APIError) RateLimited() bool {
+	return e.Code == codeRateLimited
+}
+
+// isClientError reports whether err was caused by the request itself rather
+// than the venue, in which case it must not trip the circuit breaker. OKX
+// uses the 51xxx range for invalid parameters and instruments.
+func isClientError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && strings.HasPrefix(apiErr.Code, "51")
+}
+
+// apiStatus is the envelope shared by all v5 responses.
+type apiStatus struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func (s apiStatus) Validate() error {
+	if s.Code != "0" {
+		return &APIError{Code: s.Code, Msg: s.Msg}
+	}
+	return nil
+}
+
 type Adapter struct {
-	client *http.Client
+	client  *httpclient.Client
+	baseURL string
+	depth   *orderbook.DepthLimit
+	clock   *clock.Offset
+
+	instruments *instruments.Registry
+}
+
+func NewAdapter(baseURL string) ports.ExchangeAdapter {
+	return newAdapter(baseURL)
 }
 
-func NewAdapter() ports.ExchangeAdapter {
-	return &Adapter{
-		client: &http.Client{
-			Timeout: 5 * time.Second,
-		},
+func newAdapter(baseURL string) *Adapter {
+	cfg := httpclient.DefaultConfig("okx")
+	cfg.IsClientError = isClientError
+
+	a := &Adapter{
+		client:  httpclient.New(cfg),
+		baseURL: baseURL,
+		depth:   orderbook.NewDepthLimit("okx", defaultDepth, 20, 50, 100, 200, 400),
 	}
+	a.clock = clock.NewOffset("okx", a.serverTime)
+	a.instruments = instruments.NewRegistry("okx", instruments.DefaultTTL, a.loadInstruments)
+	return a
 }
 
 type okxResponse struct {
-	Code string    `json:"code"`
-	Msg  string    `json:"msg"`
+	apiStatus
 	Data []okxData `json:"data"`
 }
 
@@ -42,39 +100,15 @@ type okxData struct {
 }
 
 func (a *Adapter) GetOrderBook(ctx context.Context, symbol string) (*domain.OrderBook, error) {
-	okxSymbol := convertToOKXSymbol(symbol)
-
-	url := fmt.Sprintf("%s/api/v5/market/books?instId=%s&sz=100", BaseURL, okxSymbol)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := a.client.Do(req)
+	inst, err := a.instruments.Lookup(ctx, symbol)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
 
 	var okxResp okxResponse
-	if err := json.Unmarshal(body, &okxResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if okxResp.Code != "0" {
-		return nil, fmt.Errorf("okx api error: %s - %s", okxResp.Code, okxResp.Msg)
+	url := fmt.Sprintf("%s/api/v5/market/books?instId=%s&sz=%d", a.baseURL, inst.VenueSymbol, a.depth.Levels())
+	if err := a.client.GetJSON(ctx, url, &okxResp); err != nil {
+		return nil, err
 	}
 
 	if len(okxResp.Data) == 0 {
@@ -83,65 +117,67 @@ func (a *Adapter) GetOrderBook(ctx context.Context, symbol string) (*domain.Orde
 
 	data := okxResp.Data[0]
 
-	orderBook := &domain.OrderBook{
-		Asks:      make([]domain.PriceLevel, 0, len(data.Asks)),
-		Bids:      make([]domain.PriceLevel, 0, len(data.Bids)),
-		Timestamp: time.Now(),
+	bids, err := orderbook.ParseLevels(data.Bids)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bids: %w", err)
 	}
-
-	for _, ask := range data.Asks {
-		if len(ask) < 2 {
-			continue
-		}
-		price, err := decimal.NewFromString(ask[0])
-		if err != nil {
-			continue
-		}
-		quantity, err := decimal.NewFromString(ask[1])
-		if err != nil {
-			continue
-		}
-		orderBook.Asks = append(orderBook.Asks, domain.PriceLevel{
-			Price:  price,
-			Amount: quantity,
-		})
+	asks, err := orderbook.ParseLevels(data.Asks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asks: %w", err)
 	}
 
-	for _, bid := range data.Bids {
-		if len(bid) < 2 {
-			continue
-		}
-		price, err := decimal.NewFromString(bid[0])
-		if err != nil {
-			continue
-		}
-		quantity, err := decimal.NewFromString(bid[1])
-		if err != nil {
-			continue
-		}
-		orderBook.Bids = append(orderBook.Bids, domain.PriceLevel{
-			Price:  price,
-			Amount: quantity,
-		})
+	orderBook := &domain.OrderBook{
+		Asks:         asks,
+		Bids:         bids,
+		Timestamp:    time.Now(),
+		ExchangeTime: a.clock.ToLocal(parseMillis(data.Ts)),
 	}
 
 	return orderBook, nil
 }
 
-func convertToOKXSymbol(symbol string) string {
-	switch symbol {
-	case "ETHUSDC":
-		return "ETH-USDC"
-	case "ETHUSD":
-		return "ETH-USD"
-	case "BTCUSDC":
-		return "BTC-USDC"
-	case "BTCUSD":
-		return "BTC-USD"
-	default:
-		if len(symbol) >= 6 {
-			return symbol[:len(symbol)-4] + "-" + symbol[len(symbol)-4:]
-		}
-		return symbol
+// SetBookDepth sets the configured number of levels fetched per side.
+func (a *Adapter) SetBookDepth(levels int) { a.depth.Configure(levels) }
+
+// DeepenBook fetches the next depth tier from now on, up to the 400 levels OKX serves.
+func (a *Adapter) DeepenBook() bool { return a.depth.Deepen() }
+
+// BookFilled lets a deepened depth relax back toward the configured one.
+func (a *Adapter) BookFilled() { a.depth.Filled() }
+
+// SyncClock re-estimates the offset to OKX's server clock.
+func (a *Adapter) SyncClock(ctx context.Context) error {
+	return a.clock.Sync(ctx)
+}
+
+type timeResponse struct {
+	apiStatus
+	Data []struct {
+		Ts string `json:"ts"`
+	} `json:"data"`
+}
+
+func (a *Adapter) serverTime(ctx context.Context) (time.Time, error) {
+	var resp timeResponse
+	if err := a.client.GetJSON(ctx, a.baseURL+"/api/v5/public/time", &resp); err != nil {
+		return time.Time{}, err
+	}
+	if len(resp.Data) == 0 {
+		return time.Time{}, fmt.Errorf("no data in response")
+	}
+	t := parseMillis(resp.Data[0].Ts)
+	if t.IsZero() {
+		return time.Time{}, fmt.Errorf("invalid server time %q", resp.Data[0].Ts)
+	}
+	return t, nil
+}
+
+// parseMillis parses the millisecond Unix timestamps OKX sends as strings,
+// returning the zero time if ts is missing or malformed.
+func parseMillis(ts string) time.Time {
+	ms, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}
 	}
+	return time.UnixMilli(ms)
 }
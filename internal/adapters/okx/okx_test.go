@@ -0,0 +1,65 @@
+package okx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const instrumentsResponseJSON = `{
+	"code": "0",
+	"msg": "",
+	"data": [
+		{"instId": "ETH-USDC", "baseCcy": "ETH", "quoteCcy": "USDC", "tickSz": "0.01", "lotSz": "0.000001", "minSz": "0.0001", "state": "live"},
+		{"instId": "ETH-USDT", "baseCcy": "ETH", "quoteCcy": "USDT", "tickSz": "0.01", "lotSz": "0.000001", "minSz": "0.0001", "state": "live"},
+		{"instId": "ETH-EUR", "baseCcy": "ETH", "quoteCcy": "EUR", "tickSz": "0.01", "lotSz": "0.000001", "minSz": "0.0001", "state": "suspend"}
+	]
+}`
+
+func TestGetOrderBook_ResolvesSymbolFromInstruments(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v5/public/instruments":
+			_, _ = fmt.Fprintln(w, instrumentsResponseJSON)
+		case "/api/v5/market/books":
+			if r.URL.Query().Get("instId") != "ETH-USDC" {
+				t.Errorf("Expected instId ETH-USDC, got %s", r.URL.Query().Get("instId"))
+			}
+			_, _ = fmt.Fprintln(w, `{"code":"0","msg":"","data":[{"asks":[["2001.00","1.0","0","1"]],"bids":[["2000.00","2.0","0","1"]],"ts":"1700000000000"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	adapter := NewAdapter(ts.URL)
+	ob, err := adapter.GetOrderBook(context.Background(), "ETHUSDC")
+
+	require.NoError(t, err)
+	require.Len(t, ob.Asks, 1)
+	assert.True(t, ob.Asks[0].Price.Equal(decimal.RequireFromString("2001")))
+	assert.Equal(t, time.UnixMilli(1700000000000), ob.ExchangeTime, "unsynced clock leaves the venue timestamp as is")
+}
+
+func TestGetOrderBook_NotListed(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, instrumentsResponseJSON)
+	}))
+	defer ts.Close()
+
+	adapter := NewAdapter(ts.URL)
+
+	_, err := adapter.GetOrderBook(context.Background(), "ETHEUR")
+	assert.ErrorIs(t, err, domain.ErrInstrumentNotListed, "suspended instruments are not listed")
+
+	_, err = adapter.GetOrderBook(context.Background(), "BTCUSDC")
+	assert.ErrorIs(t, err, domain.ErrInstrumentNotListed)
+}
@@ -0,0 +1,409 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/orderbook"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// WSURL is the public v5 endpoint, the default for venues.okx.ws_url.
+	WSURL = "wss://ws.okx.com:8443/ws/v5/public"
+
+	streamChecksumDepth = 25
+	streamPingInterval  = 20 * time.Second
+	streamReadTimeout   = 30 * time.Second
+	streamSyncTimeout   = 5 * time.Second
+	streamMaxBackoff    = 30 * time.Second
+)
+
+var (
+	errChecksumMismatch = errors.New("book checksum mismatch")
+	errSequenceGap      = errors.New("book sequence gap")
+)
+
+// StreamAdapter serves order books from local copies maintained from the OKX
+// public books channel. Updates are sequenced on seqId/prevSeqId and verified
+// against the per-message CRC32 checksum; a gap or mismatch drops the book and
+// resubscribes to obtain a fresh snapshot.
+type StreamAdapter struct {
+	wsURL  string
+	rest   *Adapter
+	dialer *websocket.Dialer
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	books map[string]*streamBook
+}
+
+func NewStreamAdapter(wsURL, restURL string) *StreamAdapter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &StreamAdapter{
+		wsURL:  wsURL,
+		rest:   newAdapter(restURL),
+		dialer: websocket.DefaultDialer,
+		ctx:    ctx,
+		cancel: cancel,
+		books:  make(map[string]*streamBook),
+	}
+}
+
+// GetOrderBook returns a copy of the in-memory book for symbol. The first call
+// for a symbol starts its stream; until the book is synced it falls back to
+// the REST books endpoint.
+func (s *StreamAdapter) GetOrderBook(ctx context.Context, symbol string) (*domain.OrderBook, error) {
+	book := s.book(symbol)
+
+	timer := time.NewTimer(streamSyncTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-book.ready:
+	case <-timer.C:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if ob, ok := book.orderBook(); ok {
+		ob.ExchangeTime = s.rest.clock.ToLocal(ob.ExchangeTime)
+		return ob, nil
+	}
+
+	slog.Warn("OKX book stream not synced, falling back to REST", "symbol", symbol)
+	return s.rest.GetOrderBook(ctx, symbol)
+}
+
+// GetInstrument returns the public instruments metadata for symbol.
+func (s *StreamAdapter) GetInstrument(ctx context.Context, symbol string) (*domain.Instrument, error) {
+	return s.rest.GetInstrument(ctx, symbol)
+}
+
+// SyncClock re-estimates the offset to OKX's server clock, which is
+// applied to book update times.
+func (s *StreamAdapter) SyncClock(ctx context.Context) error {
+	return s.rest.SyncClock(ctx)
+}
+
+// SetBookDepth sets the depth fetched while the stream is not synced. The
+// books channel always carries 400 levels, so the depth only applies to the
+// REST fallback.
+func (s *StreamAdapter) SetBookDepth(levels int) { s.rest.SetBookDepth(levels) }
+
+// DeepenBook deepens the REST fallback.
+func (s *StreamAdapter) DeepenBook() bool { return s.rest.DeepenBook() }
+
+// BookFilled lets a deepened REST fallback relax toward the configured depth.
+func (s *StreamAdapter) BookFilled() { s.rest.BookFilled() }
+
+// Close stops all book streams.
+func (s *StreamAdapter) Close() error {
+	s.cancel()
+	return nil
+}
+
+func (s *StreamAdapter) book(symbol string) *streamBook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	book, ok := s.books[symbol]
+	if !ok {
+		book = newStreamBook()
+		s.books[symbol] = book
+		go s.run(symbol, book)
+	}
+	return book
+}
+
+func (s *StreamAdapter) run(symbol string, book *streamBook) {
+	backoff := time.Second
+
+	for {
+		synced, err := s.stream(symbol, book)
+		book.invalidate()
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		if synced {
+			backoff = time.Second
+		}
+		slog.Warn("OKX book stream interrupted, reconnecting", "symbol", symbol, "error", err, "backoff", backoff)
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > streamMaxBackoff {
+			backoff = streamMaxBackoff
+		}
+	}
+}
+
+type wsArg struct {
+	Channel string `json:"channel"`
+	InstID  string `json:"instId"`
+}
+
+type wsRequest struct {
+	Op   string  `json:"op"`
+	Args []wsArg `json:"args"`
+}
+
+type wsMessage struct {
+	Event  string     `json:"event"`
+	Code   string     `json:"code"`
+	Msg    string     `json:"msg"`
+	Arg    wsArg      `json:"arg"`
+	Action string     `json:"action"`
+	Data   []bookData `json:"data"`
+}
+
+type bookData struct {
+	Asks      [][]string `json:"asks"` // [price, quantity, deprecated, num_orders]
+	Bids      [][]string `json:"bids"`
+	Ts        string     `json:"ts"`
+	Checksum  int32      `json:"checksum"`
+	PrevSeqID int64      `json:"prevSeqId"`
+	SeqID     int64      `json:"seqId"`
+}
+
+// stream runs a single connection until it fails. It reports whether the book
+// reached a synced state so the caller can reset its backoff.
+func (s *StreamAdapter) stream(symbol string, book *streamBook) (bool, error) {
+	inst, err := s.rest.GetInstrument(s.ctx, symbol)
+	if err != nil {
+		return false, err
+	}
+	instID := inst.VenueSymbol
+
+	conn, _, err := s.dialer.DialContext(s.ctx, s.wsURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("dial failed: %w", err)
+	}
+
+	var writeMu sync.Mutex
+	write := func(messageType int, data []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteMessage(messageType, data)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		// OKX drops connections that stay silent for 30 seconds.
+		ticker := time.NewTicker(streamPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.ctx.Done():
+				_ = conn.Close()
+				return
+			case <-done:
+				_ = conn.Close()
+				return
+			case <-ticker.C:
+				_ = write(websocket.TextMessage, []byte("ping"))
+			}
+		}
+	}()
+
+	subscribe := func(op string) error {
+		req, err := json.Marshal(wsRequest{Op: op, Args: []wsArg{{Channel: "books", InstID: instID}}})
+		if err != nil {
+			return err
+		}
+		return write(websocket.TextMessage, req)
+	}
+
+	if err := subscribe("subscribe"); err != nil {
+		return false, fmt.Errorf("subscribe failed: %w", err)
+	}
+
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			return book.isSynced(), fmt.Errorf("read failed: %w", err)
+		}
+		if string(raw) == "pong" {
+			book.confirm()
+			continue
+		}
+
+		var msg wsMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return book.isSynced(), fmt.Errorf("failed to parse message: %w", err)
+		}
+
+		if msg.Event == "error" {
+			return book.isSynced(), fmt.Errorf("okx ws error: %s - %s", msg.Code, msg.Msg)
+		}
+		if msg.Event != "" || msg.Arg.Channel != "books" {
+			book.confirm()
+			continue
+		}
+
+		for _, d := range msg.Data {
+			err := book.apply(msg.Action == "snapshot", d)
+			if errors.Is(err, errChecksumMismatch) || errors.Is(err, errSequenceGap) {
+				slog.Warn("OKX book out of sync, resubscribing", "instId", instID, "error", err)
+				if err := subscribe("unsubscribe"); err != nil {
+					return true, fmt.Errorf("unsubscribe failed: %w", err)
+				}
+				if err := subscribe("subscribe"); err != nil {
+					return true, fmt.Errorf("subscribe failed: %w", err)
+				}
+				break
+			}
+			if err != nil {
+				return book.isSynced(), err
+			}
+		}
+	}
+}
+
+type streamBook struct {
+	mu        sync.RWMutex
+	book      *orderbook.Book
+	seqID     int64
+	updatedAt time.Time
+	eventTime time.Time
+	liveAt    time.Time
+	synced    bool
+
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+func newStreamBook() *streamBook {
+	return &streamBook{
+		book:  orderbook.New(),
+		ready: make(chan struct{}),
+	}
+}
+
+// apply applies a snapshot or update after checking its sequence, then
+// verifies the resulting checksum. Updates received before the first snapshot
+// are ignored. Any failure leaves the book unsynced until the next snapshot.
+func (b *streamBook) apply(snapshot bool, data bookData) error {
+	bids, err := orderbook.ParseLevels(data.Bids)
+	if err != nil {
+		return err
+	}
+	asks, err := orderbook.ParseLevels(data.Asks)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if snapshot {
+		b.book.Clear()
+	} else if !b.synced {
+		return nil
+	} else if data.PrevSeqID != b.seqID {
+		b.synced = false
+		return fmt.Errorf("%w: expected prevSeqId=%d, got %d", errSequenceGap, b.seqID, data.PrevSeqID)
+	}
+
+	b.book.UpdateBids(bids)
+	b.book.UpdateAsks(asks)
+	b.seqID = data.SeqID
+	b.updatedAt = time.Now()
+	b.eventTime = parseMillis(data.Ts)
+
+	if sum := checksum(b.book); sum != data.Checksum {
+		b.synced = false
+		return fmt.Errorf("%w: expected %d, computed %d", errChecksumMismatch, data.Checksum, sum)
+	}
+
+	b.liveAt = b.updatedAt
+	if !b.synced {
+		b.synced = true
+		b.readyOnce.Do(func() { close(b.ready) })
+	}
+	return nil
+}
+
+// confirm records that the stream is alive. While synced, the book is then
+// known to be current even if no update changed it.
+func (b *streamBook) confirm() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.synced {
+		b.liveAt = time.Now()
+	}
+}
+
+func (b *streamBook) invalidate() {
+	b.mu.Lock()
+	b.synced = false
+	b.mu.Unlock()
+}
+
+func (b *streamBook) isSynced() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.synced
+}
+
+// orderBook returns a sorted copy of the book, or false if it is not synced.
+// ExchangeTime is the time of the last applied message, in OKX's clock;
+// LiveAt is when the stream was last seen alive, at least every ping.
+func (b *streamBook) orderBook() (*domain.OrderBook, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.synced {
+		return nil, false
+	}
+	ob := b.book.OrderBook(b.updatedAt)
+	ob.ExchangeTime = b.eventTime
+	ob.LiveAt = b.liveAt
+	return ob, true
+}
+
+// checksum computes the OKX book checksum: the signed CRC32 of the top 25
+// bids and asks interleaved as bid:ask pairs, each level written as
+// price:quantity using the exchange's original string representation.
+func checksum(book *orderbook.Book) int32 {
+	bids, asks := book.Bids(), book.Asks()
+
+	fields := make([]string, 0, 4*streamChecksumDepth)
+	for i := 0; i < streamChecksumDepth; i++ {
+		if i < len(bids) {
+			fields = append(fields, checksumField(bids[i].Price), checksumField(bids[i].Amount))
+		}
+		if i < len(asks) {
+			fields = append(fields, checksumField(asks[i].Price), checksumField(asks[i].Amount))
+		}
+	}
+	return int32(crc32.ChecksumIEEE([]byte(strings.Join(fields, ":"))))
+}
+
+// checksumField reproduces the string a decimal was parsed from, keeping any
+// trailing zeros that decimal.String would drop.
+func checksumField(d decimal.Decimal) string {
+	if d.Exponent() >= 0 {
+		return d.String()
+	}
+	return d.StringFixed(-d.Exponent())
+}
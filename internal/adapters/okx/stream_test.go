@@ -0,0 +1,154 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/orderbook"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksum(t *testing.T) {
+	// Example from the OKX order book checksum documentation.
+	bids, err := orderbook.ParseLevels([][]string{{"3366.1", "7", "0", "3"}, {"3366", "6", "3", "4"}})
+	require.NoError(t, err)
+	asks, err := orderbook.ParseLevels([][]string{{"3366.8", "9", "10", "3"}, {"3368", "8", "3", "4"}})
+	require.NoError(t, err)
+
+	book := orderbook.New()
+	book.UpdateBids(bids)
+	book.UpdateAsks(asks)
+
+	expected := int32(crc32.ChecksumIEEE([]byte("3366.1:7:3366.8:9:3366:6:3368:8")))
+	assert.Equal(t, expected, checksum(book))
+}
+
+func TestChecksumField_KeepsTrailingZeros(t *testing.T) {
+	assert.Equal(t, "0.10", checksumField(decimal.RequireFromString("0.10")))
+	assert.Equal(t, "2000", checksumField(decimal.RequireFromString("2000")))
+}
+
+// booksMessage builds a books channel message whose checksum matches the
+// state of want after the levels have been applied.
+func booksMessage(t *testing.T, action string, want *orderbook.Book, prevSeq, seq int64, bids, asks [][]string) []byte {
+	t.Helper()
+
+	b, err := orderbook.ParseLevels(bids)
+	require.NoError(t, err)
+	a, err := orderbook.ParseLevels(asks)
+	require.NoError(t, err)
+	want.UpdateBids(b)
+	want.UpdateAsks(a)
+
+	msg, err := json.Marshal(map[string]any{
+		"arg":    wsArg{Channel: "books", InstID: "ETH-USDC"},
+		"action": action,
+		"data": []map[string]any{{
+			"bids":      bids,
+			"asks":      asks,
+			"ts":        "1597026383085",
+			"checksum":  checksum(want),
+			"prevSeqId": prevSeq,
+			"seqId":     seq,
+		}},
+	})
+	require.NoError(t, err)
+	return msg
+}
+
+func TestStreamAdapter_ResubscribesOnSequenceGap(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+
+	upgrader := websocket.Upgrader{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v5/public/instruments" {
+			_, _ = w.Write([]byte(instrumentsResponseJSON))
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		subs := 0
+		for {
+			var req wsRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+
+			mu.Lock()
+			requests = append(requests, fmt.Sprintf("%s:%s", req.Op, req.Args[0].InstID))
+			mu.Unlock()
+
+			if req.Op != "subscribe" {
+				continue
+			}
+			subs++
+			_ = conn.WriteJSON(map[string]any{"event": "subscribe", "arg": req.Args[0]})
+
+			want := orderbook.New()
+			if subs == 1 {
+				_ = conn.WriteMessage(websocket.TextMessage, booksMessage(t, "snapshot", want, -1, 10,
+					[][]string{{"2000.00", "1.5", "0", "1"}}, [][]string{{"2001.00", "2.0", "0", "1"}}))
+				_ = conn.WriteMessage(websocket.TextMessage, booksMessage(t, "update", want, 10, 11,
+					[][]string{{"1999.50", "3.0", "0", "1"}}, nil))
+				// prevSeqId should be 11.
+				_ = conn.WriteMessage(websocket.TextMessage, booksMessage(t, "update", want, 15, 16,
+					[][]string{{"1999.00", "1.0", "0", "1"}}, nil))
+			} else {
+				_ = conn.WriteMessage(websocket.TextMessage, booksMessage(t, "snapshot", want, -1, 20,
+					[][]string{{"2100.00", "1.0", "0", "1"}}, [][]string{{"2101.00", "1.0", "0", "1"}}))
+			}
+		}
+	}))
+	defer ts.Close()
+
+	adapter := NewStreamAdapter("ws"+strings.TrimPrefix(ts.URL, "http"), ts.URL)
+	defer func() {
+		_ = adapter.Close()
+	}()
+
+	require.Eventually(t, func() bool {
+		ob, ok := adapter.book("ETHUSDC").orderBook()
+		return ok && len(ob.Bids) == 1 && ob.Bids[0].Price.Equal(decimal.RequireFromString("2100"))
+	}, 2*time.Second, 20*time.Millisecond)
+
+	ob, err := adapter.GetOrderBook(context.Background(), "ETHUSDC")
+	require.NoError(t, err)
+	require.Len(t, ob.Asks, 1)
+	assert.True(t, ob.Asks[0].Price.Equal(decimal.RequireFromString("2101")))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"subscribe:ETH-USDC", "unsubscribe:ETH-USDC", "subscribe:ETH-USDC"}, requests)
+}
+
+func TestStreamAdapter_ForwardsDepthToREST(t *testing.T) {
+	adapter := NewStreamAdapter("ws://unused", "http://unused")
+	defer func() {
+		_ = adapter.Close()
+	}()
+
+	adapter.SetBookDepth(50)
+	assert.Equal(t, 50, adapter.rest.depth.Levels())
+	require.True(t, adapter.DeepenBook())
+	assert.Equal(t, 100, adapter.rest.depth.Levels())
+}
@@ -0,0 +1,108 @@
+package orderbook
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/observability"
+)
+
+// relaxAfter is the number of consecutive fully filled books after which a
+// deepened limit steps back toward the configured depth.
+const relaxAfter = 100
+
+// DepthLimit chooses how many levels per side an adapter requests. It starts
+// at the configured depth, steps up a tier each time a book could not fill a
+// trade size, and steps back down once books have been deep enough for a
+// while. It is safe for concurrent use.
+type DepthLimit struct {
+	venue string
+	// tiers are the depths the venue accepts, ascending; the last is its
+	// maximum.
+	tiers []int
+
+	mu      sync.Mutex
+	base    int
+	current int
+	filled  int
+}
+
+func NewDepthLimit(venue string, levels int, tiers ...int) *DepthLimit {
+	d := &DepthLimit{venue: venue, tiers: tiers}
+	d.Configure(levels)
+	return d
+}
+
+// Configure sets the configured depth, rounded up to a tier the venue
+// accepts, and resets any adjustment.
+func (d *DepthLimit) Configure(levels int) {
+	tier := d.tier(levels)
+	if d.tiers[tier] != levels {
+		slog.Warn("order book depth not supported by venue, rounding", "venue", d.venue, "configured", levels, "using", d.tiers[tier])
+	}
+
+	d.mu.Lock()
+	d.base, d.current, d.filled = tier, tier, 0
+	d.mu.Unlock()
+	observability.CEXBookDepth.WithLabelValues(d.venue).Set(float64(d.tiers[tier]))
+}
+
+// Levels returns the depth to request on the next fetch.
+func (d *DepthLimit) Levels() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.tiers[d.current]
+}
+
+// Deepen moves to the next tier after a book ran out before filling a trade
+// size. It reports false if the venue maximum is already in use.
+func (d *DepthLimit) Deepen() bool {
+	d.mu.Lock()
+	d.filled = 0
+	if d.current == len(d.tiers)-1 {
+		d.mu.Unlock()
+		return false
+	}
+	d.current++
+	levels := d.tiers[d.current]
+	d.mu.Unlock()
+
+	slog.Info("deepening order book", "venue", d.venue, "levels", levels)
+	observability.CEXBookDepth.WithLabelValues(d.venue).Set(float64(levels))
+	observability.CEXBookDepthChanges.WithLabelValues(d.venue, "deepen").Inc()
+	return true
+}
+
+// Filled records a book that filled every trade size. After relaxAfter in a
+// row, a deepened limit steps back one tier.
+func (d *DepthLimit) Filled() {
+	d.mu.Lock()
+	if d.current == d.base {
+		d.mu.Unlock()
+		return
+	}
+	d.filled++
+	if d.filled < relaxAfter {
+		d.mu.Unlock()
+		return
+	}
+	d.filled = 0
+	d.current--
+	levels := d.tiers[d.current]
+	d.mu.Unlock()
+
+	slog.Info("relaxing order book depth", "venue", d.venue, "levels", levels)
+	observability.CEXBookDepth.WithLabelValues(d.venue).Set(float64(levels))
+	observability.CEXBookDepthChanges.WithLabelValues(d.venue, "relax").Inc()
+}
+
+// tier returns the index of the smallest tier holding at least levels,
+// capped at the venue maximum.
+func (d *DepthLimit) tier(levels int) int {
+	i := sort.SearchInts(d.tiers, levels)
+	if i == len(d.tiers) {
+		return len(d.tiers) - 1
+	}
+	return i
+}
@@ -0,0 +1,39 @@
+package orderbook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDepthLimit_DeepensAndRelaxes(t *testing.T) {
+	d := NewDepthLimit("test", 100, 50, 100, 200)
+	assert.Equal(t, 100, d.Levels())
+
+	assert.True(t, d.Deepen())
+	assert.Equal(t, 200, d.Levels())
+	assert.False(t, d.Deepen(), "the venue maximum cannot be exceeded")
+
+	for i := 0; i < relaxAfter-1; i++ {
+		d.Filled()
+	}
+	assert.Equal(t, 200, d.Levels())
+	d.Filled()
+	assert.Equal(t, 100, d.Levels())
+
+	for i := 0; i < 2*relaxAfter; i++ {
+		d.Filled()
+	}
+	assert.Equal(t, 100, d.Levels(), "depth never relaxes below the configured value")
+}
+
+func TestDepthLimit_ConfigureRoundsToTier(t *testing.T) {
+	d := NewDepthLimit("test", 100, 50, 100, 200)
+
+	d.Configure(120)
+	assert.Equal(t, 200, d.Levels())
+	d.Configure(1000)
+	assert.Equal(t, 200, d.Levels())
+	d.Configure(1)
+	assert.Equal(t, 50, d.Levels())
+}
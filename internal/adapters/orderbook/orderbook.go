@@ -0,0 +1,152 @@
+package orderbook
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Book is an in-memory price-level book maintained from exchange snapshots and
+// incremental updates. Levels are keyed by price; updating a level with a zero
+// amount removes it. Book is not safe for concurrent use.
+type Book struct {
+	bids map[string]domain.PriceLevel
+	asks map[string]domain.PriceLevel
+}
+
+func New() *Book {
+	return &Book{
+		bids: make(map[string]domain.PriceLevel),
+		asks: make(map[string]domain.PriceLevel),
+	}
+}
+
+// Clear removes every level from both sides.
+func (b *Book) Clear() {
+	b.bids = make(map[string]domain.PriceLevel)
+	b.asks = make(map[string]domain.PriceLevel)
+}
+
+func (b *Book) UpdateBids(levels []domain.PriceLevel) {
+	update(b.bids, levels)
+}
+
+func (b *Book) UpdateAsks(levels []domain.PriceLevel) {
+	update(b.asks, levels)
+}
+
+// Bids returns the bid levels sorted best (highest) first.
+func (b *Book) Bids() []domain.PriceLevel {
+	levels := values(b.bids)
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Price.GreaterThan(levels[j].Price) })
+	return levels
+}
+
+// Asks returns the ask levels sorted best (lowest) first.
+func (b *Book) Asks() []domain.PriceLevel {
+	levels := values(b.asks)
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Price.LessThan(levels[j].Price) })
+	return levels
+}
+
+// Truncate drops levels beyond depth on each side, keeping the best ones.
+func (b *Book) Truncate(depth int) {
+	if len(b.bids) > depth {
+		for _, l := range b.Bids()[depth:] {
+			delete(b.bids, l.Price.String())
+		}
+	}
+	if len(b.asks) > depth {
+		for _, l := range b.Asks()[depth:] {
+			delete(b.asks, l.Price.String())
+		}
+	}
+}
+
+// OrderBook returns a sorted copy of the book stamped with ts.
+func (b *Book) OrderBook(ts time.Time) *domain.OrderBook {
+	return &domain.OrderBook{
+		Bids:      b.Bids(),
+		Asks:      b.Asks(),
+		Timestamp: ts,
+	}
+}
+
+// ParseLevels parses [price, amount, ...] string tuples as returned by most
+// exchange APIs. Extra tuple fields are ignored. Errors wrap
+// domain.ErrMalformedBook.
+func ParseLevels(raw [][]string) ([]domain.PriceLevel, error) {
+	levels := make([]domain.PriceLevel, 0, len(raw))
+	for _, l := range raw {
+		if len(l) < 2 {
+			return nil, fmt.Errorf("%w: level %v", domain.ErrMalformedBook, l)
+		}
+		level, err := ParseLevel(l[0], l[1])
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// ParseMixedLevels parses tuples whose price and amount are strings but whose
+// remaining fields are numbers, such as Coinbase's [price, size, num_orders]
+// and Kraken's [price, volume, timestamp].
+func ParseMixedLevels(raw [][]any) ([]domain.PriceLevel, error) {
+	levels := make([]domain.PriceLevel, 0, len(raw))
+	for _, l := range raw {
+		if len(l) < 2 {
+			return nil, fmt.Errorf("%w: level %v", domain.ErrMalformedBook, l)
+		}
+		price, ok := l[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: price %v is not a string", domain.ErrMalformedBook, l[0])
+		}
+		amount, ok := l[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: amount %v is not a string", domain.ErrMalformedBook, l[1])
+		}
+		level, err := ParseLevel(price, amount)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// ParseLevel parses a decimal price and amount.
+func ParseLevel(price, amount string) (domain.PriceLevel, error) {
+	p, err := decimal.NewFromString(price)
+	if err != nil {
+		return domain.PriceLevel{}, fmt.Errorf("%w: invalid price %q: %v", domain.ErrMalformedBook, price, err)
+	}
+	a, err := decimal.NewFromString(amount)
+	if err != nil {
+		return domain.PriceLevel{}, fmt.Errorf("%w: invalid amount %q: %v", domain.ErrMalformedBook, amount, err)
+	}
+	return domain.PriceLevel{Price: p, Amount: a}, nil
+}
+
+func update(side map[string]domain.PriceLevel, levels []domain.PriceLevel) {
+	for _, l := range levels {
+		key := l.Price.String()
+		if l.Amount.IsZero() {
+			delete(side, key)
+			continue
+		}
+		side[key] = l
+	}
+}
+
+func values(side map[string]domain.PriceLevel) []domain.PriceLevel {
+	levels := make([]domain.PriceLevel, 0, len(side))
+	for _, l := range side {
+		levels = append(levels, l)
+	}
+	return levels
+}
@@ -0,0 +1,72 @@
+package websocket
+
+import "github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+
+// replayBufferSize is the number of recent events kept for clients resuming
+// after a reconnect: about an hour of blocks with an opportunity on each.
+const replayBufferSize = 1024
+
+// Reasons a resuming client could not be sent every event it missed.
+const (
+	gapExpired = "expired" // the events have rolled out of the buffer
+	gapReset   = "reset"   // the sequence restarted, e.g. after a server restart
+)
+
+// Gap describes events a resuming client missed and cannot be replayed:
+// From..To inclusive when the range is known.
+type Gap struct {
+	From   uint64
+	To     uint64
+	Reason string
+}
+
+// eventLog numbers events and keeps the most recent ones in a ring buffer.
+// It is not safe for concurrent use.
+type eventLog struct {
+	events []domain.ArbitrageEvent
+	start  int // index of the oldest event
+	n      int
+	last   uint64 // sequence of the newest event; sequences start at 1
+}
+
+func newEventLog(size int) *eventLog {
+	return &eventLog{events: make([]domain.ArbitrageEvent, size)}
+}
+
+// append assigns e the next sequence number and stores it, evicting the
+// oldest event once the buffer is full.
+func (l *eventLog) append(e domain.ArbitrageEvent) domain.ArbitrageEvent {
+	l.last++
+	e.Seq = l.last
+	if l.n < len(l.events) {
+		l.events[(l.start+l.n)%len(l.events)] = e
+		l.n++
+	} else {
+		l.events[l.start] = e
+		l.start = (l.start + 1) % len(l.events)
+	}
+	return e
+}
+
+// since returns the buffered events after seq, oldest first, and the gap
+// before them if some of the missed events are no longer buffered.
+func (l *eventLog) since(seq uint64) ([]domain.ArbitrageEvent, *Gap) {
+	oldest := l.last - uint64(l.n) + 1
+
+	var missed *Gap
+	skip := 0
+	switch {
+	case seq > l.last:
+		missed = &Gap{Reason: gapReset}
+	case seq+1 < oldest:
+		missed = &Gap{From: seq + 1, To: oldest - 1, Reason: gapExpired}
+	default:
+		skip = int(seq + 1 - oldest)
+	}
+
+	events := make([]domain.ArbitrageEvent, 0, l.n-skip)
+	for i := skip; i < l.n; i++ {
+		events = append(events, l.events[(l.start+i)%len(l.events)])
+	}
+	return events, missed
+}
@@ -0,0 +1,45 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func seqs(events []domain.ArbitrageEvent) []uint64 {
+	out := make([]uint64, 0, len(events))
+	for _, e := range events {
+		out = append(out, e.Seq)
+	}
+	return out
+}
+
+func TestEventLog_Since(t *testing.T) {
+	l := newEventLog(3)
+
+	events, missed := l.since(0)
+	assert.Empty(t, events)
+	assert.Nil(t, missed)
+
+	for i := 0; i < 5; i++ {
+		e := l.append(domain.ArbitrageEvent{Type: domain.EventHeartbeat})
+		assert.Equal(t, uint64(i+1), e.Seq)
+	}
+
+	events, missed = l.since(3)
+	assert.Equal(t, []uint64{4, 5}, seqs(events))
+	assert.Nil(t, missed)
+
+	events, missed = l.since(5)
+	assert.Empty(t, events)
+	assert.Nil(t, missed)
+
+	events, missed = l.since(1)
+	assert.Equal(t, []uint64{3, 4, 5}, seqs(events))
+	assert.Equal(t, &Gap{From: 2, To: 2, Reason: gapExpired}, missed)
+
+	events, missed = l.since(9)
+	assert.Equal(t, []uint64{3, 4, 5}, seqs(events), "after a restart everything buffered is replayed")
+	assert.Equal(t, gapReset, missed.Reason)
+}
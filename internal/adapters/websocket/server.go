@@ -1,107 +1,368 @@
 package websocket
 
 import (
+	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"sync"
+	"time"
 
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/auth"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/observability"
 	"github.com/gorilla/websocket"
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
+const (
+	// clientQueueSize is the number of messages buffered per client. A
+	// client that falls this far behind is evicted.
+	clientQueueSize = 256
+	// maxMessageSize bounds messages read from clients.
+	maxMessageSize = 4096
+
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = pongWait * 9 / 10
+)
+
+// Control message types exchanged with clients.
+const (
+	actionSubscribe = "subscribe"
+	typeSubscribed  = "SUBSCRIBED"
+	typeGap         = "GAP"
+	typeError       = "ERROR"
+)
+
+// clientMessage is a request sent by a client. A subscribe replaces the
+// client's filter; until the first one every event is delivered.
+type clientMessage struct {
+	Action string `json:"action"`
+	domain.EventFilter
 }
 
-type Server struct {
-	clients   map[*websocket.Conn]bool
-	broadcast chan domain.ArbitrageEvent
-	mu        sync.RWMutex
+// controlMessage acknowledges or rejects a client request, or reports events
+// a resuming client missed that could not be replayed.
+type controlMessage struct {
+	Type   string              `json:"type"`
+	Filter *domain.EventFilter `json:"filter,omitempty"`
+	Error  string              `json:"error,omitempty"`
+	From   uint64              `json:"from,omitempty"`
+	To     uint64              `json:"to,omitempty"`
+	Reason string              `json:"reason,omitempty"`
 }
 
-func NewServer() *Server {
-	return &Server{
-		clients:   make(map[*websocket.Conn]bool),
-		broadcast: make(chan domain.ArbitrageEvent),
+// client is a connection with its own send queue, drained by writePump so
+// that a slow connection never holds up the others.
+type client struct {
+	conn *websocket.Conn
+	send chan any
+
+	// done is closed when the client is evicted or disconnects.
+	done      chan struct{}
+	closeOnce sync.Once
+	closeCode int
+
+	mu     sync.RWMutex
+	filter domain.EventFilter
+}
+
+func newClient(conn *websocket.Conn, queueSize int) *client {
+	return &client{
+		conn: conn,
+		send: make(chan any, queueSize),
+		done: make(chan struct{}),
 	}
 }
 
-func (s *Server) Start(addr string) {
-	http.HandleFunc("/ws", s.handleConnections)
+// enqueue queues msg without blocking. It reports false if the queue is full
+// or the client is gone.
+func (c *client) enqueue(msg any) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+	select {
+	case c.send <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops writePump, which sends a close frame with code if non-zero.
+func (c *client) close(code int) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		close(c.done)
+	})
+}
+
+func (c *client) matches(event domain.ArbitrageEvent) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.filter.Matches(event)
+}
 
-	go s.handleMessages()
+func (c *client) subscribe(filter domain.EventFilter) {
+	c.mu.Lock()
+	c.filter = filter
+	c.mu.Unlock()
+}
 
-	slog.Info("WebSocket server starting", "addr", addr)
+type Server struct {
+	// mu guards clients and log. Sequencing an event and queuing it happen
+	// under one lock, so a resuming client sees every event exactly once.
+	mu      sync.Mutex
+	clients map[*client]bool
+	log     *eventLog
 
-	// Wrap the default mux with CORS middleware
-	handler := corsMiddleware(http.DefaultServeMux)
+	auth     *auth.Authenticator
+	upgrader websocket.Upgrader
 
-	if err := http.ListenAndServe(addr, handler); err != nil {
-		slog.Error("WebSocket server failed", "error", err)
+	queueSize  int
+	writeWait  time.Duration
+	pongWait   time.Duration
+	pingPeriod time.Duration
+}
+
+func NewServer(authn *auth.Authenticator) *Server {
+	return &Server{
+		clients:    make(map[*client]bool),
+		log:        newEventLog(replayBufferSize),
+		auth:       authn,
+		upgrader:   websocket.Upgrader{CheckOrigin: authn.CheckOrigin},
+		queueSize:  clientQueueSize,
+		writeWait:  writeWait,
+		pongWait:   pongWait,
+		pingPeriod: pingPeriod,
 	}
 }
 
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS, PUT, DELETE")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+// Handler returns the server's routes: the event stream over WebSocket at
+// /ws and as Server-Sent Events at /events. Every endpoint requires the read
+// scope and an allowed origin.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/ws", s.auth.Require(auth.ScopeRead, http.HandlerFunc(s.handleConnections)))
+	mux.Handle("/events", s.auth.Require(auth.ScopeRead, http.HandlerFunc(s.handleEvents)))
+	return s.auth.CORS(mux)
+}
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
+func (s *Server) Start(addr string) {
+	slog.Info("WebSocket server starting", "addr", addr, "auth", s.auth.Enabled())
 
-		next.ServeHTTP(w, r)
-	})
+	if err := http.ListenAndServe(addr, s.Handler()); err != nil {
+		slog.Error("WebSocket server failed", "error", err)
+	}
 }
 
+// handleConnections serves /ws. A client reconnecting with
+// ?resume_from=<seq> is first sent the buffered events after seq.
 func (s *Server) handleConnections(w http.ResponseWriter, r *http.Request) {
-	ws, err := upgrader.Upgrade(w, r, nil)
+	resumeFrom, err := parseResume(r.URL.Query().Get("resume_from"))
+	if err != nil {
+		http.Error(w, "invalid resume_from", http.StatusBadRequest)
+		return
+	}
+
+	ws, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		slog.Error("WS upgrade failed", "error", err)
 		return
 	}
-	defer func() {
-		_ = ws.Close()
-	}()
 
+	c := newClient(ws, s.queueSize)
+	go s.writePump(c, replayMessages(s.register(c, resumeFrom)))
+
+	slog.Info("New WebSocket client connected", "resume_from", resumeFrom)
+	s.readPump(c)
+}
+
+// parseResume parses the sequence a client last saw; empty means it is not
+// resuming.
+func parseResume(v string) (*uint64, error) {
+	if v == "" {
+		return nil, nil
+	}
+	seq, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &seq, nil
+}
+
+// register adds c to the broadcast set and returns what to send ahead of its
+// queue when resumeFrom is set: the matching events after it, and the gap
+// before them if some could not be replayed.
+func (s *Server) register(c *client, resumeFrom *uint64) ([]domain.ArbitrageEvent, *Gap) {
 	s.mu.Lock()
-	s.clients[ws] = true
+	defer s.mu.Unlock()
+
+	var replay []domain.ArbitrageEvent
+	var missed *Gap
+	if resumeFrom != nil {
+		var events []domain.ArbitrageEvent
+		events, missed = s.log.since(*resumeFrom)
+		for _, e := range events {
+			if c.matches(e) {
+				replay = append(replay, e)
+			}
+		}
+	}
+	s.clients[c] = true
+	observability.WSClients.Set(float64(len(s.clients)))
+	return replay, missed
+}
+
+// replayMessages returns the messages sent to a resuming connection before
+// live events.
+func replayMessages(events []domain.ArbitrageEvent, missed *Gap) []any {
+	var msgs []any
+	if missed != nil {
+		msgs = append(msgs, controlMessage{Type: typeGap, From: missed.From, To: missed.To, Reason: missed.Reason})
+	}
+	for _, e := range events {
+		msgs = append(msgs, e)
+	}
+	return msgs
+}
+
+func (s *Server) unregister(c *client) {
+	s.mu.Lock()
+	delete(s.clients, c)
+	observability.WSClients.Set(float64(len(s.clients)))
 	s.mu.Unlock()
+}
 
-	slog.Info("New WebSocket client connected")
+// readPump handles client requests until the connection fails or no pong
+// arrives within pongWait.
+func (s *Server) readPump(c *client) {
+	defer func() {
+		s.unregister(c)
+		c.close(0)
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(s.pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(s.pongWait))
+	})
 
 	for {
-		_, _, err := ws.ReadMessage()
+		_, data, err := c.conn.ReadMessage()
 		if err != nil {
-			s.mu.Lock()
-			delete(s.clients, ws)
-			s.mu.Unlock()
-			break
+			return
+		}
+
+		var msg clientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			s.reply(c, controlMessage{Type: typeError, Error: "invalid message: " + err.Error()})
+			continue
+		}
+		if err := s.handleRequest(c, msg); err != nil {
+			s.reply(c, controlMessage{Type: typeError, Error: err.Error()})
 		}
 	}
 }
 
-func (s *Server) handleMessages() {
-	for {
-		msg := <-s.broadcast
+// writePump is the only writer to the connection. It sends replay, then
+// queued messages and periodic pings, until the client is closed or a write
+// misses its deadline.
+func (s *Server) writePump(c *client, replay []any) {
+	ticker := time.NewTicker(s.pingPeriod)
+	defer func() {
+		ticker.Stop()
+		_ = c.conn.Close()
+	}()
 
-		s.mu.RLock()
-		for client := range s.clients {
-			err := client.WriteJSON(msg)
-			if err != nil {
-				slog.Error("WS write failed", "error", err)
-				_ = client.Close()
+	write := func(msg any) bool {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(s.writeWait))
+		if err := c.conn.WriteJSON(msg); err != nil {
+			slog.Debug("WS write failed", "error", err)
+			return false
+		}
+		return true
+	}
 
+	for _, msg := range replay {
+		if !write(msg) {
+			return
+		}
+	}
+	for {
+		select {
+		case msg := <-c.send:
+			if !write(msg) {
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(s.writeWait)); err != nil {
+				return
 			}
+		case <-c.done:
+			if c.closeCode != 0 {
+				msg := websocket.FormatCloseMessage(c.closeCode, "client too slow")
+				_ = c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(s.writeWait))
+			}
+			return
+		}
+	}
+}
+
+// handleRequest applies a client request and acknowledges it.
+func (s *Server) handleRequest(c *client, msg clientMessage) error {
+	switch msg.Action {
+	case actionSubscribe:
+		filter := msg.EventFilter
+		if err := filter.Normalize(); err != nil {
+			return err
 		}
-		s.mu.RUnlock()
+		c.subscribe(filter)
+		slog.Debug("WS client subscribed", "filter", filter)
+		s.reply(c, controlMessage{Type: typeSubscribed, Filter: &filter})
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q", msg.Action)
 	}
 }
 
+func (s *Server) reply(c *client, msg controlMessage) {
+	if !c.enqueue(msg) {
+		s.evict(c)
+	}
+}
+
+// evict disconnects a client whose queue is full. It does not wait for a
+// WebSocket close frame to be written.
+func (s *Server) evict(c *client) {
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+	slog.Warn("Evicting slow client", "queued", len(c.send))
+	observability.WSEvictions.Inc()
+	c.close(websocket.CloseTryAgainLater)
+}
+
+// Broadcast sequences event and queues it for every matching client. It
+// never blocks on a connection; clients that cannot keep up are evicted.
 func (s *Server) Broadcast(event domain.ArbitrageEvent) {
-	s.broadcast <- event
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event = s.log.append(event)
+	for c := range s.clients {
+		if !c.matches(event) {
+			continue
+		}
+		if !c.enqueue(event) {
+			delete(s.clients, c)
+			observability.WSClients.Set(float64(len(s.clients)))
+			s.evict(c)
+		}
+	}
 }
@@ -1,28 +1,32 @@
 package websocket
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/auth"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+func opportunity(symbol string, profit float64) domain.ArbitrageEvent {
+	return domain.ArbitrageEvent{Type: domain.EventOpportunity, Data: &domain.TradeData{Symbol: symbol, EstimatedProfit: profit}}
+}
+
 func TestServer_Broadcast(t *testing.T) {
 	// Setup Server
-	server := NewServer()
+	server := NewServer(auth.New(auth.Config{}))
 
 	// Create test server
 	s := httptest.NewServer(http.HandlerFunc(server.handleConnections))
 	defer s.Close()
 
-	// Start message handler
-	go server.handleMessages()
-
 	// Convert http URL to ws URL
 	u := "ws" + strings.TrimPrefix(s.URL, "http")
 
@@ -52,3 +56,240 @@ func TestServer_Broadcast(t *testing.T) {
 	assert.Equal(t, event.Type, received.Type)
 	assert.Equal(t, event.BlockNumber, received.BlockNumber)
 }
+
+func TestServer_SubscriptionFilters(t *testing.T) {
+	server := NewServer(auth.New(auth.Config{}))
+	s := httptest.NewServer(http.HandlerFunc(server.handleConnections))
+	defer s.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(s.URL, "http"), nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = ws.Close()
+	}()
+
+	var ack controlMessage
+	require.NoError(t, ws.WriteJSON(map[string]any{"action": "subscribe", "types": []string{"opportunity"}, "symbols": []string{"eth-usdc"}, "minProfit": 50}))
+	require.NoError(t, ws.ReadJSON(&ack))
+	assert.Equal(t, typeSubscribed, ack.Type)
+	assert.Equal(t, []string{"ETHUSDC"}, ack.Filter.Symbols)
+
+	server.Broadcast(domain.ArbitrageEvent{Type: domain.EventHeartbeat, BlockNumber: 1})
+	server.Broadcast(opportunity("ETHUSDC", 10))
+	server.Broadcast(opportunity("BTCUSDC", 100))
+	server.Broadcast(opportunity("ETHUSDC", 100))
+
+	var received domain.ArbitrageEvent
+	require.NoError(t, ws.ReadJSON(&received))
+	assert.Equal(t, domain.EventOpportunity, received.Type)
+	assert.Equal(t, "ETHUSDC", received.Data.Symbol)
+	assert.Equal(t, 100.0, received.Data.EstimatedProfit)
+
+	// Switch to heartbeats on the same connection.
+	require.NoError(t, ws.WriteJSON(map[string]any{"action": "subscribe", "types": []string{"HEARTBEAT"}}))
+	require.NoError(t, ws.ReadJSON(&ack))
+	assert.Equal(t, typeSubscribed, ack.Type)
+
+	server.Broadcast(opportunity("ETHUSDC", 100))
+	server.Broadcast(domain.ArbitrageEvent{Type: domain.EventHeartbeat, BlockNumber: 2})
+	require.NoError(t, ws.ReadJSON(&received))
+	assert.Equal(t, domain.EventHeartbeat, received.Type)
+	assert.Equal(t, uint64(2), received.BlockNumber)
+
+	require.NoError(t, ws.WriteJSON(map[string]any{"action": "subscribe", "types": []string{"TRADES"}}))
+	require.NoError(t, ws.ReadJSON(&ack))
+	assert.Equal(t, typeError, ack.Type)
+	assert.Contains(t, ack.Error, "unknown event type")
+}
+
+func TestServer_ResumeReplaysMissedEvents(t *testing.T) {
+	server := NewServer(auth.New(auth.Config{}))
+	server.log = newEventLog(3)
+	s := httptest.NewServer(http.HandlerFunc(server.handleConnections))
+	defer s.Close()
+
+	for i := uint64(1); i <= 5; i++ {
+		server.Broadcast(domain.ArbitrageEvent{Type: domain.EventHeartbeat, BlockNumber: i})
+	}
+
+	dial := func(resumeFrom string) *websocket.Conn {
+		ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(s.URL, "http")+"?resume_from="+resumeFrom, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ws.Close() })
+		return ws
+	}
+
+	ws := dial("3")
+	var e domain.ArbitrageEvent
+	for _, want := range []uint64{4, 5} {
+		require.NoError(t, ws.ReadJSON(&e))
+		assert.Equal(t, want, e.Seq)
+	}
+	server.Broadcast(domain.ArbitrageEvent{Type: domain.EventHeartbeat, BlockNumber: 6})
+	require.NoError(t, ws.ReadJSON(&e))
+	assert.Equal(t, uint64(6), e.Seq, "live events follow the replay")
+
+	// Events 1-3 have rolled out of the buffer.
+	ws = dial("0")
+	var notice controlMessage
+	require.NoError(t, ws.ReadJSON(&notice))
+	assert.Equal(t, controlMessage{Type: typeGap, From: 1, To: 3, Reason: gapExpired}, notice)
+	require.NoError(t, ws.ReadJSON(&e))
+	assert.Equal(t, uint64(4), e.Seq)
+
+	_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(s.URL, "http")+"?resume_from=abc", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestServer_BroadcastEvictsSlowClient(t *testing.T) {
+	server := NewServer(auth.New(auth.Config{}))
+	slow := newClient(nil, 1)
+	server.register(slow, nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 3; i++ {
+			server.Broadcast(domain.ArbitrageEvent{Type: domain.EventHeartbeat})
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Broadcast blocked on a client that is not reading")
+	}
+	select {
+	case <-slow.done:
+	default:
+		t.Fatal("Expected the slow client to be closed")
+	}
+	assert.Equal(t, websocket.CloseTryAgainLater, slow.closeCode)
+	assert.Empty(t, server.clients)
+}
+
+func TestServer_EvictedClientReceivesCloseCode(t *testing.T) {
+	server := NewServer(auth.New(auth.Config{}))
+	s := httptest.NewServer(http.HandlerFunc(server.handleConnections))
+	defer s.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(s.URL, "http"), nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = ws.Close()
+	}()
+	require.Eventually(t, func() bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return len(server.clients) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	server.mu.Lock()
+	for c := range server.clients {
+		server.evict(c)
+	}
+	server.mu.Unlock()
+
+	_, _, err = ws.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseTryAgainLater), "got %v", err)
+}
+
+func TestServer_PingsIdleClients(t *testing.T) {
+	server := NewServer(auth.New(auth.Config{}))
+	server.pingPeriod = 20 * time.Millisecond
+	s := httptest.NewServer(http.HandlerFunc(server.handleConnections))
+	defer s.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(s.URL, "http"), nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = ws.Close()
+	}()
+
+	pinged := make(chan struct{}, 1)
+	ws.SetPingHandler(func(string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	go func() {
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-pinged:
+	case <-time.After(time.Second):
+		t.Fatal("Expected a ping from the server")
+	}
+}
+
+func TestServer_HandlerRequiresTokenAndOrigin(t *testing.T) {
+	server := NewServer(auth.New(auth.Config{
+		AllowedOrigins: []string{"https://dash.example.com"},
+		ReadTokens:     []string{"reader-token-0001"},
+	}))
+	s := httptest.NewServer(server.Handler())
+	defer s.Close()
+	u := "ws" + strings.TrimPrefix(s.URL, "http") + "/ws"
+
+	_, resp, err := websocket.DefaultDialer.Dial(u, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	header := http.Header{"Authorization": {"Bearer reader-token-0001"}, "Origin": {"https://evil.example.com"}}
+	_, resp, err = websocket.DefaultDialer.Dial(u, header)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	header.Set("Origin", "https://dash.example.com")
+	ws, _, err := websocket.DefaultDialer.Dial(u, header)
+	require.NoError(t, err)
+	_ = ws.Close()
+
+	// Browsers cannot set headers on the handshake, so the token may be
+	// passed in the query instead.
+	ws, _, err = websocket.DefaultDialer.Dial(u+"?access_token=reader-token-0001", http.Header{"Origin": {"https://dash.example.com"}})
+	require.NoError(t, err)
+	_ = ws.Close()
+}
+
+func TestSubscription_DeliversAndEvicts(t *testing.T) {
+	server := NewServer(auth.New(auth.Config{}))
+	server.queueSize = 1
+	server.Broadcast(opportunity("ETHUSDC", 100))
+
+	sub := server.Subscribe(domain.EventFilter{}, new(uint64))
+	assert.Nil(t, sub.Gap)
+	ctx := context.Background()
+	e, err := sub.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), e.Seq, "replayed")
+
+	server.Broadcast(domain.ArbitrageEvent{Type: domain.EventHeartbeat})
+	e, err = sub.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), e.Seq)
+
+	// The second event overflows the queue of one.
+	server.Broadcast(domain.ArbitrageEvent{Type: domain.EventHeartbeat})
+	server.Broadcast(domain.ArbitrageEvent{Type: domain.EventHeartbeat})
+	assert.Equal(t, 0, server.Subscribers())
+	for {
+		if _, err = sub.Next(ctx); err != nil {
+			break
+		}
+	}
+	assert.ErrorIs(t, err, ErrSlowSubscriber)
+
+	sub = server.Subscribe(domain.EventFilter{}, nil)
+	sub.Close()
+	_, err = sub.Next(ctx)
+	assert.ErrorIs(t, err, ErrSubscriptionClosed)
+}
@@ -0,0 +1,155 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+)
+
+// sseComment is written as an SSE comment line, which clients ignore. It
+// keeps idle connections open through proxies.
+type sseComment string
+
+const sseHeartbeat sseComment = "heartbeat"
+
+// handleEvents serves /events, the broadcast as Server-Sent Events for
+// clients that cannot use WebSockets. The filter is given in the query, e.g.
+// ?types=OPPORTUNITY&symbols=ETHUSDC&minProfit=25, and a reconnecting client
+// is sent the events after its Last-Event-ID first.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := queryFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("resume_from")
+	}
+	resumeFrom, err := parseResume(lastID)
+	if err != nil {
+		http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Stop nginx from buffering the stream.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		slog.Error("SSE streaming unsupported", "error", err)
+		return
+	}
+
+	c := newClient(nil, s.queueSize)
+	c.subscribe(filter)
+	replay := replayMessages(s.register(c, resumeFrom))
+	defer func() {
+		s.unregister(c)
+		c.close(0)
+	}()
+	slog.Info("New SSE client connected", "resume_from", resumeFrom)
+
+	write := func(msg any) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(s.writeWait))
+		if err := writeSSE(w, msg); err != nil {
+			slog.Debug("SSE write failed", "error", err)
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	for _, msg := range replay {
+		if !write(msg) {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(s.pingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg := <-c.send:
+			if !write(msg) {
+				return
+			}
+		case <-ticker.C:
+			if !write(sseHeartbeat) {
+				return
+			}
+		case <-c.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// writeSSE writes msg as one SSE message. Events carry their sequence as the
+// id, so that clients resume from it, and their type as the event name.
+func writeSSE(w io.Writer, msg any) error {
+	var id uint64
+	var name string
+	switch m := msg.(type) {
+	case sseComment:
+		_, err := fmt.Fprintf(w, ": %s\n\n", m)
+		return err
+	case domain.ArbitrageEvent:
+		id, name = m.Seq, m.Type
+	case controlMessage:
+		name = m.Type
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	if id != 0 {
+		fmt.Fprintf(&b, "id: %d\n", id)
+	}
+	fmt.Fprintf(&b, "event: %s\ndata: %s\n\n", name, data)
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// queryFilter reads an event filter from query parameters. Lists may be
+// comma separated or repeated.
+func queryFilter(q url.Values) (domain.EventFilter, error) {
+	list := func(key string) []string {
+		var out []string
+		for _, v := range q[key] {
+			for _, p := range strings.Split(v, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					out = append(out, p)
+				}
+			}
+		}
+		return out
+	}
+
+	f := domain.EventFilter{
+		Symbols:    list("symbols"),
+		Venues:     list("venues"),
+		Types:      list("types"),
+		Directions: list("directions"),
+	}
+	if v := q.Get("minProfit"); v != "" {
+		p, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return f, fmt.Errorf("%w: minProfit %q is not a number", domain.ErrInvalidFilter, v)
+		}
+		f.MinProfit = p
+	}
+	return f, f.Normalize()
+}
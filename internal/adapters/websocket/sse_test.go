@@ -0,0 +1,140 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/auth"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sseMessage struct {
+	id, event, data string
+	comment         string
+}
+
+// openSSE requests path and returns a function reading one message at a time.
+func openSSE(t *testing.T, baseURL, path string, header http.Header) (*http.Response, func() sseMessage) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+path, nil)
+	require.NoError(t, err)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	r := bufio.NewReader(resp.Body)
+	return resp, func() sseMessage {
+		t.Helper()
+		var m sseMessage
+		for {
+			line, err := r.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimSuffix(line, "\n")
+			if line == "" {
+				return m
+			}
+			field, value, _ := strings.Cut(line, ": ")
+			switch field {
+			case "":
+				m.comment = value
+			case "id":
+				m.id = value
+			case "event":
+				m.event = value
+			case "data":
+				m.data = value
+			}
+		}
+	}
+}
+
+func TestSSE_FiltersAndResumes(t *testing.T) {
+	server := NewServer(auth.New(auth.Config{}))
+	s := httptest.NewServer(server.Handler())
+	t.Cleanup(s.Close)
+
+	server.Broadcast(opportunity("ETHUSDC", 100))
+
+	resp, next := openSSE(t, s.URL, "/events?types=OPPORTUNITY&symbols=ethusdc&minProfit=50", http.Header{"Last-Event-ID": {"0"}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	m := next()
+	assert.Equal(t, "1", m.id, "replayed after Last-Event-ID")
+	assert.Equal(t, domain.EventOpportunity, m.event)
+
+	server.Broadcast(domain.ArbitrageEvent{Type: domain.EventHeartbeat, BlockNumber: 2})
+	server.Broadcast(opportunity("ETHUSDC", 10))
+	server.Broadcast(opportunity("ETHUSDC", 75))
+
+	m = next()
+	assert.Equal(t, "4", m.id)
+	var e domain.ArbitrageEvent
+	require.NoError(t, json.Unmarshal([]byte(m.data), &e))
+	assert.Equal(t, 75.0, e.Data.EstimatedProfit)
+	assert.Equal(t, uint64(4), e.Seq)
+}
+
+func TestSSE_ReportsGap(t *testing.T) {
+	server := NewServer(auth.New(auth.Config{}))
+	server.log = newEventLog(2)
+	s := httptest.NewServer(server.Handler())
+	t.Cleanup(s.Close)
+
+	for i := uint64(1); i <= 4; i++ {
+		server.Broadcast(domain.ArbitrageEvent{Type: domain.EventHeartbeat, BlockNumber: i})
+	}
+
+	_, next := openSSE(t, s.URL, "/events", http.Header{"Last-Event-ID": {"1"}})
+	m := next()
+	assert.Equal(t, typeGap, m.event)
+	assert.Empty(t, m.id)
+	var notice controlMessage
+	require.NoError(t, json.Unmarshal([]byte(m.data), &notice))
+	assert.Equal(t, controlMessage{Type: typeGap, From: 2, To: 2, Reason: gapExpired}, notice)
+	assert.Equal(t, "3", next().id)
+}
+
+func TestSSE_SendsHeartbeatComments(t *testing.T) {
+	server := NewServer(auth.New(auth.Config{}))
+	server.pingPeriod = 20 * time.Millisecond
+	s := httptest.NewServer(server.Handler())
+	t.Cleanup(s.Close)
+
+	_, next := openSSE(t, s.URL, "/events", nil)
+	assert.Equal(t, string(sseHeartbeat), next().comment)
+}
+
+func TestSSE_RejectsBadRequests(t *testing.T) {
+	server := NewServer(auth.New(auth.Config{ReadTokens: []string{"reader-token-0001"}}))
+	s := httptest.NewServer(server.Handler())
+	t.Cleanup(s.Close)
+
+	token := http.Header{"Authorization": {"Bearer reader-token-0001"}}
+	for path, want := range map[string]int{
+		"/events?types=TRADES":      http.StatusBadRequest,
+		"/events?minProfit=lots":    http.StatusBadRequest,
+		"/events?resume_from=abc":   http.StatusBadRequest,
+		"/events?directions=UP":     http.StatusBadRequest,
+		"/events?types=OPPORTUNITY": http.StatusOK,
+	} {
+		resp, _ := openSSE(t, s.URL, path, token)
+		assert.Equal(t, want, resp.StatusCode, path)
+	}
+
+	resp, _ := openSSE(t, s.URL, "/events", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
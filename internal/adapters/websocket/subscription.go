@@ -0,0 +1,78 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/gorilla/websocket"
+)
+
+var (
+	// ErrSlowSubscriber is returned by Subscription.Next once the subscriber
+	// has been dropped for falling behind. It can resume from the last event
+	// it received.
+	ErrSlowSubscriber     = errors.New("subscriber too slow")
+	ErrSubscriptionClosed = errors.New("subscription closed")
+)
+
+// Subscription receives the broadcast in process, for transports served
+// outside this package. It is queued and evicted like a connection.
+type Subscription struct {
+	// Gap is set when events after the resume point could not be replayed.
+	Gap *Gap
+
+	s      *Server
+	c      *client
+	replay []domain.ArbitrageEvent
+}
+
+// Subscribe registers a subscriber to the events matching filter, which must
+// be normalized. When resumeFrom is set, the buffered events after it are
+// delivered first.
+func (s *Server) Subscribe(filter domain.EventFilter, resumeFrom *uint64) *Subscription {
+	c := newClient(nil, s.queueSize)
+	c.subscribe(filter)
+	replay, missed := s.register(c, resumeFrom)
+	return &Subscription{Gap: missed, s: s, c: c, replay: replay}
+}
+
+// Next returns the next event, waiting until one is broadcast.
+func (sub *Subscription) Next(ctx context.Context) (domain.ArbitrageEvent, error) {
+	if len(sub.replay) > 0 {
+		e := sub.replay[0]
+		sub.replay = sub.replay[1:]
+		return e, nil
+	}
+	select {
+	case msg := <-sub.c.send:
+		return msg.(domain.ArbitrageEvent), nil
+	case <-sub.c.done:
+		if sub.c.closeCode == websocket.CloseTryAgainLater {
+			return domain.ArbitrageEvent{}, ErrSlowSubscriber
+		}
+		return domain.ArbitrageEvent{}, ErrSubscriptionClosed
+	case <-ctx.Done():
+		return domain.ArbitrageEvent{}, ctx.Err()
+	}
+}
+
+// Close unregisters the subscriber.
+func (sub *Subscription) Close() {
+	sub.s.unregister(sub.c)
+	sub.c.close(0)
+}
+
+// Subscribers returns the number of connected clients across transports.
+func (s *Server) Subscribers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+// LastSeq returns the sequence of the latest event broadcast.
+func (s *Server) LastSeq() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.log.last
+}
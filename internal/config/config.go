@@ -0,0 +1,604 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/auth"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/binance"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/kraken"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/notify"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/okx"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/services"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// Config is the bot configuration as read from the YAML file and environment.
+type Config struct {
+	Ethereum EthereumConfig `mapstructure:"ethereum"`
+	Pair     PairConfig     `mapstructure:"pair"`
+	Venues   VenuesConfig   `mapstructure:"venues"`
+	Fees     FeesConfig     `mapstructure:"fees"`
+	Risk     RiskConfig     `mapstructure:"risk"`
+	Server   ServerConfig   `mapstructure:"server"`
+	// Analytics keeps statistics on closed opportunities.
+	Analytics AnalyticsConfig `mapstructure:"analytics"`
+	// Notifiers are the outbound sinks events are pushed to.
+	Notifiers []NotifierConfig `mapstructure:"notifiers"`
+}
+
+type EthereumConfig struct {
+	WSURL          string `mapstructure:"ws_url"`
+	HTTPURL        string `mapstructure:"http_url"`
+	TokenCachePath string `mapstructure:"token_cache_path"`
+}
+
+type PairConfig struct {
+	Symbol     string      `mapstructure:"symbol"`
+	TokenIn    TokenConfig `mapstructure:"token_in"`
+	TokenOut   TokenConfig `mapstructure:"token_out"`
+	PoolFee    int64       `mapstructure:"pool_fee"`
+	TradeSizes []*big.Int  `mapstructure:"trade_sizes"`
+}
+
+type TokenConfig struct {
+	Address string `mapstructure:"address"`
+	// Decimals is checked against the chain at startup; 0 resolves it there.
+	Decimals int32 `mapstructure:"decimals"`
+}
+
+type VenuesConfig struct {
+	Provider string         `mapstructure:"provider"`
+	Binance  BinanceConfig  `mapstructure:"binance"`
+	Kraken   StreamConfig   `mapstructure:"kraken"`
+	OKX      StreamConfig   `mapstructure:"okx"`
+	Coinbase CoinbaseConfig `mapstructure:"coinbase"`
+	Bybit    RESTConfig     `mapstructure:"bybit"`
+
+	// ClockSyncInterval is how often the venue clock offset is re-estimated;
+	// 0 disables syncing.
+	ClockSyncInterval time.Duration `mapstructure:"clock_sync_interval"`
+}
+
+type BinanceConfig struct {
+	APIURL string `mapstructure:"api_url"`
+	WSURL  string `mapstructure:"ws_url"`
+	// Depth is the number of order book levels requested per side when
+	// polling REST, as for every venue. It grows automatically while books
+	// are too shallow to fill the trade sizes.
+	Depth int `mapstructure:"depth"`
+}
+
+// StreamConfig configures venues whose REST endpoint is fixed. An empty WSURL
+// polls REST instead of maintaining a streamed book.
+type StreamConfig struct {
+	WSURL string `mapstructure:"ws_url"`
+	Depth int    `mapstructure:"depth"`
+}
+
+// RESTConfig configures REST-only venues.
+type RESTConfig struct {
+	APIURL string `mapstructure:"api_url"`
+	Depth  int    `mapstructure:"depth"`
+}
+
+// CoinbaseConfig has no depth: the level 2 book is always complete.
+type CoinbaseConfig struct {
+	APIURL string `mapstructure:"api_url"`
+	// USDCAlias serves a USDC pair Coinbase does not list from its USD book.
+	// Coinbase folded its USDC books into USD, but the quotes are then in
+	// USD, so this is off unless asked for.
+	USDCAlias bool `mapstructure:"usdc_alias"`
+}
+
+type FeesConfig struct {
+	CEXTaker decimal.Decimal `mapstructure:"cex_taker"`
+}
+
+type RiskConfig struct {
+	MinProfit  decimal.Decimal `mapstructure:"min_profit"`
+	MaxWorkers int             `mapstructure:"max_workers"`
+	QuoteCache time.Duration   `mapstructure:"quote_cache"`
+	// MinBookLevels is the fewest levels each side of the CEX book needs to
+	// be evaluated.
+	MinBookLevels int `mapstructure:"min_book_levels"`
+	// MaxBookSkew is the largest allowed gap between the CEX book and block
+	// timestamps; 0 disables the check.
+	MaxBookSkew time.Duration `mapstructure:"max_book_skew"`
+	// BookSkewAction is "reject" to skip evaluations past MaxBookSkew or
+	// "flag" to report them marked as stale.
+	BookSkewAction string `mapstructure:"book_skew_action"`
+	// MaterialChange is the relative change in profit that reports an open
+	// opportunity again.
+	MaterialChange float64 `mapstructure:"material_change"`
+}
+
+type AnalyticsConfig struct {
+	// HistoryPath is the file closed opportunities are appended to; empty
+	// keeps them in memory only.
+	HistoryPath string `mapstructure:"history_path"`
+}
+
+type ServerConfig struct {
+	Port        string `mapstructure:"port"`
+	MetricsPort string `mapstructure:"metrics_port"`
+	// GRPCPort serves the gRPC API; empty disables it.
+	GRPCPort string `mapstructure:"grpc_port"`
+	// AllowedOrigins are the browser origins allowed to use the API; empty
+	// allows same-origin requests only, "*" allows any.
+	AllowedOrigins []string   `mapstructure:"allowed_origins"`
+	Auth           AuthConfig `mapstructure:"auth"`
+}
+
+// AuthConfig holds the credentials accepted by the API. With none set the
+// API is open to anyone who can reach it.
+type AuthConfig struct {
+	// JWTSecret verifies HS256 tokens carrying a "read" or "admin" scope.
+	JWTSecret   string   `mapstructure:"jwt_secret"`
+	ReadTokens  []string `mapstructure:"read_tokens"`
+	AdminTokens []string `mapstructure:"admin_tokens"`
+}
+
+// NotifierConfig configures one notifier sink. URL, Secret and BotToken may
+// reference environment variables as ${NAME}, keeping credentials out of the
+// file.
+type NotifierConfig struct {
+	Kind string `mapstructure:"kind"`
+	Name string `mapstructure:"name"`
+	URL  string `mapstructure:"url"`
+	// Secret signs webhook bodies.
+	Secret   string `mapstructure:"secret"`
+	BotToken string `mapstructure:"bot_token"`
+	ChatID   string `mapstructure:"chat_id"`
+
+	Filter   NotifierFilter `mapstructure:"filter"`
+	Template string         `mapstructure:"template"`
+
+	// Zero values use the sink defaults.
+	QueueSize    int           `mapstructure:"queue_size"`
+	MaxAttempts  int           `mapstructure:"max_attempts"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	Timeout      time.Duration `mapstructure:"timeout"`
+	// Cooldown is the least time between messages about one opportunity.
+	Cooldown time.Duration `mapstructure:"cooldown"`
+}
+
+// NotifierFilter selects the events a sink sends, as the stream filters do.
+// Without types only opportunity lifecycle events are sent.
+type NotifierFilter struct {
+	Symbols    []string `mapstructure:"symbols"`
+	Venues     []string `mapstructure:"venues"`
+	Types      []string `mapstructure:"types"`
+	Directions []string `mapstructure:"directions"`
+	MinProfit  float64  `mapstructure:"min_profit"`
+}
+
+// Minimum credential lengths, so that a placeholder cannot guard the API.
+const (
+	minJWTSecretLen = 32
+	minTokenLen     = 16
+)
+
+var defaults = map[string]any{
+	"ethereum.ws_url":            "wss://mainnet.infura.io/ws/v3/YOUR_KEY",
+	"ethereum.http_url":          "https://mainnet.infura.io/v3/YOUR_KEY",
+	"ethereum.token_cache_path":  ".cache/tokens.json",
+	"pair.symbol":                "ETHUSDC",
+	"pair.token_in.address":      "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+	"pair.token_in.decimals":     0,
+	"pair.token_out.address":     "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+	"pair.token_out.decimals":    0,
+	"pair.pool_fee":              3000,
+	"pair.trade_sizes":           "1000000000000000000,10000000000000000000",
+	"venues.provider":            "binance",
+	"venues.clock_sync_interval": "5m",
+	"venues.binance.api_url":     "https://api.binance.com/api/v3",
+	"venues.binance.ws_url":      binance.WSURL,
+	"venues.binance.depth":       100,
+	"venues.kraken.ws_url":       kraken.WSURL,
+	"venues.kraken.depth":        100,
+	"venues.okx.ws_url":          okx.WSURL,
+	"venues.okx.depth":           100,
+	"venues.coinbase.api_url":    "https://api.exchange.coinbase.com",
+	"venues.coinbase.usdc_alias": false,
+	"venues.bybit.api_url":       "https://api.bybit.com",
+	"venues.bybit.depth":         200,
+	"fees.cex_taker":             "0.001",
+	"risk.min_profit":            "10.0",
+	"risk.max_workers":           5,
+	"risk.quote_cache":           "10s",
+	"risk.max_book_skew":         "15s",
+	"risk.min_book_levels":       5,
+	"risk.book_skew_action":      "reject",
+	"risk.material_change":       0.1,
+	"server.port":                "8080",
+	"server.metrics_port":        "8085",
+	"server.grpc_port":           "50051",
+	"server.allowed_origins":     "",
+	"server.auth.jwt_secret":     "",
+	"server.auth.read_tokens":    "",
+	"server.auth.admin_tokens":   "",
+	"analytics.history_path":     ".cache/opportunities.jsonl",
+}
+
+// envBindings maps config keys to the environment variables that override
+// them. The names predate the config file and are kept for existing
+// deployments.
+var envBindings = map[string]string{
+	"ethereum.ws_url":            "ETH_NODE_WS",
+	"ethereum.http_url":          "ETH_NODE_HTTP",
+	"ethereum.token_cache_path":  "TOKEN_CACHE_PATH",
+	"pair.symbol":                "SYMBOL",
+	"pair.token_in.address":      "TOKEN_IN",
+	"pair.token_in.decimals":     "TOKEN_IN_DEC",
+	"pair.token_out.address":     "TOKEN_OUT",
+	"pair.token_out.decimals":    "TOKEN_OUT_DEC",
+	"pair.pool_fee":              "POOL_FEE",
+	"pair.trade_sizes":           "TRADE_SIZES",
+	"venues.provider":            "CEX_PROVIDER",
+	"venues.clock_sync_interval": "CLOCK_SYNC_INTERVAL",
+	"venues.binance.api_url":     "BINANCE_API_URL",
+	"venues.binance.ws_url":      "BINANCE_WS_URL",
+	"venues.binance.depth":       "BINANCE_BOOK_DEPTH",
+	"venues.kraken.ws_url":       "KRAKEN_WS_URL",
+	"venues.kraken.depth":        "KRAKEN_BOOK_DEPTH",
+	"venues.okx.ws_url":          "OKX_WS_URL",
+	"venues.okx.depth":           "OKX_BOOK_DEPTH",
+	"venues.coinbase.api_url":    "COINBASE_API_URL",
+	"venues.coinbase.usdc_alias": "COINBASE_USDC_ALIAS",
+	"venues.bybit.api_url":       "BYBIT_API_URL",
+	"venues.bybit.depth":         "BYBIT_BOOK_DEPTH",
+	"fees.cex_taker":             "CEX_TAKER_FEE",
+	"risk.min_profit":            "MIN_PROFIT",
+	"risk.max_workers":           "MAX_WORKERS",
+	"risk.quote_cache":           "QUOTE_CACHE",
+	"risk.max_book_skew":         "MAX_BOOK_SKEW",
+	"risk.min_book_levels":       "MIN_BOOK_LEVELS",
+	"risk.book_skew_action":      "BOOK_SKEW_ACTION",
+	"risk.material_change":       "MATERIAL_CHANGE",
+	"server.port":                "PORT",
+	"server.metrics_port":        "METRICS_PORT",
+	"server.grpc_port":           "GRPC_PORT",
+	"server.allowed_origins":     "ALLOWED_ORIGINS",
+	"server.auth.jwt_secret":     "AUTH_JWT_SECRET",
+	"server.auth.read_tokens":    "AUTH_READ_TOKENS",
+	"server.auth.admin_tokens":   "AUTH_ADMIN_TOKENS",
+	"analytics.history_path":     "OPPORTUNITY_HISTORY_PATH",
+}
+
+var (
+	providers   = map[string]bool{"binance": true, "kraken": true, "okx": true, "coinbase": true, "bybit": true}
+	skewActions = map[string]bool{"reject": true, "flag": true}
+	poolFees    = map[int64]bool{100: true, 500: true, 3000: true, 10000: true}
+)
+
+// Loader reads the configuration from an optional YAML file, with environment
+// variables taking precedence over file values.
+type Loader struct {
+	v    *viper.Viper
+	path string
+}
+
+// NewLoader creates a loader for the YAML file at path. An empty path loads
+// the configuration from defaults and the environment only.
+func NewLoader(path string) *Loader {
+	v := viper.New()
+	v.AllowEmptyEnv(true)
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+	for key, env := range envBindings {
+		_ = v.BindEnv(key, env)
+	}
+	if path != "" {
+		v.SetConfigFile(path)
+	}
+	return &Loader{v: v, path: path}
+}
+
+// Load reads and validates the configuration. The returned error joins every
+// problem found rather than stopping at the first one.
+func (l *Loader) Load() (*Config, error) {
+	if l.path != "" {
+		if err := l.v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+	return l.decode()
+}
+
+func (l *Loader) decode() (*Config, error) {
+	var cfg Config
+	var errs []error
+	if err := l.v.Unmarshal(&cfg, viper.DecodeHook(decodeHook())); err != nil {
+		// Report each field error on its own rather than under
+		// mapstructure's summary header.
+		if joined, ok := errors.Unwrap(err).(interface{ Unwrap() []error }); ok {
+			errs = append(errs, joined.Unwrap()...)
+		} else {
+			errs = append(errs, err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	return &cfg, errors.Join(errs...)
+}
+
+// Watch calls onChange with the new configuration each time the config file
+// changes. Edits that fail validation are logged and ignored.
+func (l *Loader) Watch(onChange func(*Config)) {
+	if l.path == "" {
+		return
+	}
+	l.v.OnConfigChange(func(e fsnotify.Event) {
+		cfg, err := l.decode()
+		if err != nil {
+			slog.Error("Ignoring invalid config change", "file", e.Name, "error", err)
+			return
+		}
+		onChange(cfg)
+	})
+	l.v.WatchConfig()
+}
+
+// Validate checks the configuration and returns all problems found.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	check(c.Ethereum.WSURL != "", "ethereum.ws_url", "is required")
+	check(c.Ethereum.HTTPURL != "", "ethereum.http_url", "is required")
+
+	check(c.Pair.Symbol != "", "pair.symbol", "is required")
+	for name, token := range map[string]TokenConfig{"pair.token_in": c.Pair.TokenIn, "pair.token_out": c.Pair.TokenOut} {
+		check(common.IsHexAddress(token.Address), name+".address", "%q is not a valid address", token.Address)
+		check(token.Decimals >= 0 && token.Decimals <= 77, name+".decimals", "must be between 0 and 77, got %d", token.Decimals)
+	}
+	check(!strings.EqualFold(c.Pair.TokenIn.Address, c.Pair.TokenOut.Address), "pair.token_out.address", "must differ from pair.token_in.address")
+	check(poolFees[c.Pair.PoolFee], "pair.pool_fee", "must be one of 100, 500, 3000, 10000, got %d", c.Pair.PoolFee)
+	check(len(c.Pair.TradeSizes) > 0, "pair.trade_sizes", "at least one size is required")
+	for i, size := range c.Pair.TradeSizes {
+		check(size != nil && size.Sign() > 0, fmt.Sprintf("pair.trade_sizes[%d]", i), "must be positive")
+	}
+
+	check(providers[strings.ToLower(c.Venues.Provider)], "venues.provider", "unknown provider %q", c.Venues.Provider)
+	check(c.Venues.ClockSyncInterval >= 0, "venues.clock_sync_interval", "must not be negative, got %s", c.Venues.ClockSyncInterval)
+	check(c.Venues.Binance.APIURL != "", "venues.binance.api_url", "is required")
+	check(c.Venues.Coinbase.APIURL != "", "venues.coinbase.api_url", "is required")
+	check(c.Venues.Bybit.APIURL != "", "venues.bybit.api_url", "is required")
+	for name, depth := range map[string]int{"binance": c.Venues.Binance.Depth, "kraken": c.Venues.Kraken.Depth, "okx": c.Venues.OKX.Depth, "bybit": c.Venues.Bybit.Depth} {
+		check(depth > 0, "venues."+name+".depth", "must be positive, got %d", depth)
+	}
+
+	check(!c.Fees.CEXTaker.IsNegative() && c.Fees.CEXTaker.LessThan(decimal.NewFromInt(1)), "fees.cex_taker", "must be in [0, 1), got %s", c.Fees.CEXTaker)
+
+	check(!c.Risk.MinProfit.IsNegative(), "risk.min_profit", "must not be negative, got %s", c.Risk.MinProfit)
+	check(c.Risk.MaxWorkers > 0, "risk.max_workers", "must be positive, got %d", c.Risk.MaxWorkers)
+	check(c.Risk.QuoteCache >= 0, "risk.quote_cache", "must not be negative, got %s", c.Risk.QuoteCache)
+	check(c.Risk.MinBookLevels >= 1, "risk.min_book_levels", "must be at least 1, got %d", c.Risk.MinBookLevels)
+	check(c.Risk.MaxBookSkew >= 0, "risk.max_book_skew", "must not be negative, got %s", c.Risk.MaxBookSkew)
+	check(skewActions[strings.ToLower(c.Risk.BookSkewAction)], "risk.book_skew_action", "must be reject or flag, got %q", c.Risk.BookSkewAction)
+	check(c.Risk.MaterialChange >= 0, "risk.material_change", "must not be negative, got %v", c.Risk.MaterialChange)
+
+	check(c.Server.Port != "", "server.port", "is required")
+	check(c.Server.MetricsPort != "", "server.metrics_port", "is required")
+	check(c.Server.Auth.JWTSecret == "" || len(c.Server.Auth.JWTSecret) >= minJWTSecretLen, "server.auth.jwt_secret", "must be at least %d bytes", minJWTSecretLen)
+	for name, tokens := range map[string][]string{"server.auth.read_tokens": c.Server.Auth.ReadTokens, "server.auth.admin_tokens": c.Server.Auth.AdminTokens} {
+		for i, token := range tokens {
+			check(len(token) >= minTokenLen, fmt.Sprintf("%s[%d]", name, i), "must be at least %d characters", minTokenLen)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, n := range c.Notifiers {
+		key := fmt.Sprintf("notifiers[%d]", i)
+		sink, err := n.sink()
+		if err == nil {
+			_, err = notify.NewSink(sink)
+		}
+		check(err == nil, key, "%v", err)
+		check(!names[sink.Name], key+".name", "%q is already used", sink.Name)
+		names[sink.Name] = true
+	}
+
+	return errors.Join(errs...)
+}
+
+// sink converts n to a sink configuration, expanding environment references
+// and normalizing the filter.
+func (n NotifierConfig) sink() (notify.Config, error) {
+	cfg := notify.Config{
+		Kind:     strings.ToLower(n.Kind),
+		Name:     n.Name,
+		URL:      os.ExpandEnv(n.URL),
+		BotToken: os.ExpandEnv(n.BotToken),
+		ChatID:   n.ChatID,
+		Filter: domain.EventFilter{
+			Symbols:    n.Filter.Symbols,
+			Venues:     n.Filter.Venues,
+			Types:      n.Filter.Types,
+			Directions: n.Filter.Directions,
+			MinProfit:  n.Filter.MinProfit,
+		},
+		Template:     n.Template,
+		QueueSize:    n.QueueSize,
+		MaxAttempts:  n.MaxAttempts,
+		RetryBackoff: n.RetryBackoff,
+		Timeout:      n.Timeout,
+		Cooldown:     n.Cooldown,
+	}
+	if cfg.Name == "" {
+		cfg.Name = cfg.Kind
+	}
+	if secret := os.ExpandEnv(n.Secret); secret != "" {
+		cfg.Secret = []byte(secret)
+	}
+	return cfg, cfg.Filter.Normalize()
+}
+
+// Limits returns the thresholds that can be applied to a running Manager.
+func (c *Config) Limits() services.Limits {
+	return services.Limits{
+		MinProfit:  c.Risk.MinProfit,
+		TradeSizes: c.Pair.TradeSizes,
+	}
+}
+
+// Engine converts the configuration to the engine's flat form.
+func (c *Config) Engine() engine.Config {
+	return engine.Config{
+		Config: services.Config{
+			Venue:         strings.ToLower(c.Venues.Provider),
+			Symbol:        c.Pair.Symbol,
+			TokenInAddr:   c.Pair.TokenIn.Address,
+			TokenOutAddr:  c.Pair.TokenOut.Address,
+			TokenInDec:    c.Pair.TokenIn.Decimals,
+			TokenOutDec:   c.Pair.TokenOut.Decimals,
+			PoolFee:       c.Pair.PoolFee,
+			TradeSizes:    c.Pair.TradeSizes,
+			MinProfit:     c.Risk.MinProfit,
+			CEXFee:        c.Fees.CEXTaker,
+			MaxWorkers:    c.Risk.MaxWorkers,
+			CacheDuration: c.Risk.QuoteCache,
+
+			MinBookLevels:     c.Risk.MinBookLevels,
+			MaxBookSkew:       c.Risk.MaxBookSkew,
+			RejectSkewedBooks: strings.EqualFold(c.Risk.BookSkewAction, "reject"),
+			ClockSyncInterval: c.Venues.ClockSyncInterval,
+			MaterialChange:    c.Risk.MaterialChange,
+		},
+		EthNodeWS:         c.Ethereum.WSURL,
+		EthNodeHTTP:       c.Ethereum.HTTPURL,
+		Port:              c.Server.Port,
+		MetricsPort:       c.Server.MetricsPort,
+		GRPCPort:          c.Server.GRPCPort,
+		CEXProvider:       c.Venues.Provider,
+		BookDepth:         c.Venues.bookDepth(),
+		BinanceAPIURL:     c.Venues.Binance.APIURL,
+		BinanceWSURL:      c.Venues.Binance.WSURL,
+		KrakenWSURL:       c.Venues.Kraken.WSURL,
+		OKXWSURL:          c.Venues.OKX.WSURL,
+		CoinbaseAPIURL:    c.Venues.Coinbase.APIURL,
+		CoinbaseUSDCAlias: c.Venues.Coinbase.USDCAlias,
+		BybitAPIURL:       c.Venues.Bybit.APIURL,
+		TokenCachePath:    c.Ethereum.TokenCachePath,
+		HistoryPath:       c.Analytics.HistoryPath,
+		Auth: auth.Config{
+			AllowedOrigins: c.Server.AllowedOrigins,
+			JWTSecret:      []byte(c.Server.Auth.JWTSecret),
+			ReadTokens:     c.Server.Auth.ReadTokens,
+			AdminTokens:    c.Server.Auth.AdminTokens,
+		},
+		Notifiers: c.notifiers(),
+	}
+}
+
+// notifiers returns the sink configurations. They were checked by Validate.
+func (c *Config) notifiers() []notify.Config {
+	sinks := make([]notify.Config, 0, len(c.Notifiers))
+	for _, n := range c.Notifiers {
+		sink, _ := n.sink()
+		sinks = append(sinks, sink)
+	}
+	return sinks
+}
+
+func decodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		commaSliceHook,
+		decimalHook,
+		bigIntHook,
+	)
+}
+
+// commaSliceHook splits comma separated strings, as set through the
+// environment, into list values such as trade sizes.
+func commaSliceHook(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to.Kind() != reflect.Slice {
+		return data, nil
+	}
+	var parts []string
+	for _, p := range strings.Split(data.(string), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts, nil
+}
+
+var (
+	decimalType = reflect.TypeOf(decimal.Decimal{})
+	bigIntType  = reflect.TypeOf(&big.Int{})
+)
+
+func decimalHook(from, to reflect.Type, data any) (any, error) {
+	if to != decimalType {
+		return data, nil
+	}
+	switch v := data.(type) {
+	case string:
+		return decimal.NewFromString(strings.TrimSpace(v))
+	case int:
+		return decimal.NewFromInt(int64(v)), nil
+	case float64:
+		return decimal.NewFromFloat(v), nil
+	}
+	return data, nil
+}
+
+// bigIntHook parses wei amounts. Large values should be quoted in YAML, as
+// unquoted integers beyond uint64 are decoded as lossy floats.
+func bigIntHook(from, to reflect.Type, data any) (any, error) {
+	if to != bigIntType {
+		return data, nil
+	}
+	switch v := data.(type) {
+	case string:
+		n, ok := new(big.Int).SetString(strings.TrimSpace(v), 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", v)
+		}
+		return n, nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case float64:
+		return nil, fmt.Errorf("%v must be written as a quoted integer", v)
+	}
+	return data, nil
+}
+
+// bookDepth returns the configured depth of the selected provider.
+func (c VenuesConfig) bookDepth() int {
+	switch strings.ToLower(c.Provider) {
+	case "kraken":
+		return c.Kraken.Depth
+	case "okx":
+		return c.OKX.Depth
+	case "bybit":
+		return c.Bybit.Depth
+	case "coinbase":
+		return 0
+	default:
+		return c.Binance.Depth
+	}
+}
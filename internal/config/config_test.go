@@ -0,0 +1,209 @@
+package config
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/binance"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/kraken"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/okx"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/services"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoad_ExampleFile(t *testing.T) {
+	cfg, err := NewLoader("../../config.example.yaml").Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "ETHUSDC", cfg.Pair.Symbol)
+	assert.Equal(t, int32(18), cfg.Pair.TokenIn.Decimals)
+	require.Len(t, cfg.Pair.TradeSizes, 2)
+	expected, _ := new(big.Int).SetString("10000000000000000000", 10)
+	assert.Equal(t, expected, cfg.Pair.TradeSizes[1])
+	assert.True(t, cfg.Risk.MinProfit.Equal(decimal.NewFromInt(10)))
+	assert.True(t, cfg.Fees.CEXTaker.Equal(decimal.RequireFromString("0.001")))
+	assert.Equal(t, 10*time.Second, cfg.Risk.QuoteCache)
+	assert.Equal(t, 15*time.Second, cfg.Risk.MaxBookSkew)
+	assert.True(t, cfg.Engine().RejectSkewedBooks)
+	assert.Equal(t, 100, cfg.Engine().BookDepth)
+	assert.Equal(t, 0.1, cfg.Engine().MaterialChange)
+	assert.Equal(t, ".cache/opportunities.jsonl", cfg.Engine().HistoryPath)
+	assert.Equal(t, binance.WSURL, cfg.Engine().BinanceWSURL)
+}
+
+func TestLoad_EnvOverridesFile(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), `
+risk:
+  min_profit: 5
+venues:
+  binance:
+    ws_url: wss://example.com/ws
+`)
+	t.Setenv("MIN_PROFIT", "25.5")
+	t.Setenv("TRADE_SIZES", "1,2,3")
+	t.Setenv("BINANCE_WS_URL", "")
+	t.Setenv("COINBASE_USDC_ALIAS", "true")
+
+	cfg, err := NewLoader(path).Load()
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Risk.MinProfit.Equal(decimal.RequireFromString("25.5")))
+	assert.Equal(t, []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}, cfg.Pair.TradeSizes)
+	assert.Empty(t, cfg.Venues.Binance.WSURL)
+	assert.Equal(t, kraken.WSURL, cfg.Engine().KrakenWSURL, "venues missing from the file keep their defaults")
+	assert.Equal(t, okx.WSURL, cfg.Engine().OKXWSURL)
+	assert.True(t, cfg.Engine().CoinbaseUSDCAlias)
+}
+
+func TestLoad_AuthFromEnv(t *testing.T) {
+	t.Setenv("ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("AUTH_JWT_SECRET", "0123456789abcdef0123456789abcdef")
+	t.Setenv("AUTH_READ_TOKENS", "reader-token-0001,reader-token-0002")
+
+	cfg, err := NewLoader("").Load()
+	require.NoError(t, err)
+
+	a := cfg.Engine().Auth
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, a.AllowedOrigins)
+	assert.Equal(t, []byte("0123456789abcdef0123456789abcdef"), a.JWTSecret)
+	assert.Equal(t, []string{"reader-token-0001", "reader-token-0002"}, a.ReadTokens)
+	assert.Empty(t, a.AdminTokens)
+}
+
+func TestLoad_Notifiers(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), `
+notifiers:
+  - kind: webhook
+    url: https://hooks.example.com/arb
+    secret: ${WEBHOOK_SECRET}
+    filter:
+      symbols: [eth/usdc]
+      min_profit: 25
+    retry_backoff: 2s
+    cooldown: 5m
+  - kind: telegram
+    name: desk
+    bot_token: ${TELEGRAM_TOKEN}
+    chat_id: "-100200"
+    template: "{{.Data.Symbol}}"
+`)
+	t.Setenv("WEBHOOK_SECRET", "s3cret")
+	t.Setenv("TELEGRAM_TOKEN", "123:abc")
+
+	cfg, err := NewLoader(path).Load()
+	require.NoError(t, err)
+
+	sinks := cfg.Engine().Notifiers
+	require.Len(t, sinks, 2)
+	assert.Equal(t, "webhook", sinks[0].Name)
+	assert.Equal(t, []byte("s3cret"), sinks[0].Secret)
+	assert.Equal(t, []string{"ETHUSDC"}, sinks[0].Filter.Symbols)
+	assert.Equal(t, 25.0, sinks[0].Filter.MinProfit)
+	assert.Equal(t, 2*time.Second, sinks[0].RetryBackoff)
+	assert.Equal(t, 5*time.Minute, sinks[0].Cooldown)
+	assert.Equal(t, "desk", sinks[1].Name)
+	assert.Equal(t, "123:abc", sinks[1].BotToken)
+	assert.Equal(t, "-100200", sinks[1].ChatID)
+}
+
+func TestLoad_ReportsAllErrors(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), `
+pair:
+  token_in:
+    address: not-an-address
+  pool_fee: 42
+  trade_sizes: ["abc"]
+venues:
+  provider: ftx
+  kraken:
+    depth: 0
+risk:
+  min_profit: -1
+  max_workers: 0
+  book_skew_action: ignore
+  material_change: -1
+server:
+  auth:
+    jwt_secret: short
+    admin_tokens: [changeme]
+notifiers:
+  - kind: pager
+  - kind: slack
+    url: https://hooks.slack.com/services/x
+    filter:
+      types: [trade]
+  - kind: slack
+    url: https://hooks.slack.com/services/y
+`)
+
+	_, err := NewLoader(path).Load()
+	require.Error(t, err)
+
+	for _, key := range []string{
+		"pair.token_in.address",
+		"pair.pool_fee",
+		"trade_sizes",
+		"venues.provider",
+		"venues.kraken.depth",
+		"risk.min_profit",
+		"risk.max_workers",
+		"risk.book_skew_action",
+		"risk.material_change",
+		"server.auth.jwt_secret",
+		"server.auth.admin_tokens[0]",
+		"notifiers[0]",
+		"notifiers[1]",
+		"notifiers[2].name",
+	} {
+		assert.True(t, strings.Contains(err.Error(), key), "missing error for %s in:\n%v", key, err)
+	}
+}
+
+func TestWatch_AppliesValidChanges(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "risk:\n  min_profit: 10\n")
+
+	loader := NewLoader(path)
+	_, err := loader.Load()
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var limits []services.Limits
+	loader.Watch(func(cfg *Config) {
+		mu.Lock()
+		limits = append(limits, cfg.Limits())
+		mu.Unlock()
+	})
+
+	// An invalid edit is ignored; the following valid one is applied.
+	writeConfig(t, dir, "risk:\n  min_profit: -3\n")
+	time.Sleep(100 * time.Millisecond)
+	writeConfig(t, dir, "risk:\n  min_profit: 42\npair:\n  trade_sizes: [\"5\"]\n")
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(limits) > 0 && limits[len(limits)-1].MinProfit.Equal(decimal.NewFromInt(42))
+	}, 2*time.Second, 20*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, l := range limits {
+		assert.False(t, l.MinProfit.IsNegative())
+	}
+	assert.Equal(t, []*big.Int{big.NewInt(5)}, limits[len(limits)-1].TradeSizes)
+}
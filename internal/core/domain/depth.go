@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Depth is one side of an order book with cumulative quantity and notional
+// precomputed, so fills of any size are answered with a binary search.
+// Levels must be ordered best first, as checked by OrderBook.Validate.
+type Depth struct {
+	levels []PriceLevel
+	// cumQty[i] and cumNotional[i] sum levels[0..i].
+	cumQty      []decimal.Decimal
+	cumNotional []decimal.Decimal
+	ascending   bool
+}
+
+// BookDepth holds the precomputed depth of both sides of a snapshot.
+type BookDepth struct {
+	Bids *Depth
+	Asks *Depth
+}
+
+// Depth precomputes both sides of the book. Build it once per snapshot and
+// reuse it for every size evaluated.
+func (ob *OrderBook) Depth() *BookDepth {
+	return &BookDepth{
+		Bids: NewDepth(ob.Bids, false),
+		Asks: NewDepth(ob.Asks, true),
+	}
+}
+
+// Side returns the asks for "buy" and the bids otherwise, matching
+// CalculateEffectivePrice.
+func (b *BookDepth) Side(side string) *Depth {
+	if side == "buy" {
+		return b.Asks
+	}
+	return b.Bids
+}
+
+// EffectivePrice returns the same result as OrderBook.CalculateEffectivePrice.
+func (b *BookDepth) EffectivePrice(side string, amount decimal.Decimal) (decimal.Decimal, bool) {
+	return b.Side(side).EffectivePrice(amount)
+}
+
+// NewDepth precomputes a side whose prices are ascending (asks) or
+// descending (bids).
+func NewDepth(levels []PriceLevel, ascending bool) *Depth {
+	d := &Depth{
+		levels:      levels,
+		cumQty:      make([]decimal.Decimal, len(levels)),
+		cumNotional: make([]decimal.Decimal, len(levels)),
+		ascending:   ascending,
+	}
+	qty, notional := decimal.Zero, decimal.Zero
+	for i, l := range levels {
+		qty = qty.Add(l.Amount)
+		notional = notional.Add(l.Amount.Mul(l.Price))
+		d.cumQty[i] = qty
+		d.cumNotional[i] = notional
+	}
+	return d
+}
+
+// Total returns the quantity available on the side.
+func (d *Depth) Total() decimal.Decimal {
+	if len(d.cumQty) == 0 {
+		return decimal.Zero
+	}
+	return d.cumQty[len(d.cumQty)-1]
+}
+
+// EffectivePrice returns the volume-weighted price of filling amount, or
+// false if the side cannot fill it. Sums are exact, so the result matches a
+// level-by-level walk.
+func (d *Depth) EffectivePrice(amount decimal.Decimal) (decimal.Decimal, bool) {
+	i, ok := d.fillLevel(amount)
+	if !ok {
+		return decimal.Zero, false
+	}
+	return d.notionalAt(i, amount).Div(amount), true
+}
+
+// Notional returns the total cost of filling amount, or false if the side
+// cannot fill it.
+func (d *Depth) Notional(amount decimal.Decimal) (decimal.Decimal, bool) {
+	i, ok := d.fillLevel(amount)
+	if !ok {
+		return decimal.Zero, false
+	}
+	return d.notionalAt(i, amount), true
+}
+
+// MarginalPrice returns the price of the level the last unit of amount is
+// filled at, or false if the side cannot fill it.
+func (d *Depth) MarginalPrice(amount decimal.Decimal) (decimal.Decimal, bool) {
+	i, ok := d.fillLevel(amount)
+	if !ok {
+		return decimal.Zero, false
+	}
+	return d.levels[i].Price, true
+}
+
+// MaxFillable returns the quantity available at limit or better: at or
+// below limit for asks, at or above it for bids.
+func (d *Depth) MaxFillable(limit decimal.Decimal) decimal.Decimal {
+	n := sort.Search(len(d.levels), func(i int) bool {
+		if d.ascending {
+			return d.levels[i].Price.GreaterThan(limit)
+		}
+		return d.levels[i].Price.LessThan(limit)
+	})
+	if n == 0 {
+		return decimal.Zero
+	}
+	return d.cumQty[n-1]
+}
+
+// fillLevel returns the index of the first level whose cumulative quantity
+// covers amount.
+func (d *Depth) fillLevel(amount decimal.Decimal) (int, bool) {
+	if !amount.IsPositive() {
+		return 0, false
+	}
+	i := sort.Search(len(d.cumQty), func(i int) bool { return d.cumQty[i].GreaterThanOrEqual(amount) })
+	return i, i < len(d.cumQty)
+}
+
+// notionalAt is the cost of amount when it completes at level i: every level
+// before i in full, and the remainder at level i's price.
+func (d *Depth) notionalAt(i int, amount decimal.Decimal) decimal.Decimal {
+	if i == 0 {
+		return amount.Mul(d.levels[0].Price)
+	}
+	remaining := amount.Sub(d.cumQty[i-1])
+	return d.cumNotional[i-1].Add(remaining.Mul(d.levels[i].Price))
+}
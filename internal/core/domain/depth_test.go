@@ -0,0 +1,105 @@
+package domain_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// randomBook builds a valid book of n levels per side around 2000.
+func randomBook(r *rand.Rand, n int) *domain.OrderBook {
+	ob := &domain.OrderBook{}
+	bid, ask := decimal.NewFromInt(2000), decimal.RequireFromString("2000.01")
+	for i := 0; i < n; i++ {
+		ob.Bids = append(ob.Bids, domain.PriceLevel{Price: bid, Amount: decimal.New(r.Int63n(100000)+1, -4)})
+		ob.Asks = append(ob.Asks, domain.PriceLevel{Price: ask, Amount: decimal.New(r.Int63n(100000)+1, -4)})
+		bid = bid.Sub(decimal.New(r.Int63n(100)+1, -2))
+		ask = ask.Add(decimal.New(r.Int63n(100)+1, -2))
+	}
+	return ob
+}
+
+func TestBookDepth_MatchesLinearWalk(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		ob := randomBook(r, 50)
+		depth := ob.Depth()
+
+		for _, side := range []string{"buy", "sell"} {
+			for i := 0; i < 50; i++ {
+				amount := decimal.New(r.Int63n(3000000)+1, -4)
+
+				want, wantOK := ob.CalculateEffectivePrice(side, amount)
+				got, ok := depth.EffectivePrice(side, amount)
+
+				require.Equal(t, wantOK, ok, "%s %s", side, amount)
+				assert.Equal(t, want.String(), got.String(), "%s %s", side, amount)
+			}
+		}
+	}
+}
+
+func TestDepth_Lookups(t *testing.T) {
+	asks := domain.NewDepth([]domain.PriceLevel{level("100", "1"), level("101", "2"), level("103", "3")}, true)
+	bids := domain.NewDepth([]domain.PriceLevel{level("99", "1"), level("98", "2")}, false)
+
+	price, ok := asks.EffectivePrice(decimal.NewFromInt(3))
+	require.True(t, ok)
+	assert.Equal(t, "100.6666666666666667", price.String())
+
+	notional, ok := asks.Notional(decimal.NewFromInt(4))
+	require.True(t, ok)
+	assert.True(t, notional.Equal(decimal.NewFromInt(405)))
+
+	marginal, ok := asks.MarginalPrice(decimal.RequireFromString("3.5"))
+	require.True(t, ok)
+	assert.True(t, marginal.Equal(decimal.NewFromInt(103)))
+
+	_, ok = asks.EffectivePrice(decimal.NewFromInt(7))
+	assert.False(t, ok)
+	_, ok = asks.EffectivePrice(decimal.Zero)
+	assert.False(t, ok)
+
+	assert.True(t, asks.Total().Equal(decimal.NewFromInt(6)))
+	assert.True(t, asks.MaxFillable(decimal.NewFromInt(102)).Equal(decimal.NewFromInt(3)))
+	assert.True(t, asks.MaxFillable(decimal.NewFromInt(99)).IsZero())
+	assert.True(t, bids.MaxFillable(decimal.NewFromInt(98)).Equal(decimal.NewFromInt(3)))
+	assert.True(t, bids.MaxFillable(decimal.RequireFromString("98.5")).Equal(decimal.NewFromInt(1)))
+}
+
+func BenchmarkEffectivePrice(b *testing.B) {
+	ob := randomBook(rand.New(rand.NewSource(1)), 1000)
+	amounts := make([]decimal.Decimal, 64)
+	for i := range amounts {
+		amounts[i] = decimal.NewFromInt(int64(i+1) * 50)
+	}
+
+	b.Run("linear", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, amt := range amounts {
+				ob.CalculateEffectivePrice("buy", amt)
+			}
+		}
+	})
+	b.Run("depth", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			depth := ob.Depth()
+			for _, amt := range amounts {
+				depth.EffectivePrice("buy", amt)
+			}
+		}
+	})
+	b.Run("depth_prebuilt", func(b *testing.B) {
+		depth := ob.Depth()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, amt := range amounts {
+				depth.EffectivePrice("buy", amt)
+			}
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"math/big"
 	"time"
 
@@ -8,9 +9,30 @@ import (
 )
 
 type OrderBook struct {
-	Bids      []PriceLevel
-	Asks      []PriceLevel
+	Bids []PriceLevel
+	Asks []PriceLevel
+	// Timestamp is when the book was received or last updated locally.
 	Timestamp time.Time
+	// ExchangeTime is the venue's timestamp for the book, converted to the
+	// local clock. It is zero when the venue does not report one.
+	ExchangeTime time.Time
+	// LiveAt is, for streamed books, the last time the stream was seen alive
+	// while the book was synced: the book was still current then, even if
+	// nothing changed.
+	LiveAt time.Time
+}
+
+// Time returns when the book was last known to be current: the exchange time
+// when known, the local receive time otherwise, or a later LiveAt.
+func (ob *OrderBook) Time() time.Time {
+	t := ob.Timestamp
+	if !ob.ExchangeTime.IsZero() {
+		t = ob.ExchangeTime
+	}
+	if ob.LiveAt.After(t) {
+		return ob.LiveAt
+	}
+	return t
 }
 
 type Block struct {
@@ -23,6 +45,8 @@ type Slot0 struct {
 	Tick         *big.Int
 }
 
+// CalculateEffectivePrice walks the book to price a single fill. Use Depth
+// when evaluating several sizes against the same snapshot.
 func (ob *OrderBook) CalculateEffectivePrice(side string, amount decimal.Decimal) (decimal.Decimal, bool) {
 	var levels []PriceLevel
 	if side == "buy" {
@@ -67,6 +91,14 @@ type PriceQuote struct {
 	Timestamp   time.Time
 }
 
+// Quote failures price providers tell apart from network and node errors.
+var (
+	// ErrQuoteReverted means the quoter rejected the swap, as when the pool
+	// lacks the liquidity for the size.
+	ErrQuoteReverted  = errors.New("quote reverted")
+	ErrQuoteMalformed = errors.New("malformed quote result")
+)
+
 type ArbitrageOpportunity struct {
 	BuyOn     string
 	SellOn    string
@@ -83,12 +115,38 @@ type TradeData struct {
 	EstimatedProfit float64 `json:"estimatedProfit"`
 	GasCost         float64 `json:"gasCost"`
 	Symbol          string  `json:"symbol"`
+	Venue           string  `json:"venue,omitempty"`
 	Direction       string  `json:"direction"`
+	// Size is the trade size in token_in units.
+	Size float64 `json:"size,omitempty"`
+	// PoolFee is the fee tier of the pool quoted.
+	PoolFee int64 `json:"poolFee,omitempty"`
+	// BookSkewMs is the time between the CEX book and the block.
+	BookSkewMs int64 `json:"bookSkewMs"`
+	// StaleBook is set when BookSkewMs exceeded the allowed skew.
+	StaleBook bool `json:"staleBook,omitempty"`
 }
 
+// Event types broadcast by the Manager.
+const (
+	EventHeartbeat   = "HEARTBEAT"
+	EventOpportunity = "OPPORTUNITY"
+)
+
+// Trade directions reported in TradeData.
+const (
+	DirectionCEXToDEX = "CEX -> DEX"
+	DirectionDEXToCEX = "DEX -> CEX"
+)
+
 type ArbitrageEvent struct {
+	// Seq increases by one with every event broadcast. It is assigned by the
+	// notifier, not the sender.
+	Seq         uint64     `json:"seq,omitempty"`
 	Type        string     `json:"type"`
 	BlockNumber uint64     `json:"blockNumber"`
 	Timestamp   time.Time  `json:"timestamp"`
 	Data        *TradeData `json:"data,omitempty"`
+	// Opportunity is set on lifecycle events.
+	Opportunity *Opportunity `json:"opportunity,omitempty"`
 }
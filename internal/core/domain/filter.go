@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidFilter = errors.New("invalid event filter")
+
+// EventFilter selects the events a subscriber receives. Empty lists match
+// everything. Symbols, venues, directions and MinProfit only constrain events
+// that carry trade data; heartbeats are selected by type alone. A zero
+// MinProfit also passes unprofitable evaluations. MinProfit does not apply to
+// the changes and close of an opportunity: its profit decays before it
+// closes, and a subscriber that saw it open must see it close.
+type EventFilter struct {
+	Symbols    []string `json:"symbols,omitempty"`
+	Venues     []string `json:"venues,omitempty"`
+	Types      []string `json:"types,omitempty"`
+	Directions []string `json:"directions,omitempty"`
+	MinProfit  float64  `json:"minProfit,omitempty"`
+}
+
+// Normalize canonicalizes symbols, venue and type names so that Matches can
+// compare them directly, and rejects unknown types and directions.
+func (f *EventFilter) Normalize() error {
+	for i, s := range f.Symbols {
+		f.Symbols[i] = NormalizeSymbol(s)
+	}
+	for i, v := range f.Venues {
+		f.Venues[i] = strings.ToLower(v)
+	}
+	for i, t := range f.Types {
+		f.Types[i] = strings.ToUpper(t)
+		if f.Types[i] != EventHeartbeat && f.Types[i] != EventOpportunity && !IsLifecycleEvent(f.Types[i]) {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidFilter, t)
+		}
+	}
+	for i, d := range f.Directions {
+		f.Directions[i] = strings.ToUpper(strings.TrimSpace(d))
+		if f.Directions[i] != DirectionCEXToDEX && f.Directions[i] != DirectionDEXToCEX {
+			return fmt.Errorf("%w: unknown direction %q", ErrInvalidFilter, d)
+		}
+	}
+	if f.MinProfit < 0 {
+		return fmt.Errorf("%w: negative minimum profit %v", ErrInvalidFilter, f.MinProfit)
+	}
+	return nil
+}
+
+// Matches reports whether e passes the filter. f must be normalized.
+func (f *EventFilter) Matches(e ArbitrageEvent) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, e.Type) {
+		return false
+	}
+	d := e.Data
+	if d == nil {
+		return true
+	}
+	if len(f.Symbols) > 0 && !slices.Contains(f.Symbols, NormalizeSymbol(d.Symbol)) {
+		return false
+	}
+	if len(f.Venues) > 0 && !slices.Contains(f.Venues, strings.ToLower(d.Venue)) {
+		return false
+	}
+	if len(f.Directions) > 0 && !slices.Contains(f.Directions, d.Direction) {
+		return false
+	}
+	if e.Type == EventOpportunityUpdated || e.Type == EventOpportunityClosed {
+		return true
+	}
+	return f.MinProfit == 0 || d.EstimatedProfit >= f.MinProfit
+}
@@ -0,0 +1,72 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventFilter_Matches(t *testing.T) {
+	heartbeat := domain.ArbitrageEvent{Type: domain.EventHeartbeat}
+	opportunity := domain.ArbitrageEvent{Type: domain.EventOpportunity, Data: &domain.TradeData{
+		Symbol:          "ETHUSDC",
+		Venue:           "binance",
+		Direction:       domain.DirectionCEXToDEX,
+		EstimatedProfit: 42,
+	}}
+
+	tests := []struct {
+		name      string
+		filter    domain.EventFilter
+		heartbeat bool
+		trade     bool
+	}{
+		{"empty matches everything", domain.EventFilter{}, true, true},
+		{"type", domain.EventFilter{Types: []string{"opportunity"}}, false, true},
+		{"symbol ignores heartbeats", domain.EventFilter{Symbols: []string{"eth/usdc"}}, true, true},
+		{"other symbol", domain.EventFilter{Symbols: []string{"BTCUSDC"}}, true, false},
+		{"venue", domain.EventFilter{Venues: []string{"Binance"}}, true, true},
+		{"other venue", domain.EventFilter{Venues: []string{"kraken"}}, true, false},
+		{"direction", domain.EventFilter{Directions: []string{"dex -> cex"}}, true, false},
+		{"min profit met", domain.EventFilter{MinProfit: 42}, true, true},
+		{"min profit missed", domain.EventFilter{MinProfit: 42.5}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.filter.Normalize())
+			assert.Equal(t, tt.heartbeat, tt.filter.Matches(heartbeat))
+			assert.Equal(t, tt.trade, tt.filter.Matches(opportunity))
+		})
+	}
+}
+
+func TestEventFilter_MinProfitKeepsLifecycles(t *testing.T) {
+	f := domain.EventFilter{MinProfit: 50}
+	require.NoError(t, f.Normalize())
+	event := func(eventType string, profit float64) domain.ArbitrageEvent {
+		return domain.ArbitrageEvent{Type: eventType, Data: &domain.TradeData{Symbol: "ETHUSDC", EstimatedProfit: profit}}
+	}
+
+	assert.True(t, f.Matches(event(domain.EventOpportunityOpened, 60)))
+	assert.False(t, f.Matches(event(domain.EventOpportunityOpened, 40)))
+	assert.False(t, f.Matches(event(domain.EventOpportunity, 40)))
+	// Once opened, an opportunity's decay and close are not filtered out.
+	assert.True(t, f.Matches(event(domain.EventOpportunityUpdated, 30)))
+	assert.True(t, f.Matches(event(domain.EventOpportunityClosed, 10)))
+
+	f = domain.EventFilter{MinProfit: 50, Symbols: []string{"BTCUSDC"}}
+	require.NoError(t, f.Normalize())
+	assert.False(t, f.Matches(event(domain.EventOpportunityClosed, 10)), "other conditions still apply")
+}
+
+func TestEventFilter_NormalizeRejectsUnknown(t *testing.T) {
+	for _, f := range []domain.EventFilter{
+		{Types: []string{"TRADE"}},
+		{Directions: []string{"CEX->DEX"}},
+		{MinProfit: -1},
+	} {
+		assert.ErrorIs(t, f.Normalize(), domain.ErrInvalidFilter)
+	}
+}
@@ -0,0 +1,264 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxFixedScale is the largest number of decimal places a Fixed can carry.
+const MaxFixedScale = 18
+
+var (
+	ErrFixedOverflow  = errors.New("fixed-point overflow")
+	ErrFixedPrecision = errors.New("value has more decimal places than the fixed-point scale")
+)
+
+var pow10 = func() [MaxFixedScale + 1]int64 {
+	var p [MaxFixedScale + 1]int64
+	p[0] = 1
+	for i := 1; i <= MaxFixedScale; i++ {
+		p[i] = p[i-1] * 10
+	}
+	return p
+}()
+
+// Fixed is a decimal number stored as an int64 scaled by 10^-scale. Unlike
+// decimal.Decimal it is a plain value, so arithmetic does not allocate.
+// Operations never round or wrap: they report overflow instead, letting the
+// caller fall back to decimal.
+type Fixed struct {
+	value int64
+	scale uint8
+}
+
+func NewFixed(value int64, scale uint8) Fixed {
+	return Fixed{value: value, scale: scale}
+}
+
+// FixedFromDecimal converts d exactly to scale.
+func FixedFromDecimal(d decimal.Decimal, scale uint8) (Fixed, error) {
+	if scale > MaxFixedScale {
+		return Fixed{}, fmt.Errorf("%w: scale %d", ErrFixedPrecision, scale)
+	}
+	if d.NumDigits() > MaxFixedScale {
+		// Amounts from Token.ToHuman keep a zero for every unused decimal
+		// place of the token; only the significant digits need to fit.
+		d = trimZeros(d)
+		if d.NumDigits() > MaxFixedScale {
+			return Fixed{}, fmt.Errorf("%w: %s", ErrFixedOverflow, d)
+		}
+	}
+	coef, exp := d.CoefficientInt64(), d.Exponent()
+
+	shift := int(scale) + int(exp)
+	switch {
+	case shift > MaxFixedScale:
+		return Fixed{}, fmt.Errorf("%w: %s", ErrFixedOverflow, d)
+	case shift >= 0:
+		v, ok := mul64(coef, pow10[shift])
+		if !ok {
+			return Fixed{}, fmt.Errorf("%w: %s", ErrFixedOverflow, d)
+		}
+		return Fixed{value: v, scale: scale}, nil
+	case -shift > MaxFixedScale || coef%pow10[-shift] != 0:
+		return Fixed{}, fmt.Errorf("%w: %s at scale %d", ErrFixedPrecision, d, scale)
+	default:
+		return Fixed{value: coef / pow10[-shift], scale: scale}, nil
+	}
+}
+
+// trimZeros returns d with the trailing zeros of its coefficient removed.
+func trimZeros(d decimal.Decimal) decimal.Decimal {
+	coef, exp := d.Coefficient(), d.Exponent()
+	if coef.Sign() == 0 {
+		return decimal.Zero
+	}
+	var q, r big.Int
+	ten := big.NewInt(10)
+	for {
+		q.QuoRem(coef, ten, &r)
+		if r.Sign() != 0 {
+			return decimal.NewFromBigInt(coef, exp)
+		}
+		coef.Set(&q)
+		exp++
+	}
+}
+
+func (f Fixed) Value() int64 { return f.value }
+func (f Fixed) Scale() uint8 { return f.scale }
+func (f Fixed) Sign() int {
+	switch {
+	case f.value > 0:
+		return 1
+	case f.value < 0:
+		return -1
+	}
+	return 0
+}
+func (f Fixed) IsPositive() bool { return f.value > 0 }
+func (f Fixed) IsZero() bool     { return f.value == 0 }
+
+// Decimal converts f to a decimal.Decimal.
+func (f Fixed) Decimal() decimal.Decimal {
+	return decimal.New(f.value, -int32(f.scale))
+}
+
+func (f Fixed) String() string {
+	return f.Decimal().String()
+}
+
+// Rescale returns f with scale decimal places. Reducing the scale only
+// succeeds if the dropped digits are zero.
+func (f Fixed) Rescale(scale uint8) (Fixed, bool) {
+	switch {
+	case scale == f.scale:
+		return f, true
+	case scale > MaxFixedScale:
+		return Fixed{}, false
+	case scale > f.scale:
+		v, ok := mul64(f.value, pow10[scale-f.scale])
+		return Fixed{value: v, scale: scale}, ok
+	default:
+		p := pow10[f.scale-scale]
+		if f.value%p != 0 {
+			return Fixed{}, false
+		}
+		return Fixed{value: f.value / p, scale: scale}, true
+	}
+}
+
+// Add returns f+g at the larger of the two scales.
+func (f Fixed) Add(g Fixed) (Fixed, bool) {
+	f, g, ok := align(f, g)
+	if !ok {
+		return Fixed{}, false
+	}
+	v, ok := add64(f.value, g.value)
+	return Fixed{value: v, scale: f.scale}, ok
+}
+
+// Sub returns f-g at the larger of the two scales.
+func (f Fixed) Sub(g Fixed) (Fixed, bool) {
+	if g.value == math.MinInt64 {
+		return Fixed{}, false
+	}
+	return f.Add(Fixed{value: -g.value, scale: g.scale})
+}
+
+// Mul returns f*g exactly, at the sum of the two scales.
+func (f Fixed) Mul(g Fixed) (Fixed, bool) {
+	scale := f.scale + g.scale
+	if scale > MaxFixedScale {
+		return Fixed{}, false
+	}
+	v, ok := mul64(f.value, g.value)
+	return Fixed{value: v, scale: scale}, ok
+}
+
+// Cmp returns -1, 0 or 1 as f is less than, equal to or greater than g.
+func (f Fixed) Cmp(g Fixed) int {
+	if f.scale == g.scale {
+		return cmp64(f.value, g.value)
+	}
+	if fs, gs := f.Sign(), g.Sign(); fs != gs {
+		return cmp64(int64(fs), int64(gs))
+	}
+	// Same sign: compare magnitudes at a common scale in 128 bits.
+	fhi, flo := bits.Mul64(abs64(f.value), uint64(pow10[maxScale(f, g)-f.scale]))
+	ghi, glo := bits.Mul64(abs64(g.value), uint64(pow10[maxScale(f, g)-g.scale]))
+	c := 0
+	switch {
+	case fhi != ghi:
+		c = cmpU64(fhi, ghi)
+	default:
+		c = cmpU64(flo, glo)
+	}
+	if f.value < 0 {
+		return -c
+	}
+	return c
+}
+
+func align(f, g Fixed) (Fixed, Fixed, bool) {
+	s := maxScale(f, g)
+	f, okf := f.Rescale(s)
+	g, okg := g.Rescale(s)
+	return f, g, okf && okg
+}
+
+func maxScale(f, g Fixed) uint8 {
+	if f.scale > g.scale {
+		return f.scale
+	}
+	return g.scale
+}
+
+func add64(a, b int64) (int64, bool) {
+	s := a + b
+	if (a > 0 && b > 0 && s < 0) || (a < 0 && b < 0 && s >= 0) {
+		return 0, false
+	}
+	return s, true
+}
+
+func mul64(a, b int64) (int64, bool) {
+	hi, lo := bits.Mul64(abs64(a), abs64(b))
+	if hi != 0 || lo > math.MaxInt64 {
+		return 0, false
+	}
+	if (a < 0) != (b < 0) {
+		return -int64(lo), true
+	}
+	return int64(lo), true
+}
+
+func abs64(v int64) uint64 {
+	if v < 0 {
+		return uint64(-v)
+	}
+	return uint64(v)
+}
+
+func cmp64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func cmpU64(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// scaleOf returns the number of significant decimal places in d, so a tick
+// of "0.01000000" has scale 2.
+func scaleOf(d decimal.Decimal) uint8 {
+	exp := d.Exponent()
+	if exp >= 0 {
+		return 0
+	}
+	if d.NumDigits() <= MaxFixedScale {
+		for coef := d.CoefficientInt64(); exp < 0 && coef%10 == 0; coef /= 10 {
+			exp++
+		}
+	}
+	if -exp > MaxFixedScale {
+		return MaxFixedScale
+	}
+	return uint8(-exp)
+}
@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// FixedDepth is Depth in fixed point: prices at priceScale, quantities at
+// qtyScale and notionals at their sum. Lookups with a Fixed amount do not
+// allocate; results are exact, so they equal Depth's.
+type FixedDepth struct {
+	prices []int64
+	// cumQty[i] and cumNotional[i] sum levels[0..i].
+	cumQty      []int64
+	cumNotional []int64
+	ascending   bool
+
+	priceScale, qtyScale uint8
+}
+
+// FixedBookDepth holds the fixed-point depth of both sides of a snapshot.
+type FixedBookDepth struct {
+	Bids *FixedDepth
+	Asks *FixedDepth
+}
+
+// FixedDepth precomputes both sides of the book at the given precision, as
+// returned by Instrument.Scales. It fails if a level has more decimal places
+// than its scale or the sums overflow; use Depth then.
+func (ob *OrderBook) FixedDepth(priceScale, qtyScale uint8) (*FixedBookDepth, error) {
+	bids, err := NewFixedDepth(ob.Bids, false, priceScale, qtyScale)
+	if err != nil {
+		return nil, fmt.Errorf("bids: %w", err)
+	}
+	asks, err := NewFixedDepth(ob.Asks, true, priceScale, qtyScale)
+	if err != nil {
+		return nil, fmt.Errorf("asks: %w", err)
+	}
+	return &FixedBookDepth{Bids: bids, Asks: asks}, nil
+}
+
+// Side returns the asks for "buy" and the bids otherwise.
+func (b *FixedBookDepth) Side(side string) *FixedDepth {
+	if side == "buy" {
+		return b.Asks
+	}
+	return b.Bids
+}
+
+// EffectivePrice returns the same result as BookDepth.EffectivePrice for
+// amounts representable at the quantity scale.
+func (b *FixedBookDepth) EffectivePrice(side string, amount decimal.Decimal) (decimal.Decimal, bool, error) {
+	return b.Side(side).EffectivePrice(amount)
+}
+
+func NewFixedDepth(levels []PriceLevel, ascending bool, priceScale, qtyScale uint8) (*FixedDepth, error) {
+	if priceScale+qtyScale > MaxFixedScale {
+		return nil, fmt.Errorf("%w: price scale %d + quantity scale %d", ErrFixedPrecision, priceScale, qtyScale)
+	}
+	d := &FixedDepth{
+		prices:      make([]int64, len(levels)),
+		cumQty:      make([]int64, len(levels)),
+		cumNotional: make([]int64, len(levels)),
+		ascending:   ascending,
+		priceScale:  priceScale,
+		qtyScale:    qtyScale,
+	}
+	var qty, notional int64
+	for i, l := range levels {
+		price, err := FixedFromDecimal(l.Price, priceScale)
+		if err != nil {
+			return nil, fmt.Errorf("level %d price: %w", i, err)
+		}
+		amount, err := FixedFromDecimal(l.Amount, qtyScale)
+		if err != nil {
+			return nil, fmt.Errorf("level %d amount: %w", i, err)
+		}
+		cost, ok := mul64(price.value, amount.value)
+		if ok {
+			qty, ok = add64(qty, amount.value)
+		}
+		if ok {
+			notional, ok = add64(notional, cost)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: level %d", ErrFixedOverflow, i)
+		}
+		d.prices[i] = price.value
+		d.cumQty[i] = qty
+		d.cumNotional[i] = notional
+	}
+	return d, nil
+}
+
+// Total returns the quantity available on the side.
+func (d *FixedDepth) Total() Fixed {
+	if len(d.cumQty) == 0 {
+		return Fixed{scale: d.qtyScale}
+	}
+	return Fixed{value: d.cumQty[len(d.cumQty)-1], scale: d.qtyScale}
+}
+
+// EffectivePrice returns the volume-weighted price of filling amount, or
+// false if the side cannot fill it. Only the final division is done in
+// decimal, giving the same rounding as Depth. Amounts finer than the lot
+// size fail with ErrFixedPrecision; price them with Depth.
+func (d *FixedDepth) EffectivePrice(amount decimal.Decimal) (decimal.Decimal, bool, error) {
+	amt, err := FixedFromDecimal(amount, d.qtyScale)
+	if err != nil {
+		return decimal.Zero, false, err
+	}
+	notional, ok := d.Notional(amt)
+	if !ok {
+		return decimal.Zero, false, nil
+	}
+	return notional.Decimal().Div(amount), true, nil
+}
+
+// Notional returns the total cost of filling amount, at the sum of the price
+// and quantity scales, or false if the side cannot fill it.
+func (d *FixedDepth) Notional(amount Fixed) (Fixed, bool) {
+	amt, i, ok := d.fillLevel(amount)
+	if !ok {
+		return Fixed{}, false
+	}
+	scale := d.priceScale + d.qtyScale
+	// Both terms are bounded by cumNotional[i], so neither can overflow.
+	if i == 0 {
+		return Fixed{value: amt * d.prices[0], scale: scale}, true
+	}
+	remaining := amt - d.cumQty[i-1]
+	return Fixed{value: d.cumNotional[i-1] + remaining*d.prices[i], scale: scale}, true
+}
+
+// MarginalPrice returns the price of the level the last unit of amount is
+// filled at, or false if the side cannot fill it.
+func (d *FixedDepth) MarginalPrice(amount Fixed) (Fixed, bool) {
+	_, i, ok := d.fillLevel(amount)
+	if !ok {
+		return Fixed{}, false
+	}
+	return Fixed{value: d.prices[i], scale: d.priceScale}, true
+}
+
+// MaxFillable returns the quantity available at limit or better: at or
+// below limit for asks, at or above it for bids.
+func (d *FixedDepth) MaxFillable(limit Fixed) Fixed {
+	n := sort.Search(len(d.prices), func(i int) bool {
+		c := Fixed{value: d.prices[i], scale: d.priceScale}.Cmp(limit)
+		if d.ascending {
+			return c > 0
+		}
+		return c < 0
+	})
+	if n == 0 {
+		return Fixed{scale: d.qtyScale}
+	}
+	return Fixed{value: d.cumQty[n-1], scale: d.qtyScale}
+}
+
+// fillLevel rescales amount to the quantity scale and returns it with the
+// index of the first level whose cumulative quantity covers it.
+func (d *FixedDepth) fillLevel(amount Fixed) (int64, int, bool) {
+	amt, ok := amount.Rescale(d.qtyScale)
+	if !ok || !amt.IsPositive() {
+		return 0, 0, false
+	}
+	i := sort.Search(len(d.cumQty), func(i int) bool { return d.cumQty[i] >= amt.value })
+	return amt.value, i, i < len(d.cumQty)
+}
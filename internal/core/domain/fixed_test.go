@@ -0,0 +1,193 @@
+package domain_test
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixed_Arithmetic(t *testing.T) {
+	a, err := domain.FixedFromDecimal(decimal.RequireFromString("2000.25"), 2)
+	require.NoError(t, err)
+	b := domain.NewFixed(15, 1) // 1.5
+
+	sum, ok := a.Add(b)
+	require.True(t, ok)
+	assert.Equal(t, "2001.75", sum.String())
+
+	diff, ok := b.Sub(a)
+	require.True(t, ok)
+	assert.Equal(t, "-1998.75", diff.String())
+
+	prod, ok := a.Mul(b)
+	require.True(t, ok)
+	assert.Equal(t, uint8(3), prod.Scale())
+	assert.True(t, prod.Decimal().Equal(decimal.RequireFromString("3000.375")))
+
+	assert.Equal(t, 1, a.Cmp(b))
+	assert.Equal(t, 0, domain.NewFixed(150, 2).Cmp(b))
+	assert.Equal(t, -1, domain.NewFixed(-2, 0).Cmp(domain.NewFixed(-15, 1)))
+
+	_, ok = domain.NewFixed(1<<62, 0).Mul(domain.NewFixed(4, 0))
+	assert.False(t, ok, "overflow must be reported")
+	_, ok = domain.NewFixed(12345, 3).Rescale(2)
+	assert.False(t, ok, "rescaling must not drop digits")
+
+	_, err = domain.FixedFromDecimal(decimal.RequireFromString("0.001"), 2)
+	assert.ErrorIs(t, err, domain.ErrFixedPrecision)
+
+	tenEth, _ := new(big.Int).SetString("10000000000000000000", 10)
+	f, err := domain.FixedFromDecimal(domain.Ether.ToHuman(tenEth), 4)
+	require.NoError(t, err, "trailing zeros do not count towards the digits")
+	assert.Equal(t, domain.NewFixed(100000, 4), f)
+	_, err = domain.FixedFromDecimal(decimal.RequireFromString("1234567890.1234567891"), 4)
+	assert.ErrorIs(t, err, domain.ErrFixedOverflow)
+}
+
+func TestInstrument_Scales(t *testing.T) {
+	inst := &domain.Instrument{
+		TickSize: decimal.RequireFromString("0.01000000"),
+		LotSize:  decimal.RequireFromString("0.00010000"),
+	}
+	price, qty := inst.Scales()
+	assert.Equal(t, uint8(2), price)
+	assert.Equal(t, uint8(4), qty)
+}
+
+func TestFixedBookDepth_MatchesDecimal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		ob := randomBook(r, 50)
+		depth := ob.Depth()
+		fixed, err := ob.FixedDepth(2, 4)
+		require.NoError(t, err)
+
+		for _, side := range []string{"buy", "sell"} {
+			for i := 0; i < 50; i++ {
+				amount := decimal.New(r.Int63n(3000000)+1, -4)
+
+				want, wantOK := depth.EffectivePrice(side, amount)
+				got, ok, err := fixed.EffectivePrice(side, amount)
+				require.NoError(t, err)
+				require.Equal(t, wantOK, ok, "%s %s", side, amount)
+				assert.Equal(t, want.String(), got.String(), "%s %s", side, amount)
+
+				amt := domain.NewFixed(amount.CoefficientInt64(), 4)
+				wantNotional, _ := depth.Side(side).Notional(amount)
+				gotNotional, _ := fixed.Side(side).Notional(amt)
+				assert.True(t, wantNotional.Equal(gotNotional.Decimal()), "%s %s", side, amount)
+			}
+		}
+	}
+}
+
+func TestFixedDepth_Lookups(t *testing.T) {
+	asks, err := domain.NewFixedDepth([]domain.PriceLevel{level("100", "1"), level("101", "2"), level("103", "3")}, true, 2, 4)
+	require.NoError(t, err)
+
+	price, ok, err := asks.EffectivePrice(decimal.NewFromInt(3))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "100.6666666666666667", price.String())
+
+	// Token amounts carry the token's 18 decimal places.
+	price, ok, err = asks.EffectivePrice(domain.Ether.ToHuman(big.NewInt(3e18)))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "100.6666666666666667", price.String())
+
+	_, _, err = asks.EffectivePrice(decimal.RequireFromString("0.00001"))
+	assert.ErrorIs(t, err, domain.ErrFixedPrecision, "amounts finer than the lot size")
+
+	marginal, ok := asks.MarginalPrice(domain.NewFixed(35, 1))
+	require.True(t, ok)
+	assert.Equal(t, "103", marginal.String())
+
+	assert.Equal(t, "3", asks.MaxFillable(domain.NewFixed(102, 0)).String())
+	assert.Equal(t, "6", asks.Total().String())
+
+	_, ok = asks.Notional(domain.NewFixed(7, 0))
+	assert.False(t, ok)
+
+	ob := &domain.OrderBook{Asks: []domain.PriceLevel{level("100.001", "1")}}
+	_, err = ob.FixedDepth(2, 4)
+	assert.ErrorIs(t, err, domain.ErrFixedPrecision)
+}
+
+func BenchmarkFixedEffectivePrice(b *testing.B) {
+	ob := randomBook(rand.New(rand.NewSource(1)), 1000)
+	amounts := make([]decimal.Decimal, 64)
+	fixedAmounts := make([]domain.Fixed, len(amounts))
+	// Trade sizes as the Manager sees them: wei converted by Token.ToHuman.
+	tokenAmounts := make([]decimal.Decimal, len(amounts))
+	for i := range amounts {
+		amounts[i] = decimal.NewFromInt(int64(i+1) * 50)
+		fixedAmounts[i] = domain.NewFixed(int64(i+1)*50, 0)
+		wei := new(big.Int).Mul(big.NewInt(int64(i+1)*5), big.NewInt(1e18))
+		tokenAmounts[i] = domain.Ether.ToHuman(wei)
+	}
+
+	b.Run("decimal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			depth := ob.Depth()
+			for _, amt := range amounts {
+				depth.EffectivePrice("buy", amt)
+			}
+		}
+	})
+	b.Run("fixed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			depth, _ := ob.FixedDepth(2, 4)
+			for _, amt := range amounts {
+				depth.EffectivePrice("buy", amt)
+			}
+		}
+	})
+	b.Run("decimal_token_amounts", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			depth := ob.Depth()
+			for _, amt := range tokenAmounts {
+				depth.EffectivePrice("buy", amt)
+			}
+		}
+	})
+	b.Run("fixed_token_amounts", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			depth, _ := ob.FixedDepth(2, 4)
+			for _, amt := range tokenAmounts {
+				if _, _, err := depth.EffectivePrice("buy", amt); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("decimal_notional_prebuilt", func(b *testing.B) {
+		depth := ob.Depth()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, amt := range amounts {
+				depth.Asks.Notional(amt)
+			}
+		}
+	})
+	b.Run("fixed_notional_prebuilt", func(b *testing.B) {
+		depth, _ := ob.FixedDepth(2, 4)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, amt := range fixedAmounts {
+				depth.Asks.Notional(amt)
+			}
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInstrumentNotListed = errors.New("instrument not listed")
+	ErrBelowMinQty         = errors.New("quantity below venue minimum")
+	ErrBelowMinNotional    = errors.New("notional below venue minimum")
+)
+
+// Instrument describes a tradable pair on a venue. Symbol is the canonical
+// BASE+QUOTE form used throughout the bot (e.g. ETHUSDC) and VenueSymbol the
+// identifier the venue expects (e.g. ETH-USDC). Zero-valued limits are treated
+// as unconstrained.
+type Instrument struct {
+	Venue       string
+	Symbol      string
+	VenueSymbol string
+	Base        string
+	Quote       string
+	// QuoteAlias is set when Symbol names another quote asset than the
+	// venue trades in Quote, such as a USDC symbol served from a USD book.
+	QuoteAlias  string
+	TickSize    decimal.Decimal
+	LotSize     decimal.Decimal
+	MinQty      decimal.Decimal
+	MinNotional decimal.Decimal
+}
+
+// CanonicalSymbol builds the canonical symbol for a base/quote asset pair.
+func CanonicalSymbol(base, quote string) string {
+	return strings.ToUpper(base + quote)
+}
+
+// NormalizeSymbol strips separators from a symbol such as ETH-USDC or
+// eth/usdc so it can be compared with canonical symbols.
+func NormalizeSymbol(symbol string) string {
+	s := strings.ToUpper(symbol)
+	s = strings.ReplaceAll(s, "-", "")
+	s = strings.ReplaceAll(s, "/", "")
+	s = strings.ReplaceAll(s, "_", "")
+	return s
+}
+
+// RoundQty rounds qty down to a multiple of the lot size.
+func (i *Instrument) RoundQty(qty decimal.Decimal) decimal.Decimal {
+	return roundToStep(qty, i.LotSize, false)
+}
+
+// RoundPrice rounds price to a multiple of the tick size, away from the
+// market: up for buys and down for sells.
+func (i *Instrument) RoundPrice(side string, price decimal.Decimal) decimal.Decimal {
+	return roundToStep(price, i.TickSize, side == "buy")
+}
+
+// CheckOrder validates qty at price against the venue minimums.
+func (i *Instrument) CheckOrder(qty, price decimal.Decimal) error {
+	if i.MinQty.IsPositive() && qty.LessThan(i.MinQty) {
+		return fmt.Errorf("%w: %s < %s", ErrBelowMinQty, qty, i.MinQty)
+	}
+	if notional := qty.Mul(price); i.MinNotional.IsPositive() && notional.LessThan(i.MinNotional) {
+		return fmt.Errorf("%w: %s < %s", ErrBelowMinNotional, notional, i.MinNotional)
+	}
+	return nil
+}
+
+func roundToStep(v, step decimal.Decimal, up bool) decimal.Decimal {
+	if !step.IsPositive() {
+		return v
+	}
+	steps := v.Div(step)
+	if up {
+		steps = steps.Ceil()
+	} else {
+		steps = steps.Floor()
+	}
+	return steps.Mul(step)
+}
+
+// Scales returns the decimal places of the tick and lot sizes: the precision
+// prices and quantities are held at in fixed point.
+func (i *Instrument) Scales() (price, qty uint8) {
+	return scaleOf(i.TickSize), scaleOf(i.LotSize)
+}
@@ -0,0 +1,31 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrument_Rounding(t *testing.T) {
+	inst := &domain.Instrument{
+		TickSize: decimal.RequireFromString("0.01"),
+		LotSize:  decimal.RequireFromString("0.0001"),
+	}
+
+	assert.Equal(t, "1.2345", inst.RoundQty(decimal.RequireFromString("1.23456789")).String())
+	assert.Equal(t, "2000.13", inst.RoundPrice("buy", decimal.RequireFromString("2000.123")).String())
+	assert.Equal(t, "2000.12", inst.RoundPrice("sell", decimal.RequireFromString("2000.123")).String())
+}
+
+func TestInstrument_CheckOrder(t *testing.T) {
+	inst := &domain.Instrument{
+		MinQty:      decimal.RequireFromString("0.001"),
+		MinNotional: decimal.RequireFromString("5"),
+	}
+
+	assert.NoError(t, inst.CheckOrder(decimal.RequireFromString("0.01"), decimal.RequireFromString("2000")))
+	assert.ErrorIs(t, inst.CheckOrder(decimal.RequireFromString("0.0005"), decimal.RequireFromString("2000")), domain.ErrBelowMinQty)
+	assert.ErrorIs(t, inst.CheckOrder(decimal.RequireFromString("0.002"), decimal.RequireFromString("2000")), domain.ErrBelowMinNotional)
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Lifecycle events broadcast as an opportunity opens, changes materially and
+// closes. Unlike EventOpportunity, which reports the best trade of every
+// block, each is sent once per transition.
+const (
+	EventOpportunityOpened  = "OPPORTUNITY_OPENED"
+	EventOpportunityUpdated = "OPPORTUNITY_UPDATED"
+	EventOpportunityClosed  = "OPPORTUNITY_CLOSED"
+)
+
+// IsLifecycleEvent reports whether eventType is an opportunity lifecycle
+// event.
+func IsLifecycleEvent(eventType string) bool {
+	switch eventType {
+	case EventOpportunityOpened, EventOpportunityUpdated, EventOpportunityClosed:
+		return true
+	}
+	return false
+}
+
+// OpportunityKey identifies an opportunity across blocks.
+type OpportunityKey struct {
+	Symbol    string
+	Venue     string
+	Direction string
+	// PoolFee is the fee tier of the pool quoted, which with the pair
+	// identifies it.
+	PoolFee int64
+	// SizeBucket is the trade size to three significant digits, so that lot
+	// rounding does not split an opportunity.
+	SizeBucket string
+}
+
+// KeyOf returns the identity of the opportunity d describes.
+func KeyOf(d *TradeData) OpportunityKey {
+	return OpportunityKey{
+		Symbol:     d.Symbol,
+		Venue:      d.Venue,
+		Direction:  d.Direction,
+		PoolFee:    d.PoolFee,
+		SizeBucket: strconv.FormatFloat(d.Size, 'g', 3, 64),
+	}
+}
+
+func (k OpportunityKey) String() string {
+	return fmt.Sprintf("%s/%s/%s/%d/%s", k.Symbol, k.Venue, k.Direction, k.PoolFee, k.SizeBucket)
+}
+
+// Causes of an opportunity closing, from the price that moved against it
+// most between the last block it was seen in and the block that closed it.
+const (
+	// ClosedByDEX means the pool price moved: on-chain activity closed it.
+	ClosedByDEX = "dex"
+	// ClosedByCEX means the exchange book moved.
+	ClosedByCEX = "cex"
+	// ClosedByGas means gas got more expensive.
+	ClosedByGas = "gas"
+	// ClosedByUnknown means the trade could not be priced in the closing
+	// block, or no price moved against it, as when the minimum profit rose.
+	ClosedByUnknown = "unknown"
+)
+
+// Opportunity describes an opportunity in a lifecycle event. Its trade is the
+// event's Data: the latest one seen, or the last one before it closed.
+type Opportunity struct {
+	// ID is unique to each time the opportunity opens.
+	ID          string    `json:"id"`
+	Key         string    `json:"key"`
+	OpenedBlock uint64    `json:"openedBlock"`
+	OpenedAt    time.Time `json:"openedAt"`
+	// LastBlock is the latest block the opportunity was seen in.
+	LastBlock uint64 `json:"lastBlock"`
+
+	// Blocks is how many blocks the opportunity has lasted, from OpenedBlock
+	// to LastBlock.
+	Blocks     uint64  `json:"blocks"`
+	PeakProfit float64 `json:"peakProfit"`
+	PeakBlock  uint64  `json:"peakBlock"`
+	// AvgProfit is the mean profit over the blocks it was evaluated in.
+	AvgProfit float64 `json:"avgProfit"`
+
+	// The rest is set once the opportunity closed.
+	ClosedBlock uint64    `json:"closedBlock,omitempty"`
+	ClosedAt    time.Time `json:"closedAt,omitzero"`
+	// DecayPerBlock is the profit lost per block from the peak to the
+	// evaluation that closed it.
+	DecayPerBlock float64 `json:"decayPerBlock,omitempty"`
+	ClosedBy      string  `json:"closedBy,omitempty"`
+}
+
+// Duration is how long the opportunity lasted. It is zero until it closed.
+func (o *Opportunity) Duration() time.Duration {
+	if o.ClosedAt.IsZero() {
+		return 0
+	}
+	return o.ClosedAt.Sub(o.OpenedAt)
+}
+
+// OpportunitySummary aggregates the opportunities closed on one pair and
+// venue.
+type OpportunitySummary struct {
+	Symbol string
+	Venue  string
+	Closed int
+
+	AvgBlocks   float64
+	MaxBlocks   uint64
+	AvgDuration time.Duration
+
+	AvgPeakProfit    float64
+	MaxPeakProfit    float64
+	AvgProfit        float64
+	AvgDecayPerBlock float64
+
+	// ClosedBy counts closes by cause.
+	ClosedBy map[string]int
+}
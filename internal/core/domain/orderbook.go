@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrMalformedBook  = errors.New("malformed order book")
+	ErrBookLevel      = errors.New("order book level must have positive price and amount")
+	ErrBookUnsorted   = errors.New("order book levels out of order")
+	ErrBookCrossed    = errors.New("order book is crossed")
+	ErrBookTooShallow = errors.New("order book has too few levels")
+)
+
+// Validate checks that every level has a positive price and amount, that
+// bids are strictly descending and asks strictly ascending, that the best bid
+// is below the best ask, and that each side has at least minLevels levels.
+func (ob *OrderBook) Validate(minLevels int) error {
+	if err := validateSide("bid", ob.Bids, func(prev, cur PriceLevel) bool { return cur.Price.LessThan(prev.Price) }); err != nil {
+		return err
+	}
+	if err := validateSide("ask", ob.Asks, func(prev, cur PriceLevel) bool { return cur.Price.GreaterThan(prev.Price) }); err != nil {
+		return err
+	}
+
+	if len(ob.Bids) > 0 && len(ob.Asks) > 0 && !ob.Bids[0].Price.LessThan(ob.Asks[0].Price) {
+		return fmt.Errorf("%w: best bid %s >= best ask %s", ErrBookCrossed, ob.Bids[0].Price, ob.Asks[0].Price)
+	}
+
+	if len(ob.Bids) < minLevels || len(ob.Asks) < minLevels {
+		return fmt.Errorf("%w: %d bids, %d asks, need %d", ErrBookTooShallow, len(ob.Bids), len(ob.Asks), minLevels)
+	}
+	return nil
+}
+
+func validateSide(side string, levels []PriceLevel, ordered func(prev, cur PriceLevel) bool) error {
+	for i, l := range levels {
+		if !l.Price.IsPositive() || !l.Amount.IsPositive() {
+			return fmt.Errorf("%w: %s %d is %s @ %s", ErrBookLevel, side, i, l.Amount, l.Price)
+		}
+		if i > 0 && !ordered(levels[i-1], l) {
+			return fmt.Errorf("%w: %s %d at %s follows %s", ErrBookUnsorted, side, i, l.Price, levels[i-1].Price)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func level(price, amount string) domain.PriceLevel {
+	return domain.PriceLevel{Price: decimal.RequireFromString(price), Amount: decimal.RequireFromString(amount)}
+}
+
+func TestOrderBook_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		bids []domain.PriceLevel
+		asks []domain.PriceLevel
+		want error
+	}{
+		{
+			name: "valid",
+			bids: []domain.PriceLevel{level("2000", "1"), level("1999", "2")},
+			asks: []domain.PriceLevel{level("2001", "1"), level("2002", "2")},
+		},
+		{
+			name: "zero amount",
+			bids: []domain.PriceLevel{level("2000", "0"), level("1999", "2")},
+			asks: []domain.PriceLevel{level("2001", "1"), level("2002", "2")},
+			want: domain.ErrBookLevel,
+		},
+		{
+			name: "unsorted bids",
+			bids: []domain.PriceLevel{level("1999", "1"), level("2000", "2")},
+			asks: []domain.PriceLevel{level("2001", "1"), level("2002", "2")},
+			want: domain.ErrBookUnsorted,
+		},
+		{
+			name: "duplicate asks",
+			bids: []domain.PriceLevel{level("2000", "1"), level("1999", "2")},
+			asks: []domain.PriceLevel{level("2001", "1"), level("2001", "2")},
+			want: domain.ErrBookUnsorted,
+		},
+		{
+			name: "crossed",
+			bids: []domain.PriceLevel{level("2001", "1"), level("1999", "2")},
+			asks: []domain.PriceLevel{level("2001", "1"), level("2002", "2")},
+			want: domain.ErrBookCrossed,
+		},
+		{
+			name: "too shallow",
+			bids: []domain.PriceLevel{level("2000", "1"), level("1999", "2")},
+			asks: []domain.PriceLevel{level("2001", "1")},
+			want: domain.ErrBookTooShallow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ob := &domain.OrderBook{Bids: tt.bids, Asks: tt.asks}
+			err := ob.Validate(2)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
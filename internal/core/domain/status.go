@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EngineStatus describes what the engine watches and how far it has got.
+type EngineStatus struct {
+	Venue   string
+	Symbols []string
+	// StartedAt is zero until the engine starts.
+	StartedAt time.Time
+	// LastBlock is the latest block seen, zero before the first.
+	LastBlock     uint64
+	LastBlockAt   time.Time
+	ActiveWorkers int
+	MaxWorkers    int
+	MinProfit     decimal.Decimal
+}
+
+// Evaluation is the outcome of the latest block evaluated for a pair.
+type Evaluation struct {
+	Symbol      string
+	Venue       string
+	BlockNumber uint64
+	Timestamp   time.Time
+	// Best is the most profitable trade, nil when none cleared the minimum
+	// profit.
+	Best *TradeData
+	// Err is why the block could not be evaluated, empty on success.
+	Err string
+}
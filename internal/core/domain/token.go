@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrTokenMismatch = errors.New("token metadata does not match configuration")
+
+// Token describes an ERC-20 token. It is the single place where raw on-chain
+// amounts are converted to and from human-readable units.
+type Token struct {
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Decimals int32  `json:"decimals"`
+}
+
+// Ether is the chain's native asset, used to price gas.
+var Ether = Token{Symbol: "ETH", Name: "Ether", Decimals: 18}
+
+// ToHuman converts a raw amount in the token's smallest unit to token units.
+func (t Token) ToHuman(raw *big.Int) decimal.Decimal {
+	return decimal.NewFromBigInt(raw, -t.Decimals)
+}
+
+// ToRaw converts an amount in token units to the token's smallest unit,
+// truncating any precision the token cannot represent.
+func (t Token) ToRaw(amount decimal.Decimal) *big.Int {
+	return amount.Shift(t.Decimals).BigInt()
+}
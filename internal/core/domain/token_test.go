@@ -0,0 +1,22 @@
+package domain_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToken_Conversion(t *testing.T) {
+	usdc := domain.Token{Symbol: "USDC", Decimals: 6}
+
+	assert.Equal(t, "2050.5", usdc.ToHuman(big.NewInt(2050500000)).String())
+	assert.Equal(t, big.NewInt(1500000), usdc.ToRaw(decimal.RequireFromString("1.5")))
+	// Precision below one raw unit is truncated.
+	assert.Equal(t, big.NewInt(1), usdc.ToRaw(decimal.RequireFromString("0.0000019")))
+
+	gwei30, _ := new(big.Int).SetString("30000000000", 10)
+	assert.Equal(t, "0.00000003", domain.Ether.ToHuman(gwei30).String())
+}
@@ -21,6 +21,39 @@ func (m *MockExchangeAdapter) GetOrderBook(ctx context.Context, symbol string) (
 	return args.Get(0).(*domain.OrderBook), args.Error(1)
 }
 
+// MockInstrumentExchange is a mock exchange adapter that also implements
+// ports.InstrumentProvider
+type MockInstrumentExchange struct {
+	MockExchangeAdapter
+}
+
+func (m *MockInstrumentExchange) GetInstrument(ctx context.Context, symbol string) (*domain.Instrument, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Instrument), args.Error(1)
+}
+
+// MockDepthExchange is a mock exchange adapter that also implements
+// ports.DepthAdjuster
+type MockDepthExchange struct {
+	MockExchangeAdapter
+}
+
+func (m *MockDepthExchange) SetBookDepth(levels int) {
+	m.Called(levels)
+}
+
+func (m *MockDepthExchange) DeepenBook() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func (m *MockDepthExchange) BookFilled() {
+	m.Called()
+}
+
 // MockPriceProvider is a mock implementation of ports.PriceProvider
 type MockPriceProvider struct {
 	testifyMock.Mock
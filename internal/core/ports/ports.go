@@ -13,6 +13,34 @@ type ExchangeAdapter interface {
 	GetOrderBook(ctx context.Context, symbol string) (*domain.OrderBook, error)
 }
 
+// InstrumentProvider is implemented by exchange adapters that can describe
+// the instruments they trade.
+type InstrumentProvider interface {
+	// GetInstrument returns the venue metadata for a canonical symbol, or an
+	// error wrapping domain.ErrInstrumentNotListed.
+	GetInstrument(ctx context.Context, symbol string) (*domain.Instrument, error)
+}
+
+// ClockSynchronizer is implemented by exchange adapters that correct venue
+// timestamps for the offset between the venue's clock and the local clock.
+type ClockSynchronizer interface {
+	// SyncClock re-estimates the offset from the venue's server time.
+	SyncClock(ctx context.Context) error
+}
+
+// DepthAdjuster is implemented by exchange adapters that can change how many
+// order book levels they fetch.
+type DepthAdjuster interface {
+	// SetBookDepth sets the configured number of levels per side.
+	SetBookDepth(levels int)
+	// DeepenBook requests more levels on the next fetch, up to the venue
+	// maximum. It reports false if the maximum is already in use.
+	DeepenBook() bool
+	// BookFilled reports that the last book filled every trade size, so a
+	// deepened depth can relax back toward the configured one.
+	BookFilled()
+}
+
 // PriceProvider defines the interface for interacting with a DEX.
 type PriceProvider interface {
 	// GetQuote fetches the estimated output amount for a given input amount.
@@ -30,6 +58,13 @@ type PriceProvider interface {
 	GetSlot0(ctx context.Context, tokenIn, tokenOut string, fee int64) (*domain.Slot0, error)
 }
 
+// TokenProvider is implemented by price providers that can resolve ERC-20
+// token metadata.
+type TokenProvider interface {
+	// GetToken returns the decimals, symbol and name of the token at address.
+	GetToken(ctx context.Context, address string) (*domain.Token, error)
+}
+
 // BlockchainListener defines the interface for listening to blockchain events.
 type BlockchainListener interface {
 	// SubscribeNewHeads subscribes to new block headers.
@@ -41,3 +76,20 @@ type BlockchainListener interface {
 type NotificationService interface {
 	Broadcast(event domain.ArbitrageEvent)
 }
+
+// StatusProvider reports the state of the engine to API clients.
+type StatusProvider interface {
+	Status() domain.EngineStatus
+	// LatestEvaluations returns the most recent evaluation of each pair.
+	LatestEvaluations() []domain.Evaluation
+}
+
+// OpportunityStatsProvider reports statistics on closed opportunities.
+type OpportunityStatsProvider interface {
+	// OpportunitySummaries aggregates the opportunities closed per pair and
+	// venue.
+	OpportunitySummaries() []domain.OpportunitySummary
+	// RecentOpportunities returns up to limit of the latest
+	// OPPORTUNITY_CLOSED events, newest first.
+	RecentOpportunities(limit int) []domain.ArbitrageEvent
+}
@@ -2,10 +2,14 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math/big"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
@@ -15,17 +19,49 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+var (
+	one     = decimal.NewFromInt(1)
+	hundred = decimal.NewFromInt(100)
+)
+
 type Config struct {
 	TokenInAddr   string
 	TokenOutAddr  string
 	TokenInDec    int32
 	TokenOutDec   int32
 	Symbol        string
+	Venue         string
 	PoolFee       int64
 	TradeSizes    []*big.Int
 	MinProfit     decimal.Decimal
+	CEXFee        decimal.Decimal
 	MaxWorkers    int
 	CacheDuration time.Duration
+
+	// MinBookLevels is the fewest levels each side of the CEX book must have
+	// to be evaluated.
+	MinBookLevels int
+
+	// MaxBookSkew is the largest allowed gap between the CEX book time and
+	// the block timestamp; zero disables the check. Books further apart are
+	// skipped when RejectSkewedBooks is set and flagged otherwise.
+	MaxBookSkew       time.Duration
+	RejectSkewedBooks bool
+
+	// ClockSyncInterval is how often the CEX clock offset is re-estimated
+	// when the adapter supports it; zero disables syncing.
+	ClockSyncInterval time.Duration
+
+	// MaterialChange is the relative change in profit, since it was last
+	// reported, that reports an open opportunity again; zero reports every
+	// change.
+	MaterialChange float64
+}
+
+// Limits are the thresholds that can be changed on a running Manager.
+type Limits struct {
+	MinProfit  decimal.Decimal
+	TradeSizes []*big.Int
 }
 
 type Manager struct {
@@ -35,21 +71,63 @@ type Manager struct {
 	listener ports.BlockchainListener
 	notifier ports.NotificationService
 
-	mu        sync.RWMutex
-	lastBlock *big.Int
+	// instruments is set when the CEX adapter exposes venue metadata.
+	instruments ports.InstrumentProvider
+	// clock is set when the CEX adapter corrects for venue clock offset.
+	clock ports.ClockSynchronizer
+	// depth is set when the CEX adapter can fetch deeper books on demand.
+	depth ports.DepthAdjuster
+
+	tokenIn  domain.Token
+	tokenOut domain.Token
+
+	limits atomic.Pointer[Limits]
+
+	mu          sync.RWMutex
+	lastBlock   *big.Int
+	lastBlockAt time.Time
+	startedAt   time.Time
+	// evaluations holds the latest evaluation of each symbol.
+	evaluations map[string]domain.Evaluation
+
+	opportunities *opportunityTracker
 
 	sem chan struct{}
 }
 
 func NewManager(cfg Config, cex ports.ExchangeAdapter, dex ports.PriceProvider, listener ports.BlockchainListener, notifier ports.NotificationService) *Manager {
-	return &Manager{
+	m := &Manager{
 		cfg:      cfg,
 		cex:      cex,
 		dex:      dex,
 		listener: listener,
 		notifier: notifier,
 		sem:      make(chan struct{}, cfg.MaxWorkers),
+
+		evaluations:   make(map[string]domain.Evaluation),
+		opportunities: newOpportunityTracker(cfg.MaterialChange),
+
+		tokenIn:  domain.Token{Address: cfg.TokenInAddr, Decimals: cfg.TokenInDec},
+		tokenOut: domain.Token{Address: cfg.TokenOutAddr, Decimals: cfg.TokenOutDec},
 	}
+	if ip, ok := cex.(ports.InstrumentProvider); ok {
+		m.instruments = ip
+	}
+	if cs, ok := cex.(ports.ClockSynchronizer); ok {
+		m.clock = cs
+	}
+	if da, ok := cex.(ports.DepthAdjuster); ok {
+		m.depth = da
+	}
+	m.limits.Store(&Limits{MinProfit: cfg.MinProfit, TradeSizes: cfg.TradeSizes})
+	return m
+}
+
+// SetLimits replaces the profit threshold and trade sizes. Blocks already
+// being processed finish with the previous values.
+func (m *Manager) SetLimits(l Limits) {
+	m.limits.Store(&l)
+	slog.Info("Updated limits", "min_profit", l.MinProfit, "trade_sizes", l.TradeSizes)
 }
 
 func (m *Manager) Start(ctx context.Context) error {
@@ -58,6 +136,14 @@ func (m *Manager) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to start listener: %w", err)
 	}
 
+	if m.clock != nil && m.cfg.ClockSyncInterval > 0 {
+		go m.syncClock(ctx)
+	}
+
+	m.mu.Lock()
+	m.startedAt = time.Now()
+	m.mu.Unlock()
+
 	slog.Info("Bot started. Waiting for blocks...")
 
 	for {
@@ -79,6 +165,7 @@ func (m *Manager) Start(ctx context.Context) error {
 					m.processBlock(ctx, b)
 				}(block)
 			default:
+				observability.BlocksSkipped.WithLabelValues("workers_busy").Inc()
 				slog.Warn("Worker pool full, skipping block", "block", block.Number)
 			}
 		}
@@ -88,6 +175,7 @@ func (m *Manager) Start(ctx context.Context) error {
 func (m *Manager) processBlock(ctx context.Context, block *domain.Block) {
 
 	if time.Since(block.Timestamp) > 60*time.Second {
+		observability.BlocksSkipped.WithLabelValues("stale").Inc()
 		slog.Warn("Circuit Breaker: Skipping stale block", "block", block.Number, "age", time.Since(block.Timestamp))
 		return
 	}
@@ -102,18 +190,25 @@ func (m *Manager) processBlock(ctx context.Context, block *domain.Block) {
 
 	m.mu.Lock()
 	m.lastBlock = blockNum
+	m.lastBlockAt = time.Now()
 	m.mu.Unlock()
 
+	defer observeSince("process_block", time.Now())
+
 	slog.Info("new block", "height", blockNum)
 
 	slog.Info("new block", "height", blockNum)
 
 	m.notifier.Broadcast(domain.ArbitrageEvent{
-		Type:        "HEARTBEAT",
+		Type:        domain.EventHeartbeat,
 		BlockNumber: blockNum.Uint64(),
 		Timestamp:   time.Now(),
 	})
 
+	limits := m.limits.Load()
+	inst := m.instrument(ctx)
+	tradeSizes := m.tradeSizes(inst, limits.TradeSizes)
+
 	g, ctx := errgroup.WithContext(ctx)
 
 	var ob *domain.OrderBook
@@ -122,16 +217,25 @@ func (m *Manager) processBlock(ctx context.Context, block *domain.Block) {
 
 	g.Go(func() error {
 		var err error
+		start := time.Now()
 		ob, err = m.cex.GetOrderBook(ctx, m.cfg.Symbol)
+		observeSince("cex_fetch", start)
 		if err != nil {
+			m.countInvalidBook(err)
 			return fmt.Errorf("cex fetch failed: %w", err)
 		}
+		if err := ob.Validate(m.cfg.MinBookLevels); err != nil {
+			m.countInvalidBook(err)
+			return fmt.Errorf("cex book rejected: %w", err)
+		}
 		return nil
 	})
 
 	g.Go(func() error {
 		var err error
+		start := time.Now()
 		gasPrice, err = m.dex.GetGasPrice(ctx)
+		observeSince("gas_fetch", start)
 		if err != nil {
 			slog.Warn("failed to fetch gas price, using default", "err", err)
 			gasPrice = big.NewInt(30000000000)
@@ -153,17 +257,21 @@ func (m *Manager) processBlock(ctx context.Context, block *domain.Block) {
 		sellQuote *domain.PriceQuote
 		buyQuote  *domain.PriceQuote
 	}
-	quoteResults := make([]quoteResult, len(m.cfg.TradeSizes))
+	quoteResults := make([]quoteResult, len(tradeSizes))
 
-	for i, size := range m.cfg.TradeSizes {
+	for i, size := range tradeSizes {
 		i, size := i, size
 		g.Go(func() error {
-			sellQ, err := m.dex.GetQuote(ctx, m.cfg.TokenInAddr, m.cfg.TokenOutAddr, size, m.cfg.PoolFee)
+			sellQ, err := timeQuote("sell", func() (*domain.PriceQuote, error) {
+				return m.dex.GetQuote(ctx, m.cfg.TokenInAddr, m.cfg.TokenOutAddr, size, m.cfg.PoolFee)
+			})
 			if err != nil {
 				return fmt.Errorf("dex sell quote failed for size %s: %w", size, err)
 			}
 
-			buyQ, err := m.dex.GetQuoteExactOutput(ctx, m.cfg.TokenOutAddr, m.cfg.TokenInAddr, size, m.cfg.PoolFee)
+			buyQ, err := timeQuote("buy", func() (*domain.PriceQuote, error) {
+				return m.dex.GetQuoteExactOutput(ctx, m.cfg.TokenOutAddr, m.cfg.TokenInAddr, size, m.cfg.PoolFee)
+			})
 			if err != nil {
 				return fmt.Errorf("dex buy quote failed for size %s: %w", size, err)
 			}
@@ -175,6 +283,13 @@ func (m *Manager) processBlock(ctx context.Context, block *domain.Block) {
 
 	if err := g.Wait(); err != nil {
 		slog.Error("data fetch failed", "err", err)
+		m.recordEvaluation(blockNum, nil, err)
+		return
+	}
+
+	skew, skewed := m.bookSkew(block, ob)
+	if skewed && m.cfg.RejectSkewedBooks {
+		m.recordEvaluation(blockNum, nil, fmt.Errorf("cex book %s from block time exceeds %s", skew, m.cfg.MaxBookSkew))
 		return
 	}
 
@@ -182,50 +297,337 @@ func (m *Manager) processBlock(ctx context.Context, block *domain.Block) {
 		slog.Info("Pre-flight check available", "slot0_tick", slot0.Tick)
 	}
 
+	depth := &shortfallPricer{bookPricer: m.bookPricer(ob, inst)}
 	var bestTrade *domain.TradeData
+	var candidates []candidate
+	consider := func(trade *domain.TradeData, ok bool) {
+		if trade == nil {
+			return
+		}
+		trade.BookSkewMs = skew.Milliseconds()
+		trade.StaleBook = skewed
+		if bestTrade == nil || trade.EstimatedProfit > bestTrade.EstimatedProfit {
+			bestTrade = trade
+		}
+		candidates = append(candidates, candidate{trade: trade, profitable: ok})
+	}
 
 	for _, res := range quoteResults {
 		if res.sellQuote != nil {
-			trade := m.checkCexBuyDexSell(blockNum, ob, res.amt, res.sellQuote, gasPrice)
-			if trade != nil {
-				if bestTrade == nil || trade.EstimatedProfit > bestTrade.EstimatedProfit {
-					bestTrade = trade
-				}
-			}
+			consider(m.checkCexBuyDexSell(blockNum, depth, inst, limits.MinProfit, res.amt, res.sellQuote, gasPrice))
 		}
 		if res.buyQuote != nil {
-			trade := m.checkDexBuyCexSell(blockNum, ob, res.amt, res.buyQuote, gasPrice)
-			if trade != nil {
-				if bestTrade == nil || trade.EstimatedProfit > bestTrade.EstimatedProfit {
-					bestTrade = trade
-				}
-			}
+			consider(m.checkDexBuyCexSell(blockNum, depth, inst, limits.MinProfit, res.amt, res.buyQuote, gasPrice))
 		}
 	}
+	m.adjustDepth(depth.short)
+
+	m.recordEvaluation(blockNum, bestTrade, nil)
 
+	now := time.Now()
 	if bestTrade != nil {
 		m.notifier.Broadcast(domain.ArbitrageEvent{
-			Type:        "OPPORTUNITY",
+			Type:        domain.EventOpportunity,
 			BlockNumber: blockNum.Uint64(),
-			Timestamp:   time.Now(),
+			Timestamp:   now,
 			Data:        bestTrade,
 		})
 	}
+
+	for _, e := range m.opportunities.observe(blockNum.Uint64(), now, candidates) {
+		logLifecycle(e)
+		if e.Type == domain.EventOpportunityOpened {
+			m.printReport(e.Data)
+		}
+		m.notifier.Broadcast(e)
+	}
+}
+
+// recordEvaluation stores the outcome of evaluating blockNum unless a later
+// block has already been recorded.
+func (m *Manager) recordEvaluation(blockNum *big.Int, best *domain.TradeData, err error) {
+	e := domain.Evaluation{
+		Symbol:      m.cfg.Symbol,
+		Venue:       m.cfg.Venue,
+		BlockNumber: blockNum.Uint64(),
+		Timestamp:   time.Now(),
+		Best:        best,
+	}
+	if err != nil {
+		e.Err = err.Error()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if prev, ok := m.evaluations[e.Symbol]; ok && prev.BlockNumber > e.BlockNumber {
+		return
+	}
+	m.evaluations[e.Symbol] = e
+}
+
+// Status reports what the Manager watches and the latest block it saw.
+func (m *Manager) Status() domain.EngineStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s := domain.EngineStatus{
+		Venue:         m.cfg.Venue,
+		Symbols:       []string{m.cfg.Symbol},
+		StartedAt:     m.startedAt,
+		LastBlockAt:   m.lastBlockAt,
+		ActiveWorkers: len(m.sem),
+		MaxWorkers:    cap(m.sem),
+		MinProfit:     m.limits.Load().MinProfit,
+	}
+	if m.lastBlock != nil {
+		s.LastBlock = m.lastBlock.Uint64()
+	}
+	return s
+}
+
+// LatestEvaluations returns the most recent evaluation of each pair.
+func (m *Manager) LatestEvaluations() []domain.Evaluation {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	evals := make([]domain.Evaluation, 0, len(m.evaluations))
+	for _, e := range m.evaluations {
+		evals = append(evals, e)
+	}
+	slices.SortFunc(evals, func(a, b domain.Evaluation) int { return strings.Compare(a.Symbol, b.Symbol) })
+	return evals
+}
+
+// bookRejectReasons labels order book validation failures in metrics.
+var bookRejectReasons = []struct {
+	err    error
+	reason string
+}{
+	{domain.ErrMalformedBook, "malformed"},
+	{domain.ErrBookLevel, "non_positive_level"},
+	{domain.ErrBookUnsorted, "unsorted"},
+	{domain.ErrBookCrossed, "crossed"},
+	{domain.ErrBookTooShallow, "too_shallow"},
+}
+
+// countInvalidBook counts err if it reports a malformed or invalid book.
+func (m *Manager) countInvalidBook(err error) {
+	for _, r := range bookRejectReasons {
+		if errors.Is(err, r.err) {
+			observability.CEXInvalidBooks.WithLabelValues(m.cfg.Venue, r.reason).Inc()
+			return
+		}
+	}
+}
+
+// quoteFailureReasons labels DEX quote failures in metrics. Other errors come
+// from the node or the network.
+var quoteFailureReasons = []struct {
+	err    error
+	reason string
+}{
+	{domain.ErrQuoteReverted, "reverted"},
+	{domain.ErrQuoteMalformed, "malformed"},
+	{context.DeadlineExceeded, "timeout"},
+	{context.Canceled, "canceled"},
+}
+
+// timeQuote runs one DEX quote, measuring its latency and counting its
+// failure by reason.
+func timeQuote(side string, quote func() (*domain.PriceQuote, error)) (*domain.PriceQuote, error) {
+	start := time.Now()
+	q, err := quote()
+	observability.DEXQuoteDuration.WithLabelValues(side).Observe(time.Since(start).Seconds())
+	if err == nil {
+		return q, nil
+	}
+	reason := "rpc"
+	for _, r := range quoteFailureReasons {
+		if errors.Is(err, r.err) {
+			reason = r.reason
+			break
+		}
+	}
+	observability.DEXQuoteFailures.WithLabelValues(side, reason).Inc()
+	return nil, err
+}
+
+// observeSince records the time since start as the latency of stage.
+func observeSince(stage string, start time.Time) {
+	observability.StageDuration.WithLabelValues(stage).Observe(time.Since(start).Seconds())
+}
+
+// observeSpread exports the spread of d, labelled with its size as in the
+// opportunity key so that lot rounding does not add series.
+func observeSpread(d *domain.TradeData) {
+	observability.Spread.WithLabelValues(d.Symbol, d.Venue, d.Direction, domain.KeyOf(d).SizeBucket).Set(d.SpreadPct)
+}
+
+// syncClock re-estimates the CEX clock offset now and every
+// ClockSyncInterval. Failures keep the previous estimate.
+func (m *Manager) syncClock(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.ClockSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.clock.SyncClock(ctx); err != nil && ctx.Err() == nil {
+			slog.Warn("CEX clock sync failed, keeping previous offset", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// bookSkew returns how far apart the CEX book and the block were taken, and
+// whether that exceeds MaxBookSkew.
+func (m *Manager) bookSkew(block *domain.Block, ob *domain.OrderBook) (time.Duration, bool) {
+	skew := ob.Time().Sub(block.Timestamp)
+	if skew < 0 {
+		skew = -skew
+	}
+	observability.BookSkew.Observe(skew.Seconds())
+
+	if m.cfg.MaxBookSkew <= 0 || skew <= m.cfg.MaxBookSkew {
+		return skew, false
+	}
+
+	action := "flag"
+	if m.cfg.RejectSkewedBooks {
+		action = "reject"
+	}
+	observability.SkewedBooks.WithLabelValues(action).Inc()
+	slog.Warn("CEX book too far from block time", "block", block.Number, "skew", skew, "max_skew", m.cfg.MaxBookSkew, "action", action)
+	return skew, true
+}
+
+// bookPricer prices fills against one CEX snapshot.
+type bookPricer interface {
+	EffectivePrice(side string, amount decimal.Decimal) (decimal.Decimal, bool)
+}
+
+// bookPricer precomputes the snapshot's depth in fixed point at the
+// instrument's tick and lot precision, falling back to decimal when there is
+// no instrument or the book does not fit.
+func (m *Manager) bookPricer(ob *domain.OrderBook, inst *domain.Instrument) bookPricer {
+	if inst != nil {
+		depth, err := ob.FixedDepth(inst.Scales())
+		if err == nil {
+			return &fixedPricer{book: ob, fixed: depth}
+		}
+		slog.Debug("order book not representable in fixed point, using decimal", "symbol", m.cfg.Symbol, "err", err)
+	}
+	return ob.Depth()
+}
+
+// fixedPricer prices with the fixed-point depth. Amounts finer than the lot
+// size are priced with the decimal depth, built the first time one is.
+type fixedPricer struct {
+	book  *domain.OrderBook
+	fixed *domain.FixedBookDepth
+	depth *domain.BookDepth
+}
+
+func (p *fixedPricer) EffectivePrice(side string, amount decimal.Decimal) (decimal.Decimal, bool) {
+	price, ok, err := p.fixed.EffectivePrice(side, amount)
+	if err == nil {
+		return price, ok
+	}
+	if p.depth == nil {
+		p.depth = p.book.Depth()
+	}
+	return p.depth.EffectivePrice(side, amount)
+}
+
+// shortfallPricer records whether any size ran past the end of the book.
+type shortfallPricer struct {
+	bookPricer
+	short bool
+}
+
+func (p *shortfallPricer) EffectivePrice(side string, amount decimal.Decimal) (decimal.Decimal, bool) {
+	price, ok := p.bookPricer.EffectivePrice(side, amount)
+	if !ok {
+		p.short = true
+	}
+	return price, ok
+}
+
+// adjustDepth asks the CEX adapter for a deeper book after one was too
+// shallow for a trade size, and lets it relax once books are deep enough.
+func (m *Manager) adjustDepth(short bool) {
+	if !short {
+		if m.depth != nil {
+			m.depth.BookFilled()
+		}
+		return
+	}
+	observability.CEXBookShortfalls.WithLabelValues(m.cfg.Venue).Inc()
+	if m.depth != nil && !m.depth.DeepenBook() {
+		slog.Warn("order book too shallow at venue maximum depth", "venue", m.cfg.Venue, "symbol", m.cfg.Symbol)
+	}
+}
+
+// instrument returns the CEX metadata for the configured symbol, or nil if the
+// adapter does not provide it or it cannot currently be fetched.
+func (m *Manager) instrument(ctx context.Context) *domain.Instrument {
+	if m.instruments == nil {
+		return nil
+	}
+	inst, err := m.instruments.GetInstrument(ctx, m.cfg.Symbol)
+	if err != nil {
+		slog.Warn("instrument metadata unavailable, using configured sizes", "symbol", m.cfg.Symbol, "err", err)
+		return nil
+	}
+	return inst
+}
+
+// tradeSizes rounds the configured sizes down to the venue lot size and drops
+// sizes that fall below the venue minimum quantity.
+func (m *Manager) tradeSizes(inst *domain.Instrument, configured []*big.Int) []*big.Int {
+	if inst == nil {
+		return configured
+	}
+
+	sizes := make([]*big.Int, 0, len(configured))
+	for _, size := range configured {
+		qty := m.tokenIn.ToHuman(size)
+		rounded := inst.RoundQty(qty)
+		if !rounded.IsPositive() || (inst.MinQty.IsPositive() && rounded.LessThan(inst.MinQty)) {
+			slog.Warn("trade size below venue minimum, skipping", "size", qty, "min_qty", inst.MinQty)
+			continue
+		}
+		sizes = append(sizes, m.tokenIn.ToRaw(rounded))
+	}
+	return sizes
 }
 
-func (m *Manager) checkCexBuyDexSell(blockNum *big.Int, ob *domain.OrderBook, amountIn *big.Int, pq *domain.PriceQuote, gasPriceWei *big.Int) *domain.TradeData {
-	amtIn := decimal.NewFromBigInt(amountIn, -m.cfg.TokenInDec)
-	amtOut := pq.Price.Mul(decimal.NewFromFloat(1).Div(decimal.New(1, m.cfg.TokenOutDec)))
+// checkCexBuyDexSell evaluates buying amountIn on the CEX and selling it on
+// the DEX. Profit is computed in decimal rather than Fixed on purpose: the
+// effective prices carry 16 decimal places and gas prices in ether 18, so
+// their products exceed MaxFixedScale and would have to be rounded.
+func (m *Manager) checkCexBuyDexSell(blockNum *big.Int, depth bookPricer, inst *domain.Instrument, minProfit decimal.Decimal, amountIn *big.Int, pq *domain.PriceQuote, gasPriceWei *big.Int) (*domain.TradeData, bool) {
+	amtIn := m.tokenIn.ToHuman(amountIn)
+	amtOut := m.tokenOut.ToHuman(pq.Price.BigInt())
 
 	dexPrice := amtOut.Div(amtIn)
 
-	cexPrice, ok := ob.CalculateEffectivePrice("buy", amtIn)
+	cexPrice, ok := depth.EffectivePrice("buy", amtIn)
 	if !ok {
 		slog.Info(fmt.Sprintf("[DEBUG] Block %s: Size %s | CEX Price Unavailable", blockNum, amtIn))
-		return nil
+		return nil, false
 	}
 
-	spread := dexPrice.Sub(cexPrice).Div(cexPrice).Mul(decimal.NewFromFloat(100))
+	if inst != nil {
+		if err := inst.CheckOrder(amtIn, cexPrice); err != nil {
+			slog.Info("size rejected by venue limits", "block", blockNum, "err", err)
+			return nil, false
+		}
+	}
+
+	spread := dexPrice.Sub(cexPrice).Div(cexPrice).Mul(hundred)
 	slog.Info("Market analysis complete",
 		"block", blockNum,
 		"binance_price", cexPrice.StringFixed(2),
@@ -235,12 +637,11 @@ func (m *Manager) checkCexBuyDexSell(blockNum *big.Int, ob *domain.OrderBook, am
 		"size", amtIn.StringFixed(2),
 	)
 
-	cexFee := decimal.NewFromFloat(0.001)
-	cexCost := cexPrice.Mul(amtIn).Mul(decimal.NewFromFloat(1).Add(cexFee))
+	cexCost := cexPrice.Mul(amtIn).Mul(one.Add(m.cfg.CEXFee))
 
 	gasUsed := decimal.NewFromBigInt(pq.GasEstimate, 0)
 
-	gasPriceEth := decimal.NewFromBigInt(gasPriceWei, -18)
+	gasPriceEth := domain.Ether.ToHuman(gasPriceWei)
 	gasCost := gasUsed.Mul(gasPriceEth).Mul(cexPrice)
 
 	netDex := amtOut.Sub(gasCost)
@@ -259,32 +660,44 @@ func (m *Manager) checkCexBuyDexSell(blockNum *big.Int, ob *domain.OrderBook, am
 		EstimatedProfit: profitFloat,
 		GasCost:         gasCostFloat,
 		Symbol:          m.cfg.Symbol,
-		Direction:       "CEX -> DEX",
+		Venue:           m.cfg.Venue,
+		Direction:       domain.DirectionCEXToDEX,
+		Size:            amtIn.InexactFloat64(),
+		PoolFee:         m.cfg.PoolFee,
 	}
 
-	if profit.GreaterThan(m.cfg.MinProfit) {
-		observability.ArbitrageOpsFound.Inc()
-		p, _ := profit.Float64()
-		observability.ArbitrageProfit.WithLabelValues(m.cfg.Symbol).Add(p)
+	observeSpread(tradeData)
 
-		m.printReport(amtIn, cexPrice, dexPrice, profit, "CEX -> DEX")
+	ok = profit.GreaterThan(minProfit)
+	if ok {
+		observability.ArbitrageOpsFound.Inc()
+		observability.ArbitrageProfit.WithLabelValues(m.cfg.Symbol).Add(profitFloat)
 	}
 
-	return tradeData
+	return tradeData, ok
 }
 
-func (m *Manager) checkDexBuyCexSell(blockNum *big.Int, ob *domain.OrderBook, amountOut *big.Int, pq *domain.PriceQuote, gasPriceWei *big.Int) *domain.TradeData {
-	ethAmount := decimal.NewFromBigInt(amountOut, -m.cfg.TokenInDec)
-	usdcIn := pq.Price.Mul(decimal.NewFromFloat(1).Div(decimal.New(1, m.cfg.TokenOutDec)))
+// checkDexBuyCexSell evaluates buying amountOut on the DEX and selling it on
+// the CEX, in decimal for the same reason as checkCexBuyDexSell.
+func (m *Manager) checkDexBuyCexSell(blockNum *big.Int, depth bookPricer, inst *domain.Instrument, minProfit decimal.Decimal, amountOut *big.Int, pq *domain.PriceQuote, gasPriceWei *big.Int) (*domain.TradeData, bool) {
+	ethAmount := m.tokenIn.ToHuman(amountOut)
+	usdcIn := m.tokenOut.ToHuman(pq.Price.BigInt())
 
 	dexPrice := usdcIn.Div(ethAmount)
 
-	cexPrice, ok := ob.CalculateEffectivePrice("sell", ethAmount)
+	cexPrice, ok := depth.EffectivePrice("sell", ethAmount)
 	if !ok {
-		return nil
+		return nil, false
+	}
+
+	if inst != nil {
+		if err := inst.CheckOrder(ethAmount, cexPrice); err != nil {
+			slog.Info("size rejected by venue limits", "block", blockNum, "err", err)
+			return nil, false
+		}
 	}
 
-	spread := cexPrice.Sub(dexPrice).Div(dexPrice).Mul(decimal.NewFromFloat(100))
+	spread := cexPrice.Sub(dexPrice).Div(dexPrice).Mul(hundred)
 
 	slog.Info("Market analysis complete (DEX->CEX)",
 		"block", blockNum,
@@ -295,11 +708,10 @@ func (m *Manager) checkDexBuyCexSell(blockNum *big.Int, ob *domain.OrderBook, am
 		"size", ethAmount.StringFixed(2),
 	)
 
-	cexFee := decimal.NewFromFloat(0.001)
-	cexRevenue := cexPrice.Mul(ethAmount).Mul(decimal.NewFromFloat(1).Sub(cexFee))
+	cexRevenue := cexPrice.Mul(ethAmount).Mul(one.Sub(m.cfg.CEXFee))
 
 	gasUsed := decimal.NewFromBigInt(pq.GasEstimate, 0)
-	gasPriceEth := decimal.NewFromBigInt(gasPriceWei, -18)
+	gasPriceEth := domain.Ether.ToHuman(gasPriceWei)
 	gasCost := gasUsed.Mul(gasPriceEth).Mul(cexPrice)
 
 	profit := cexRevenue.Sub(usdcIn).Sub(gasCost)
@@ -317,36 +729,31 @@ func (m *Manager) checkDexBuyCexSell(blockNum *big.Int, ob *domain.OrderBook, am
 		EstimatedProfit: profitFloat,
 		GasCost:         gasCostFloat,
 		Symbol:          m.cfg.Symbol,
-		Direction:       "DEX -> CEX",
+		Venue:           m.cfg.Venue,
+		Direction:       domain.DirectionDEXToCEX,
+		Size:            ethAmount.InexactFloat64(),
+		PoolFee:         m.cfg.PoolFee,
 	}
 
-	if profit.GreaterThan(m.cfg.MinProfit) {
-		observability.ArbitrageOpsFound.Inc()
-		p, _ := profit.Float64()
-		observability.ArbitrageProfit.WithLabelValues(m.cfg.Symbol).Add(p)
+	observeSpread(tradeData)
 
-		m.printReport(ethAmount, cexPrice, dexPrice, profit, "DEX -> CEX")
+	ok = profit.GreaterThan(minProfit)
+	if ok {
+		observability.ArbitrageOpsFound.Inc()
+		observability.ArbitrageProfit.WithLabelValues(m.cfg.Symbol).Add(profitFloat)
 	}
 
-	return tradeData
+	return tradeData, ok
 }
 
-func (m *Manager) printReport(amount, cexPrice, dexPrice, profit decimal.Decimal, direction string) {
-	slog.Info("arb opportunity",
-		"ts", time.Now().UTC(),
-		"dir", direction,
-		"size", amount.StringFixed(2),
-		"cex", cexPrice.StringFixed(2),
-		"dex", dexPrice.StringFixed(2),
-		"profit", profit.StringFixed(2),
-	)
-
+// printReport prints a newly opened opportunity.
+func (m *Manager) printReport(d *domain.TradeData) {
 	fmt.Println(">>> ARB FOUND <<<")
 	fmt.Printf("Time: %s\n", time.Now().UTC().Format(time.RFC3339))
-	fmt.Printf("Dir:  %s\n", direction)
-	fmt.Printf("Size: %s ETH\n", amount.StringFixed(2))
-	fmt.Printf("CEX:  $%s\n", cexPrice.StringFixed(2))
-	fmt.Printf("DEX:  $%s\n", dexPrice.StringFixed(2))
-	fmt.Printf("Est. Profit: $%s\n", profit.StringFixed(2))
+	fmt.Printf("Dir:  %s\n", d.Direction)
+	fmt.Printf("Size: %.2f ETH\n", d.Size)
+	fmt.Printf("CEX:  $%.2f\n", d.CexPrice)
+	fmt.Printf("DEX:  $%.2f\n", d.DexPrice)
+	fmt.Printf("Est. Profit: $%.2f\n", d.EstimatedProfit)
 	fmt.Println("---------------------")
 }
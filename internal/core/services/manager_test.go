@@ -2,14 +2,19 @@ package services_test
 
 import (
 	"context"
+	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/ports/mocks"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/services"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/observability"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -29,6 +34,7 @@ func TestManager_ProcessBlock(t *testing.T) {
 		PoolFee:       3000,
 		TradeSizes:    []*big.Int{big.NewInt(1000000000000000000)}, // 1 ETH
 		MinProfit:     decimal.NewFromFloat(10.0),
+		CEXFee:        decimal.NewFromFloat(0.001),
 		MaxWorkers:    1,
 		CacheDuration: time.Second,
 	}
@@ -64,12 +70,16 @@ func TestManager_ProcessBlock(t *testing.T) {
 	mockDEX.On("GetQuoteExactOutput", mock.Anything, "0xUSDC", "0xWETH", amountIn, int64(3000)).Return(pq, nil) // Just reuse pq for simplicity, though technically incorrect for buy
 	mockDEX.On("GetGasPrice", mock.Anything).Return(big.NewInt(30000000000), nil)                               // 30 gwei
 	mockDEX.On("GetSlot0", mock.Anything, "0xWETH", "0xUSDC", int64(3000)).Return(&domain.Slot0{SqrtPriceX96: big.NewInt(0), Tick: big.NewInt(0)}, nil)
-	var capturedEvent domain.ArbitrageEvent
+	var capturedEvent, openedEvent domain.ArbitrageEvent
 	mockNotifier.On("Broadcast", mock.MatchedBy(func(e domain.ArbitrageEvent) bool {
 		if e.Type == "OPPORTUNITY" {
 			capturedEvent = e
 			return true
 		}
+		if e.Type == domain.EventOpportunityOpened && e.Data.Direction == domain.DirectionCEXToDEX {
+			openedEvent = e
+			return true
+		}
 		return true // Accept other events (HEARTBEAT) but don't capture them as the one we want to verify
 	})).Return()
 
@@ -132,4 +142,373 @@ func TestManager_ProcessBlock(t *testing.T) {
 	if diff := capturedEvent.Data.EstimatedProfit - expectedProfit; diff > 0.01 || diff < -0.01 {
 		t.Errorf("Expected profit ~%f, got %f", expectedProfit, capturedEvent.Data.EstimatedProfit)
 	}
+
+	if openedEvent.Opportunity == nil || openedEvent.Opportunity.Key != "ETHUSDC//CEX -> DEX/3000/1" || openedEvent.Opportunity.OpenedBlock != 100 {
+		t.Errorf("Expected the opportunity to open at block 100, got %+v", openedEvent.Opportunity)
+	}
+
+	status := manager.Status()
+	if status.LastBlock != 100 || status.StartedAt.IsZero() || status.MaxWorkers != 1 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	evals := manager.LatestEvaluations()
+	if len(evals) != 1 || evals[0].BlockNumber != 100 || evals[0].Best == nil || evals[0].Err != "" {
+		t.Fatalf("Expected an evaluation of block 100 with a best trade, got %+v", evals)
+	}
+	if evals[0].Best.EstimatedProfit != capturedEvent.Data.EstimatedProfit {
+		t.Errorf("Expected the broadcast trade as best, got %+v", evals[0].Best)
+	}
+}
+
+func TestManager_ProcessBlock_RoundsSizesToLot(t *testing.T) {
+	mockCEX := new(mocks.MockInstrumentExchange)
+	mockDEX := new(mocks.MockPriceProvider)
+	mockListener := new(mocks.MockBlockchainListener)
+	mockNotifier := new(mocks.MockNotificationService)
+
+	cfg := services.Config{
+		Symbol:       "ETHUSDC",
+		TokenInAddr:  "0xWETH",
+		TokenOutAddr: "0xUSDC",
+		TokenInDec:   18,
+		TokenOutDec:  6,
+		PoolFee:      3000,
+		TradeSizes: []*big.Int{
+			big.NewInt(1200000000000000000), // 1.2 ETH, rounds to 1.0
+			big.NewInt(300000000000000000),  // 0.3 ETH, below lot size
+		},
+		MinProfit:     decimal.NewFromFloat(1000.0),
+		CEXFee:        decimal.NewFromFloat(0.001),
+		MaxWorkers:    1,
+		CacheDuration: time.Second,
+	}
+
+	manager := services.NewManager(cfg, mockCEX, mockDEX, mockListener, mockNotifier)
+
+	inst := &domain.Instrument{
+		Venue:       "test",
+		Symbol:      "ETHUSDC",
+		VenueSymbol: "ETHUSDC",
+		Base:        "ETH",
+		Quote:       "USDC",
+		TickSize:    decimal.RequireFromString("0.01"),
+		LotSize:     decimal.RequireFromString("0.5"),
+	}
+	ob := &domain.OrderBook{
+		Timestamp: time.Now(),
+		Asks:      []domain.PriceLevel{{Price: decimal.NewFromFloat(2000.0), Amount: decimal.NewFromFloat(10.0)}},
+		Bids:      []domain.PriceLevel{{Price: decimal.NewFromFloat(1999.0), Amount: decimal.NewFromFloat(10.0)}},
+	}
+	pq := &domain.PriceQuote{
+		Price:       decimal.NewFromInt(2000000000),
+		GasEstimate: big.NewInt(100000),
+		Timestamp:   time.Now(),
+	}
+	rounded := big.NewInt(1000000000000000000) // 1 ETH
+
+	mockCEX.On("GetInstrument", mock.Anything, "ETHUSDC").Return(inst, nil)
+	mockCEX.On("GetOrderBook", mock.Anything, "ETHUSDC").Return(ob, nil)
+	mockDEX.On("GetQuote", mock.Anything, "0xWETH", "0xUSDC", rounded, int64(3000)).Return(pq, nil)
+	mockDEX.On("GetQuoteExactOutput", mock.Anything, "0xUSDC", "0xWETH", rounded, int64(3000)).Return(pq, nil)
+	mockDEX.On("GetGasPrice", mock.Anything).Return(big.NewInt(30000000000), nil)
+	mockDEX.On("GetSlot0", mock.Anything, "0xWETH", "0xUSDC", int64(3000)).Return(&domain.Slot0{SqrtPriceX96: big.NewInt(0), Tick: big.NewInt(0)}, nil)
+	mockNotifier.On("Broadcast", mock.Anything).Return()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	blockChan := make(chan *domain.Block)
+	errChan := make(chan error)
+	mockListener.On("SubscribeNewHeads", ctx).Return((<-chan *domain.Block)(blockChan), (<-chan error)(errChan), nil)
+
+	go func() {
+		_ = manager.Start(ctx)
+	}()
+
+	blockChan <- &domain.Block{Number: big.NewInt(100), Timestamp: time.Now()}
+	time.Sleep(100 * time.Millisecond)
+
+	mockCEX.AssertExpectations(t)
+	mockDEX.AssertExpectations(t)
+	mockDEX.AssertNumberOfCalls(t, "GetQuote", 1)
+	mockDEX.AssertNumberOfCalls(t, "GetQuoteExactOutput", 1)
+}
+
+func TestManager_ProcessBlock_SkewedBook(t *testing.T) {
+	for _, reject := range []bool{true, false} {
+		mockCEX := new(mocks.MockExchangeAdapter)
+		mockDEX := new(mocks.MockPriceProvider)
+		mockListener := new(mocks.MockBlockchainListener)
+		mockNotifier := new(mocks.MockNotificationService)
+
+		cfg := services.Config{
+			Symbol:            "ETHUSDC",
+			TokenInAddr:       "0xWETH",
+			TokenOutAddr:      "0xUSDC",
+			TokenInDec:        18,
+			TokenOutDec:       6,
+			PoolFee:           3000,
+			TradeSizes:        []*big.Int{big.NewInt(1000000000000000000)},
+			MinProfit:         decimal.NewFromFloat(10.0),
+			CEXFee:            decimal.NewFromFloat(0.001),
+			MaxWorkers:        1,
+			CacheDuration:     time.Second,
+			MaxBookSkew:       5 * time.Second,
+			RejectSkewedBooks: reject,
+		}
+
+		manager := services.NewManager(cfg, mockCEX, mockDEX, mockListener, mockNotifier)
+
+		// The venue stamped the book 30s before the block.
+		ob := &domain.OrderBook{
+			Timestamp:    time.Now(),
+			ExchangeTime: time.Now().Add(-30 * time.Second),
+			Asks:         []domain.PriceLevel{{Price: decimal.NewFromFloat(2000.0), Amount: decimal.NewFromFloat(10.0)}},
+		}
+		pq := &domain.PriceQuote{
+			Price:       decimal.NewFromInt(2050000000),
+			GasEstimate: big.NewInt(100000),
+			Timestamp:   time.Now(),
+		}
+
+		mockCEX.On("GetOrderBook", mock.Anything, "ETHUSDC").Return(ob, nil)
+		mockDEX.On("GetQuote", mock.Anything, "0xWETH", "0xUSDC", cfg.TradeSizes[0], int64(3000)).Return(pq, nil)
+		mockDEX.On("GetQuoteExactOutput", mock.Anything, "0xUSDC", "0xWETH", cfg.TradeSizes[0], int64(3000)).Return(pq, nil)
+		mockDEX.On("GetGasPrice", mock.Anything).Return(big.NewInt(30000000000), nil)
+		mockDEX.On("GetSlot0", mock.Anything, "0xWETH", "0xUSDC", int64(3000)).Return(&domain.Slot0{SqrtPriceX96: big.NewInt(0), Tick: big.NewInt(0)}, nil)
+
+		opportunities := make(chan domain.ArbitrageEvent, 1)
+		mockNotifier.On("Broadcast", mock.Anything).Run(func(args mock.Arguments) {
+			if e := args.Get(0).(domain.ArbitrageEvent); e.Type == "OPPORTUNITY" {
+				opportunities <- e
+			}
+		}).Return()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		blockChan := make(chan *domain.Block)
+		errChan := make(chan error)
+		mockListener.On("SubscribeNewHeads", ctx).Return((<-chan *domain.Block)(blockChan), (<-chan error)(errChan), nil)
+
+		go func() {
+			_ = manager.Start(ctx)
+		}()
+
+		blockChan <- &domain.Block{Number: big.NewInt(100), Timestamp: time.Now()}
+
+		var opportunity *domain.ArbitrageEvent
+		select {
+		case e := <-opportunities:
+			opportunity = &e
+		case <-time.After(200 * time.Millisecond):
+		}
+		cancel()
+
+		if reject {
+			if opportunity != nil {
+				t.Errorf("Expected skewed book to be rejected, got %+v", opportunity.Data)
+			}
+			if evals := manager.LatestEvaluations(); len(evals) != 1 || !strings.Contains(evals[0].Err, "exceeds") {
+				t.Errorf("Expected the rejection to be recorded, got %+v", evals)
+			}
+			continue
+		}
+		if opportunity == nil || opportunity.Data == nil {
+			t.Fatal("Expected flagged OPPORTUNITY event")
+		}
+		if !opportunity.Data.StaleBook {
+			t.Error("Expected opportunity to be flagged as stale")
+		}
+		if opportunity.Data.BookSkewMs < 29000 {
+			t.Errorf("Expected book skew of ~30s, got %dms", opportunity.Data.BookSkewMs)
+		}
+	}
+}
+
+func TestManager_ProcessBlock_DeepensShallowBook(t *testing.T) {
+	mockCEX := new(mocks.MockDepthExchange)
+	mockDEX := new(mocks.MockPriceProvider)
+	mockListener := new(mocks.MockBlockchainListener)
+	mockNotifier := new(mocks.MockNotificationService)
+
+	cfg := services.Config{
+		Symbol:        "ETHUSDC",
+		Venue:         "test",
+		TokenInAddr:   "0xWETH",
+		TokenOutAddr:  "0xUSDC",
+		TokenInDec:    18,
+		TokenOutDec:   6,
+		PoolFee:       3000,
+		TradeSizes:    []*big.Int{big.NewInt(1000000000000000000), new(big.Int).Mul(big.NewInt(10), big.NewInt(1000000000000000000))},
+		MinProfit:     decimal.NewFromFloat(10.0),
+		CEXFee:        decimal.NewFromFloat(0.001),
+		MaxWorkers:    1,
+		CacheDuration: time.Second,
+	}
+
+	manager := services.NewManager(cfg, mockCEX, mockDEX, mockListener, mockNotifier)
+
+	// 2 ETH per side: enough for 1 ETH, not for 10.
+	ob := &domain.OrderBook{
+		Timestamp: time.Now(),
+		Asks:      []domain.PriceLevel{{Price: decimal.NewFromFloat(2000.0), Amount: decimal.NewFromFloat(2.0)}},
+		Bids:      []domain.PriceLevel{{Price: decimal.NewFromFloat(1999.0), Amount: decimal.NewFromFloat(2.0)}},
+	}
+	pq := &domain.PriceQuote{
+		Price:       decimal.NewFromInt(2000000000),
+		GasEstimate: big.NewInt(100000),
+		Timestamp:   time.Now(),
+	}
+
+	deepened := make(chan struct{}, 1)
+	mockCEX.On("GetOrderBook", mock.Anything, "ETHUSDC").Return(ob, nil)
+	mockCEX.On("DeepenBook").Run(func(mock.Arguments) { deepened <- struct{}{} }).Return(true)
+	mockDEX.On("GetQuote", mock.Anything, "0xWETH", "0xUSDC", mock.Anything, int64(3000)).Return(pq, nil)
+	mockDEX.On("GetQuoteExactOutput", mock.Anything, "0xUSDC", "0xWETH", mock.Anything, int64(3000)).Return(pq, nil)
+	mockDEX.On("GetGasPrice", mock.Anything).Return(big.NewInt(30000000000), nil)
+	mockDEX.On("GetSlot0", mock.Anything, "0xWETH", "0xUSDC", int64(3000)).Return(&domain.Slot0{SqrtPriceX96: big.NewInt(0), Tick: big.NewInt(0)}, nil)
+	mockNotifier.On("Broadcast", mock.Anything).Return()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	blockChan := make(chan *domain.Block)
+	errChan := make(chan error)
+	mockListener.On("SubscribeNewHeads", ctx).Return((<-chan *domain.Block)(blockChan), (<-chan error)(errChan), nil)
+
+	go func() {
+		_ = manager.Start(ctx)
+	}()
+
+	blockChan <- &domain.Block{Number: big.NewInt(100), Timestamp: time.Now()}
+
+	select {
+	case <-deepened:
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("Expected a deeper book to be requested after a 10 ETH shortfall")
+	}
+	mockCEX.AssertNotCalled(t, "BookFilled")
+}
+
+func TestManager_ProcessBlock_Metrics(t *testing.T) {
+	mockCEX := new(mocks.MockExchangeAdapter)
+	mockDEX := new(mocks.MockPriceProvider)
+	mockListener := new(mocks.MockBlockchainListener)
+	mockNotifier := new(mocks.MockNotificationService)
+
+	cfg := services.Config{
+		Symbol:        "ETHUSDC",
+		TokenInAddr:   "0xWETH",
+		TokenOutAddr:  "0xUSDC",
+		TokenInDec:    18,
+		TokenOutDec:   6,
+		PoolFee:       3000,
+		TradeSizes:    []*big.Int{big.NewInt(1000000000000000000)},
+		MinProfit:     decimal.NewFromFloat(10.0),
+		CEXFee:        decimal.NewFromFloat(0.001),
+		MaxWorkers:    1,
+		CacheDuration: time.Second,
+	}
+	manager := services.NewManager(cfg, mockCEX, mockDEX, mockListener, mockNotifier)
+
+	ob := &domain.OrderBook{
+		Timestamp: time.Now(),
+		Asks:      []domain.PriceLevel{{Price: decimal.NewFromFloat(2000.0), Amount: decimal.NewFromFloat(10.0)}},
+	}
+	pq := &domain.PriceQuote{Price: decimal.NewFromInt(2050000000), GasEstimate: big.NewInt(100000), Timestamp: time.Now()}
+	reverted := fmt.Errorf("%w: execution reverted", domain.ErrQuoteReverted)
+
+	mockCEX.On("GetOrderBook", mock.Anything, "ETHUSDC").Return(ob, nil)
+	mockDEX.On("GetQuote", mock.Anything, "0xWETH", "0xUSDC", cfg.TradeSizes[0], int64(3000)).Return(nil, reverted)
+	mockDEX.On("GetQuoteExactOutput", mock.Anything, "0xUSDC", "0xWETH", cfg.TradeSizes[0], int64(3000)).Return(pq, nil).Maybe()
+	mockDEX.On("GetGasPrice", mock.Anything).Return(big.NewInt(30000000000), nil)
+	mockDEX.On("GetSlot0", mock.Anything, "0xWETH", "0xUSDC", int64(3000)).Return(&domain.Slot0{SqrtPriceX96: big.NewInt(0), Tick: big.NewInt(0)}, nil)
+	mockNotifier.On("Broadcast", mock.Anything).Return()
+
+	failures := observability.DEXQuoteFailures.WithLabelValues("sell", "reverted")
+	stale := observability.BlocksSkipped.WithLabelValues("stale")
+	failuresBefore, staleBefore := testutil.ToFloat64(failures), testutil.ToFloat64(stale)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	blockChan := make(chan *domain.Block)
+	errChan := make(chan error)
+	mockListener.On("SubscribeNewHeads", ctx).Return((<-chan *domain.Block)(blockChan), (<-chan error)(errChan), nil)
+
+	go func() {
+		_ = manager.Start(ctx)
+	}()
+
+	blockChan <- &domain.Block{Number: big.NewInt(100), Timestamp: time.Now()}
+	assert.Eventually(t, func() bool { return len(manager.LatestEvaluations()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, failuresBefore+1, testutil.ToFloat64(failures))
+
+	blockChan <- &domain.Block{Number: big.NewInt(101), Timestamp: time.Now().Add(-2 * time.Minute)}
+	assert.Eventually(t, func() bool { return testutil.ToFloat64(stale) == staleBefore+1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestManager_ProcessBlock_AcceptsQuietStreamedBook(t *testing.T) {
+	mockCEX := new(mocks.MockExchangeAdapter)
+	mockDEX := new(mocks.MockPriceProvider)
+	mockListener := new(mocks.MockBlockchainListener)
+	mockNotifier := new(mocks.MockNotificationService)
+
+	cfg := services.Config{
+		Symbol:            "ETHUSDC",
+		TokenInAddr:       "0xWETH",
+		TokenOutAddr:      "0xUSDC",
+		TokenInDec:        18,
+		TokenOutDec:       6,
+		PoolFee:           3000,
+		TradeSizes:        []*big.Int{big.NewInt(1000000000000000000)},
+		MinProfit:         decimal.NewFromFloat(10.0),
+		CEXFee:            decimal.NewFromFloat(0.001),
+		MaxWorkers:        1,
+		CacheDuration:     time.Second,
+		MaxBookSkew:       5 * time.Second,
+		RejectSkewedBooks: true,
+	}
+	manager := services.NewManager(cfg, mockCEX, mockDEX, mockListener, mockNotifier)
+
+	// A synced stream whose book last changed 30s ago but whose connection
+	// was confirmed alive just now.
+	ob := &domain.OrderBook{
+		Timestamp:    time.Now().Add(-30 * time.Second),
+		ExchangeTime: time.Now().Add(-30 * time.Second),
+		LiveAt:       time.Now(),
+		Asks:         []domain.PriceLevel{{Price: decimal.NewFromFloat(2000.0), Amount: decimal.NewFromFloat(10.0)}},
+	}
+	pq := &domain.PriceQuote{Price: decimal.NewFromInt(2050000000), GasEstimate: big.NewInt(100000), Timestamp: time.Now()}
+
+	mockCEX.On("GetOrderBook", mock.Anything, "ETHUSDC").Return(ob, nil)
+	mockDEX.On("GetQuote", mock.Anything, "0xWETH", "0xUSDC", cfg.TradeSizes[0], int64(3000)).Return(pq, nil)
+	mockDEX.On("GetQuoteExactOutput", mock.Anything, "0xUSDC", "0xWETH", cfg.TradeSizes[0], int64(3000)).Return(pq, nil)
+	mockDEX.On("GetGasPrice", mock.Anything).Return(big.NewInt(30000000000), nil)
+	mockDEX.On("GetSlot0", mock.Anything, "0xWETH", "0xUSDC", int64(3000)).Return(&domain.Slot0{SqrtPriceX96: big.NewInt(0), Tick: big.NewInt(0)}, nil)
+
+	opportunities := make(chan domain.ArbitrageEvent, 1)
+	mockNotifier.On("Broadcast", mock.Anything).Run(func(args mock.Arguments) {
+		if e := args.Get(0).(domain.ArbitrageEvent); e.Type == domain.EventOpportunity {
+			opportunities <- e
+		}
+	}).Return()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	blockChan := make(chan *domain.Block)
+	errChan := make(chan error)
+	mockListener.On("SubscribeNewHeads", ctx).Return((<-chan *domain.Block)(blockChan), (<-chan error)(errChan), nil)
+
+	go func() {
+		_ = manager.Start(ctx)
+	}()
+	blockChan <- &domain.Block{Number: big.NewInt(100), Timestamp: time.Now()}
+
+	select {
+	case e := <-opportunities:
+		assert.False(t, e.Data.StaleBook)
+		assert.Less(t, e.Data.BookSkewMs, int64(5000))
+	case <-time.After(time.Second):
+		t.Fatal("Expected the quiet but live book to be evaluated")
+	}
 }
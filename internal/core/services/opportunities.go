@@ -0,0 +1,234 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/observability"
+)
+
+// opportunityTracker follows opportunities across blocks and turns the
+// profitable trades of each evaluated block into lifecycle events.
+type opportunityTracker struct {
+	// materialChange is the relative profit change since the last event that
+	// reports an open opportunity again.
+	materialChange float64
+
+	mu        sync.Mutex
+	lastBlock uint64
+	open      map[domain.OpportunityKey]*openOpportunity
+}
+
+type openOpportunity struct {
+	info  domain.Opportunity
+	trade *domain.TradeData
+	// notified is the profit last reported, which changes are measured from.
+	notified float64
+	// seen and profitSum give the average profit.
+	seen      int
+	profitSum float64
+}
+
+// candidate is a trade evaluated in a block, and whether it cleared the
+// minimum profit.
+type candidate struct {
+	trade      *domain.TradeData
+	profitable bool
+}
+
+func newOpportunityTracker(materialChange float64) *opportunityTracker {
+	return &opportunityTracker{
+		materialChange: materialChange,
+		open:           make(map[domain.OpportunityKey]*openOpportunity),
+	}
+}
+
+// observe records the trades evaluated in block and returns the events they
+// cause: opportunities opened, changed materially or no longer profitable.
+// Blocks no later than the last observed are ignored, as workers may finish
+// out of order.
+func (t *opportunityTracker) observe(block uint64, at time.Time, trades []candidate) []domain.ArbitrageEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if block <= t.lastBlock {
+		return nil
+	}
+	t.lastBlock = block
+
+	// The best trade per key, and the best one that was not profitable, which
+	// tells what closed an opportunity.
+	found := make(map[domain.OpportunityKey]*domain.TradeData, len(trades))
+	missed := make(map[domain.OpportunityKey]*domain.TradeData)
+	for _, c := range trades {
+		best := missed
+		if c.profitable {
+			best = found
+		}
+		key := domain.KeyOf(c.trade)
+		if prev, ok := best[key]; !ok || c.trade.EstimatedProfit > prev.EstimatedProfit {
+			best[key] = c.trade
+		}
+	}
+
+	var events []domain.ArbitrageEvent
+	emit := func(eventType string, o *openOpportunity) {
+		info := o.info
+		events = append(events, domain.ArbitrageEvent{
+			Type:        eventType,
+			BlockNumber: block,
+			Timestamp:   at,
+			Data:        o.trade,
+			Opportunity: &info,
+		})
+	}
+
+	for _, key := range sortedKeys(t.open) {
+		if _, ok := found[key]; ok {
+			continue
+		}
+		o := t.open[key]
+		o.close(block, at, missed[key])
+		emit(domain.EventOpportunityClosed, o)
+		delete(t.open, key)
+	}
+
+	for _, key := range sortedKeys(found) {
+		trade := found[key]
+		o, ok := t.open[key]
+		if !ok {
+			o = &openOpportunity{
+				info: domain.Opportunity{
+					ID:          fmt.Sprintf("%s@%d", key, block),
+					Key:         key.String(),
+					OpenedBlock: block,
+					OpenedAt:    at,
+				},
+			}
+			t.open[key] = o
+		}
+		o.add(block, trade)
+
+		switch {
+		case !ok:
+			o.notified = trade.EstimatedProfit
+			emit(domain.EventOpportunityOpened, o)
+		case t.material(o.notified, trade.EstimatedProfit):
+			o.notified = trade.EstimatedProfit
+			emit(domain.EventOpportunityUpdated, o)
+		}
+	}
+	return events
+}
+
+// add records trade as the opportunity's latest, seen in block.
+func (o *openOpportunity) add(block uint64, trade *domain.TradeData) {
+	o.trade = trade
+	o.seen++
+	o.profitSum += trade.EstimatedProfit
+
+	info := &o.info
+	info.LastBlock = block
+	info.Blocks = block - info.OpenedBlock + 1
+	info.AvgProfit = o.profitSum / float64(o.seen)
+	if o.seen == 1 || trade.EstimatedProfit > info.PeakProfit {
+		info.PeakProfit = trade.EstimatedProfit
+		info.PeakBlock = block
+	}
+}
+
+// close completes the opportunity's statistics. closing is the same trade
+// evaluated in the closing block, or nil if it could not be priced.
+func (o *openOpportunity) close(block uint64, at time.Time, closing *domain.TradeData) {
+	info := &o.info
+	info.ClosedBlock = block
+	info.ClosedAt = at
+	info.ClosedBy = closedBy(o.trade, closing)
+
+	final, finalBlock := o.trade.EstimatedProfit, info.LastBlock
+	if closing != nil {
+		final, finalBlock = closing.EstimatedProfit, block
+	}
+	if finalBlock > info.PeakBlock {
+		info.DecayPerBlock = (info.PeakProfit - final) / float64(finalBlock-info.PeakBlock)
+	}
+}
+
+// closedBy attributes the fall in profit from last to closing to the price
+// that moved against the trade most.
+func closedBy(last, closing *domain.TradeData) string {
+	if closing == nil {
+		return domain.ClosedByUnknown
+	}
+	// Selling on the DEX loses when its price falls, buying when it rises;
+	// the CEX leg is the other way round.
+	sign := 1.0
+	if last.Direction == domain.DirectionDEXToCEX {
+		sign = -1
+	}
+	contributions := []struct {
+		cause  string
+		change float64
+	}{
+		{domain.ClosedByDEX, sign * closing.Size * (closing.DexPrice - last.DexPrice)},
+		{domain.ClosedByCEX, -sign * closing.Size * (closing.CexPrice - last.CexPrice)},
+		{domain.ClosedByGas, -(closing.GasCost - last.GasCost)},
+	}
+
+	cause, worst := domain.ClosedByUnknown, 0.0
+	for _, c := range contributions {
+		if c.change < worst {
+			cause, worst = c.cause, c.change
+		}
+	}
+	return cause
+}
+
+// material reports whether profit has moved far enough from the profit last
+// reported to report again.
+func (t *opportunityTracker) material(notified, profit float64) bool {
+	if profit == notified {
+		return false
+	}
+	return math.Abs(profit-notified) >= t.materialChange*math.Abs(notified)
+}
+
+// sortedKeys returns the keys of m in a stable order, so that events come
+// out in one.
+func sortedKeys[V any](m map[domain.OpportunityKey]V) []domain.OpportunityKey {
+	keys := slices.Collect(maps.Keys(m))
+	slices.SortFunc(keys, func(a, b domain.OpportunityKey) int { return strings.Compare(a.String(), b.String()) })
+	return keys
+}
+
+// logLifecycle logs an opportunity lifecycle event. Openings are counted once
+// per opportunity and its peak profit is added when it closes, so neither
+// depends on how many blocks or sizes it spanned.
+func logLifecycle(e domain.ArbitrageEvent) {
+	o := e.Opportunity
+	args := []any{"id", o.ID, "block", e.BlockNumber, "profit", e.Data.EstimatedProfit, "spread_pct", e.Data.SpreadPct}
+	switch e.Type {
+	case domain.EventOpportunityOpened:
+		slog.Info("Opportunity opened", args...)
+		observability.OpportunitiesOpened.Inc()
+	case domain.EventOpportunityUpdated:
+		slog.Info("Opportunity changed", args...)
+	case domain.EventOpportunityClosed:
+		slog.Info("Opportunity closed", append(args,
+			"blocks", o.Blocks,
+			"peak_profit", o.PeakProfit,
+			"avg_profit", o.AvgProfit,
+			"decay_per_block", o.DecayPerBlock,
+			"closed_by", o.ClosedBy,
+		)...)
+		observability.OpportunityBlocks.WithLabelValues(e.Data.Symbol, e.Data.Venue).Observe(float64(o.Blocks))
+		observability.OpportunitiesClosed.WithLabelValues(e.Data.Symbol, e.Data.Venue, o.ClosedBy).Inc()
+		observability.OpportunityPeakProfit.WithLabelValues(e.Data.Symbol).Add(o.PeakProfit)
+	}
+}
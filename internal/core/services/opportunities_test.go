@@ -0,0 +1,133 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/observability"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func trade(direction string, size, profit float64) *domain.TradeData {
+	return &domain.TradeData{Symbol: "ETHUSDC", Venue: "binance", Direction: direction, PoolFee: 3000, Size: size, EstimatedProfit: profit, CexPrice: 2000, DexPrice: 2050, GasCost: 5}
+}
+
+func profitable(trades ...*domain.TradeData) []candidate {
+	var out []candidate
+	for _, t := range trades {
+		out = append(out, candidate{trade: t, profitable: true})
+	}
+	return out
+}
+
+func types(events []domain.ArbitrageEvent) []string {
+	var out []string
+	for _, e := range events {
+		out = append(out, e.Type+" "+e.Opportunity.Key)
+	}
+	return out
+}
+
+func TestOpportunityTracker_Lifecycle(t *testing.T) {
+	tr := newOpportunityTracker(0.2)
+	now := time.Now()
+	const key1 = "ETHUSDC/binance/CEX -> DEX/3000/1"
+	const key10 = "ETHUSDC/binance/CEX -> DEX/3000/10"
+
+	events := tr.observe(10, now, profitable(trade(domain.DirectionCEXToDEX, 1, 50), trade(domain.DirectionCEXToDEX, 10, 100)))
+	assert.Equal(t, []string{"OPPORTUNITY_OPENED " + key1, "OPPORTUNITY_OPENED " + key10}, types(events))
+	opened := events[0].Opportunity
+
+	// Small changes are not reported; lot rounding stays in the same bucket.
+	events = tr.observe(11, now, profitable(trade(domain.DirectionCEXToDEX, 1.0004, 55), trade(domain.DirectionCEXToDEX, 10, 110)))
+	assert.Empty(t, events)
+
+	// A block finishing after a later one is ignored.
+	assert.Empty(t, tr.observe(11, now, nil))
+
+	events = tr.observe(12, now, profitable(trade(domain.DirectionCEXToDEX, 1, 61)))
+	assert.Equal(t, []string{"OPPORTUNITY_CLOSED " + key10, "OPPORTUNITY_UPDATED " + key1}, types(events))
+	assert.Equal(t, uint64(11), events[0].Opportunity.LastBlock)
+	assert.Equal(t, 110.0, events[0].Data.EstimatedProfit, "a close carries the last trade seen")
+	assert.Equal(t, opened.ID, events[1].Opportunity.ID)
+	assert.Equal(t, uint64(10), events[1].Opportunity.OpenedBlock)
+
+	// Changes are measured from the last report, not the opening.
+	assert.Empty(t, tr.observe(13, now, profitable(trade(domain.DirectionCEXToDEX, 1, 70))))
+
+	events = tr.observe(14, now, nil)
+	require.Len(t, events, 1)
+	assert.Equal(t, domain.EventOpportunityClosed, events[0].Type)
+	assert.Equal(t, uint64(14), events[0].BlockNumber)
+	assert.Equal(t, uint64(13), events[0].Opportunity.LastBlock)
+
+	events = tr.observe(15, now, profitable(trade(domain.DirectionCEXToDEX, 1, 70)))
+	require.Len(t, events, 1)
+	assert.NotEqual(t, opened.ID, events[0].Opportunity.ID, "a reopening is a new opportunity")
+}
+
+func TestOpportunityTracker_Statistics(t *testing.T) {
+	tr := newOpportunityTracker(0)
+	start := time.Unix(1_700_000_000, 0)
+	block := func(n uint64, trades ...candidate) []domain.ArbitrageEvent {
+		return tr.observe(n, start.Add(time.Duration(n-100)*12*time.Second), trades)
+	}
+
+	block(100, profitable(trade(domain.DirectionCEXToDEX, 1, 40))...)
+	block(101, profitable(trade(domain.DirectionCEXToDEX, 1, 80))...)
+	// Block 102 was skipped; the opportunity still lasted through it.
+	block(103, profitable(trade(domain.DirectionCEXToDEX, 1, 60))...)
+
+	// The pool price fell by 30 while the exchange barely moved.
+	closing := trade(domain.DirectionCEXToDEX, 1, 20)
+	closing.DexPrice, closing.CexPrice = 2020, 2001
+	events := block(104, candidate{trade: closing})
+	require.Len(t, events, 1)
+
+	o := events[0].Opportunity
+	assert.Equal(t, uint64(4), o.Blocks)
+	assert.Equal(t, 80.0, o.PeakProfit)
+	assert.Equal(t, uint64(101), o.PeakBlock)
+	assert.Equal(t, 60.0, o.AvgProfit)
+	assert.Equal(t, uint64(104), o.ClosedBlock)
+	assert.Equal(t, 48*time.Second, o.Duration())
+	assert.Equal(t, 20.0, o.DecayPerBlock, "from 80 at block 101 to 20 at block 104")
+	assert.Equal(t, domain.ClosedByDEX, o.ClosedBy)
+}
+
+func TestClosedBy(t *testing.T) {
+	last := trade(domain.DirectionDEXToCEX, 2, 50)
+	moved := func(cex, dex, gas float64) *domain.TradeData {
+		d := *last
+		d.CexPrice, d.DexPrice, d.GasCost = cex, dex, gas
+		return &d
+	}
+
+	assert.Equal(t, domain.ClosedByDEX, closedBy(last, moved(2000, 2060, 5)), "buying on the pool got dearer")
+	assert.Equal(t, domain.ClosedByCEX, closedBy(last, moved(1990, 2050, 5)), "selling on the exchange got cheaper")
+	assert.Equal(t, domain.ClosedByGas, closedBy(last, moved(2000, 2050, 30)))
+	assert.Equal(t, domain.ClosedByUnknown, closedBy(last, moved(2010, 2040, 5)), "every price moved in its favour")
+	assert.Equal(t, domain.ClosedByUnknown, closedBy(last, nil))
+}
+
+func TestLogLifecycle_CountsOpportunitiesOnce(t *testing.T) {
+	tr := newOpportunityTracker(0)
+	now := time.Now()
+	profit := observability.OpportunityPeakProfit.WithLabelValues("ETHUSDC")
+	openedBefore, profitBefore := testutil.ToFloat64(observability.OpportunitiesOpened), testutil.ToFloat64(profit)
+
+	var events []domain.ArbitrageEvent
+	events = append(events, tr.observe(10, now, profitable(trade(domain.DirectionCEXToDEX, 1, 40), trade(domain.DirectionCEXToDEX, 10, 60)))...)
+	events = append(events, tr.observe(11, now, profitable(trade(domain.DirectionCEXToDEX, 1, 80), trade(domain.DirectionCEXToDEX, 10, 60)))...)
+	events = append(events, tr.observe(12, now, profitable(trade(domain.DirectionCEXToDEX, 1, 50)))...)
+	events = append(events, tr.observe(13, now, nil)...)
+	for _, e := range events {
+		logLifecycle(e)
+	}
+
+	assert.Equal(t, openedBefore+2, testutil.ToFloat64(observability.OpportunitiesOpened), "one per opportunity, not per block or size")
+	assert.Equal(t, profitBefore+140, testutil.ToFloat64(profit), "the peak of each closed opportunity")
+}
@@ -2,21 +2,30 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
-	"math/big"
 	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/analytics"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/auth"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/binance"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/blockchain"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/bybit"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/coinbase"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/ethereum"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/grpcapi"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/kraken"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/notify"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/okx"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/adapters/websocket"
+	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/domain"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/ports"
 	"github.com/KVasquesMoviaUTN/cex-dex-arbitrage-challenge/internal/core/services"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -24,37 +33,94 @@ import (
 
 type Config struct {
 	services.Config
-	EthNodeWS     string
-	EthNodeHTTP   string
-	MetricsPort   string
-	CEXProvider   string
-	BinanceAPIURL string
+	EthNodeWS      string
+	EthNodeHTTP    string
+	Port           string
+	MetricsPort    string
+	GRPCPort       string
+	CEXProvider    string
+	BookDepth      int
+	BinanceAPIURL  string
+	BinanceWSURL   string
+	KrakenWSURL    string
+	OKXWSURL       string
+	CoinbaseAPIURL string
+	// CoinbaseUSDCAlias lets USDC symbols resolve to Coinbase USD books.
+	CoinbaseUSDCAlias bool
+	BybitAPIURL       string
+	TokenCachePath    string
+	HistoryPath       string
+	Auth              auth.Config
+	Notifiers         []notify.Config
 }
 
+const tokenResolveTimeout = 15 * time.Second
+
 type Engine struct {
 	cfg      Config
+	cex      ports.ExchangeAdapter
 	manager  *services.Manager
 	notifier *websocket.Server
+	api      *grpcapi.Server
+	sinks    []*notify.Sink
+	history  *analytics.Store
 }
 
 func New(cfg Config) (*Engine, error) {
-	cex := createCEXAdapter(cfg.CEXProvider, cfg.BinanceAPIURL)
+	cex := createCEXAdapter(cfg)
+	if d, ok := cex.(ports.DepthAdjuster); ok && cfg.BookDepth > 0 {
+		d.SetBookDepth(cfg.BookDepth)
+	}
 	slog.Info("Using CEX provider", "provider", cfg.CEXProvider)
 
-	dex, err := ethereum.NewAdapter(cfg.EthNodeHTTP)
+	dex, err := ethereum.NewAdapter(cfg.EthNodeHTTP, cfg.TokenCachePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create DEX adapter: %w", err)
 	}
 
+	if tp, ok := dex.(ports.TokenProvider); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), tokenResolveTimeout)
+		err := resolveTokens(ctx, tp, &cfg.Config)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	listener := blockchain.NewListener(cfg.EthNodeWS)
-	notifier := websocket.NewServer()
+	authn := auth.New(cfg.Auth)
+	if !authn.Enabled() {
+		slog.Warn("API authentication is disabled; set server.auth to require tokens")
+	}
+	notifier := websocket.NewServer(authn)
+
+	history, err := analytics.NewStore(cfg.HistoryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open opportunity history: %w", err)
+	}
+
+	broadcast := notify.Fanout{notifier, history}
+	sinks := make([]*notify.Sink, 0, len(cfg.Notifiers))
+	for _, nc := range cfg.Notifiers {
+		sink, err := notify.NewSink(nc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create notifier: %w", err)
+		}
+		slog.Info("Using notifier", "name", sink.Name(), "kind", nc.Kind)
+		sinks = append(sinks, sink)
+		broadcast = append(broadcast, sink)
+	}
 
-	manager := services.NewManager(cfg.Config, cex, dex, listener, notifier)
+	manager := services.NewManager(cfg.Config, cex, dex, listener, broadcast)
 
 	return &Engine{
 		cfg:      cfg,
+		cex:      cex,
 		manager:  manager,
 		notifier: notifier,
+		api:      grpcapi.NewServer(notifier, manager, history, authn),
+		sinks:    sinks,
+		history:  history,
 	}, nil
 }
 
@@ -70,16 +136,21 @@ func (e *Engine) Run(ctx context.Context) error {
 	}()
 
 	go func() {
-		port := os.Getenv("PORT")
-		if port == "" {
-			port = "8080"
-		}
-		e.notifier.Start(":" + port)
+		e.notifier.Start(":" + e.cfg.Port)
 	}()
 
+	if e.cfg.GRPCPort != "" {
+		go e.api.Start(":" + e.cfg.GRPCPort)
+		defer e.api.Stop()
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	for _, sink := range e.sinks {
+		go sink.Run(ctx)
+	}
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
@@ -89,6 +160,20 @@ func (e *Engine) Run(ctx context.Context) error {
 		cancel()
 	}()
 
+	defer func() {
+		_ = e.history.Close()
+	}()
+
+	if c, ok := e.cex.(io.Closer); ok {
+		defer func() {
+			_ = c.Close()
+		}()
+	}
+
+	if err := e.checkInstrument(ctx); err != nil {
+		return err
+	}
+
 	slog.Info("Starting arbitrage bot")
 	if err := e.manager.Start(ctx); err != nil {
 		return fmt.Errorf("manager failed: %w", err)
@@ -97,32 +182,106 @@ func (e *Engine) Run(ctx context.Context) error {
 	return nil
 }
 
-func createCEXAdapter(provider, binanceURL string) ports.ExchangeAdapter {
-	switch strings.ToLower(provider) {
+// SetLimits applies new thresholds to the running Manager.
+func (e *Engine) SetLimits(l services.Limits) {
+	e.manager.SetLimits(l)
+}
+
+// checkInstrument fails if the configured symbol is not listed on the CEX.
+// Metadata that cannot be fetched is only logged, as the Manager retries it.
+func (e *Engine) checkInstrument(ctx context.Context) error {
+	ip, ok := e.cex.(ports.InstrumentProvider)
+	if !ok {
+		return nil
+	}
+
+	inst, err := ip.GetInstrument(ctx, e.cfg.Symbol)
+	if errors.Is(err, domain.ErrInstrumentNotListed) {
+		return fmt.Errorf("invalid SYMBOL: %w", err)
+	}
+	if err != nil {
+		slog.Warn("Instrument metadata unavailable", "symbol", e.cfg.Symbol, "error", err)
+		return nil
+	}
+
+	if inst.QuoteAlias != "" {
+		slog.Warn("Instrument is quoted in another asset than the symbol names",
+			"symbol", inst.Symbol,
+			"venue_symbol", inst.VenueSymbol,
+			"quote", inst.Quote,
+			"alias", inst.QuoteAlias,
+		)
+	}
+	slog.Info("Resolved instrument",
+		"symbol", inst.Symbol,
+		"venue_symbol", inst.VenueSymbol,
+		"tick_size", inst.TickSize,
+		"lot_size", inst.LotSize,
+		"min_notional", inst.MinNotional,
+	)
+	return nil
+}
+
+// resolveTokens validates the configured token decimals against the chain and
+// fills in any left unset. Configured values are kept if the node cannot be
+// reached, so the bot can still start from an explicit configuration.
+func resolveTokens(ctx context.Context, tokens ports.TokenProvider, cfg *services.Config) error {
+	for _, t := range []struct {
+		name     string
+		address  string
+		decimals *int32
+	}{
+		{"TOKEN_IN", cfg.TokenInAddr, &cfg.TokenInDec},
+		{"TOKEN_OUT", cfg.TokenOutAddr, &cfg.TokenOutDec},
+	} {
+		token, err := tokens.GetToken(ctx, t.address)
+		if err != nil {
+			if *t.decimals > 0 {
+				slog.Warn("Failed to resolve token, using configured decimals", "token", t.name, "address", t.address, "error", err)
+				continue
+			}
+			return fmt.Errorf("failed to resolve %s and %s_DEC is not set: %w", t.name, t.name, err)
+		}
+
+		if *t.decimals != 0 && *t.decimals != token.Decimals {
+			return fmt.Errorf("%w: %s %s (%s) has %d decimals, %s_DEC is %d",
+				domain.ErrTokenMismatch, t.name, token.Address, token.Symbol, token.Decimals, t.name, *t.decimals)
+		}
+		*t.decimals = token.Decimals
+
+		slog.Info("Resolved token",
+			"token", t.name,
+			"address", token.Address,
+			"symbol", token.Symbol,
+			"name", token.Name,
+			"decimals", token.Decimals,
+		)
+	}
+	return nil
+}
+
+func createCEXAdapter(cfg Config) ports.ExchangeAdapter {
+	switch strings.ToLower(cfg.CEXProvider) {
 	case "kraken":
-		return kraken.NewAdapter()
+		if cfg.KrakenWSURL != "" {
+			return kraken.NewStreamAdapter(cfg.KrakenWSURL, kraken.BaseURL)
+		}
+		return kraken.NewAdapter(kraken.BaseURL)
 	case "okx":
-		return okx.NewAdapter()
+		if cfg.OKXWSURL != "" {
+			return okx.NewStreamAdapter(cfg.OKXWSURL, okx.BaseURL)
+		}
+		return okx.NewAdapter(okx.BaseURL)
+	case "coinbase":
+		return coinbase.NewAdapter(cfg.CoinbaseAPIURL, cfg.CoinbaseUSDCAlias)
+	case "bybit":
+		return bybit.NewAdapter(cfg.BybitAPIURL)
 	case "binance":
 		fallthrough
 	default:
-		return binance.NewAdapter(binanceURL)
-	}
-}
-
-func ParseTradeSizes(s string) ([]*big.Int, error) {
-	parts := strings.Split(s, ",")
-	sizes := make([]*big.Int, 0, len(parts))
-	for _, p := range parts {
-		p = strings.TrimSpace(p)
-		if p == "" {
-			continue
-		}
-		size, ok := new(big.Int).SetString(p, 10)
-		if !ok {
-			return sizes, fmt.Errorf("invalid trade size: %s", p)
+		if cfg.BinanceWSURL != "" {
+			return binance.NewStreamAdapter(cfg.BinanceWSURL, cfg.BinanceAPIURL)
 		}
-		sizes = append(sizes, size)
+		return binance.NewAdapter(cfg.BinanceAPIURL)
 	}
-	return sizes, nil
 }
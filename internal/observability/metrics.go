@@ -21,8 +21,171 @@ var (
 		Help: "The total profit from arbitrage opportunities",
 	}, []string{"asset"})
 
+	OpportunitiesOpened = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "arbitrage_opportunities_opened_total",
+		Help: "The total number of arbitrage opportunities opened",
+	})
+
+	OpportunityPeakProfit = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arbitrage_opportunity_peak_profit_total",
+		Help: "The total peak estimated profit of closed arbitrage opportunities",
+	}, []string{"asset"})
+
 	ActiveWorkers = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "arbitrage_active_workers",
 		Help: "The number of active workers processing blocks",
 	})
+
+	BookSkew = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "arbitrage_book_skew_seconds",
+		Help:    "Time between the CEX order book and the block it was evaluated against",
+		Buckets: []float64{0.25, 0.5, 1, 2, 4, 8, 15, 30, 60},
+	})
+
+	SkewedBooks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arbitrage_skewed_books_total",
+		Help: "Evaluations whose CEX book was further from the block than the allowed skew, by action taken",
+	}, []string{"action"})
+
+	BlocksSkipped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arbitrage_blocks_skipped_total",
+		Help: "Blocks not evaluated, by reason: workers_busy (worker pool full) or stale",
+	}, []string{"reason"})
+
+	StageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "arbitrage_stage_duration_seconds",
+		Help:    "Latency of block processing stages: cex_fetch, gas_fetch and the whole process_block",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"stage"})
+
+	DEXQuoteDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "arbitrage_dex_quote_duration_seconds",
+		Help:    "Latency of each DEX quote, by side: sell (exact input) or buy (exact output)",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"side"})
+
+	DEXQuoteFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arbitrage_dex_quote_failures_total",
+		Help: "Failed DEX quotes by side and reason: reverted, malformed, timeout, canceled or rpc",
+	}, []string{"side", "reason"})
+
+	Spread = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "arbitrage_spread_pct",
+		Help: "Spread between the CEX and DEX prices in the latest block, in percent, by pair, venue, direction and trade size",
+	}, []string{"symbol", "venue", "direction", "size"})
+)
+
+var (
+	ListenerReconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "eth_listener_reconnects_total",
+		Help: "Block listener reconnections by reason: dial_failed, subscribe_failed, subscription_error or heartbeat_timeout",
+	}, []string{"reason"})
+
+	ListenerBackfilled = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "eth_listener_backfilled_blocks_total",
+		Help: "Blocks missed while disconnected and fetched after reconnecting",
+	})
+
+	ListenerBackfillErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "eth_listener_backfill_errors_total",
+		Help: "Missed blocks that could not be fetched after reconnecting",
+	})
+)
+
+var (
+	CEXRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cex_http_requests_total",
+		Help: "HTTP requests sent to CEX REST APIs by venue and status code",
+	}, []string{"venue", "status"})
+
+	CEXRequestErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cex_http_errors_total",
+		Help: "Failed CEX REST requests by venue and reason",
+	}, []string{"venue", "reason"})
+
+	CEXRequestRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cex_http_retries_total",
+		Help: "CEX REST requests retried after a transient failure",
+	}, []string{"venue"})
+
+	CEXRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cex_http_request_duration_seconds",
+		Help:    "Latency of CEX REST requests",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"venue"})
+
+	CEXBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cex_circuit_breaker_state",
+		Help: "Circuit breaker state per venue: 0 closed, 1 half-open, 2 open",
+	}, []string{"venue"})
+
+	CEXWeightRemaining = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cex_request_weight_remaining",
+		Help: "Request weight left in the venue's current rate-limit window",
+	}, []string{"venue"})
+
+	CEXWeightThrottled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cex_request_weight_throttled_total",
+		Help: "CEX REST requests delayed until the next window to stay within the weight budget",
+	}, []string{"venue"})
+
+	CEXInvalidBooks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cex_invalid_order_books_total",
+		Help: "CEX order books rejected before evaluation, by venue and reason",
+	}, []string{"venue", "reason"})
+
+	CEXClockOffset = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cex_clock_offset_seconds",
+		Help: "Estimated venue clock minus local clock",
+	}, []string{"venue"})
+
+	CEXBookDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cex_order_book_depth",
+		Help: "Order book levels per side requested from the venue",
+	}, []string{"venue"})
+
+	CEXBookDepthChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cex_order_book_depth_changes_total",
+		Help: "Adjustments of the requested order book depth, by direction (deepen or relax)",
+	}, []string{"venue", "direction"})
+
+	CEXBookShortfalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cex_order_book_shortfalls_total",
+		Help: "Evaluations where the CEX book was too shallow to fill a trade size",
+	}, []string{"venue"})
+
+	WSClients = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ws_clients",
+		Help: "Connected WebSocket clients",
+	})
+
+	WSEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ws_slow_client_evictions_total",
+		Help: "WebSocket clients disconnected for not keeping up with the event stream",
+	})
+)
+
+var (
+	NotifierDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notifier_deliveries_total",
+		Help: "Events handled by outbound notifier sinks by result: sent, failed after retries, or dropped with the queue full",
+	}, []string{"sink", "result"})
+
+	NotifierRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notifier_retries_total",
+		Help: "Notifier deliveries retried after a transient failure",
+	}, []string{"sink"})
+)
+
+var (
+	OpportunityBlocks = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "arbitrage_opportunity_duration_blocks",
+		Help:    "Blocks each opportunity lasted, from opening to the last block it was seen in",
+		Buckets: []float64{1, 2, 3, 5, 10, 20, 50, 100},
+	}, []string{"symbol", "venue"})
+
+	OpportunitiesClosed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arbitrage_opportunities_closed_total",
+		Help: "Opportunities closed, by the price that moved against them: dex (on-chain), cex, gas or unknown",
+	}, []string{"symbol", "venue", "closed_by"})
 )
@@ -58,6 +58,13 @@ func TestEndToEndArbitrageFlow(t *testing.T) {
 			_ = json.NewEncoder(w).Encode(response)
 			return
 		}
+		if r.URL.Path == "/exchangeInfo" {
+			_, _ = w.Write([]byte(`{"symbols": [{"symbol": "ETHUSDC", "status": "TRADING", "baseAsset": "ETH", "quoteAsset": "USDC", "filters": [
+				{"filterType": "PRICE_FILTER", "tickSize": "0.01"},
+				{"filterType": "LOT_SIZE", "stepSize": "0.0001", "minQty": "0.0001"}
+			]}]}`))
+			return
+		}
 		w.WriteHeader(http.StatusNotFound)
 	}))
 	defer binanceServer.Close()
@@ -99,7 +106,7 @@ func TestEndToEndArbitrageFlow(t *testing.T) {
 	defer ethServer.Close()
 
 	cexAdapter := binance.NewAdapter(binanceServer.URL)
-	dexAdapter, err := ethereum.NewAdapter(ethServer.URL)
+	dexAdapter, err := ethereum.NewAdapter(ethServer.URL, "")
 	assert.NoError(t, err)
 
 	mockListener := &MockBlockchainListener{}
@@ -118,6 +125,7 @@ func TestEndToEndArbitrageFlow(t *testing.T) {
 		PoolFee:       3000,
 		TradeSizes:    []*big.Int{big.NewInt(1000000000000000000)},
 		MinProfit:     decimal.NewFromFloat(1.0),
+		CEXFee:        decimal.NewFromFloat(0.001),
 		MaxWorkers:    1,
 		CacheDuration: time.Second,
 	}